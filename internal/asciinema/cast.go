@@ -0,0 +1,149 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/shopspring/decimal"
+)
+
+func ReadV2(r io.Reader) (*V2Header, []V2Event, error) {
+	dec := json.NewDecoder(r)
+
+	h := new(V2Header)
+	if err := dec.Decode(h); err != nil {
+		return nil, nil, err
+	}
+
+	events := make([]V2Event, 0)
+	for dec.More() {
+		var e V2Event
+		if err := dec.Decode(&e); err != nil {
+			return nil, nil, err
+		}
+
+		events = append(events, e)
+	}
+
+	return h, events, nil
+}
+
+func WriteV2(w io.Writer, h *V2Header, events []V2Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(h); err != nil {
+		return err
+	}
+
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadV1 reads asciicast v1 as v2, with frames turned into output events at their
+// time since the beginning.
+func ReadV1(r io.Reader) (*V2Header, []V2Event, error) {
+	var v1 V1
+	if err := json.NewDecoder(r).Decode(&v1); err != nil {
+		return nil, nil, err
+	}
+
+	if v1.Version != 1 {
+		return nil, nil, fmt.Errorf("invalid header version: %v", v1.Version)
+	}
+
+	h := &V2Header{
+		Version:  2,
+		Width:    v1.Width,
+		Height:   v1.Height,
+		Duration: v1.Duration,
+		Command:  v1.Command,
+		Title:    v1.Title,
+		Env:      v1.Env,
+	}
+
+	events := make([]V2Event, 0, len(v1.Stdout))
+	var at decimal.Decimal
+	for _, f := range v1.Stdout {
+		at = at.Add(decimal.NewFromFloat(f.Delay))
+		events = append(events, V2Event{Time: NewTime(at), Code: "o", Data: f.Data})
+	}
+
+	return h, events, nil
+}
+
+// ReadV3 reads asciicast v3, with event intervals turned into the time since the
+// beginning as in v2.
+func ReadV3(r io.Reader) (*V3Header, []V2Event, error) {
+	dec := json.NewDecoder(r)
+
+	h := new(V3Header)
+	if err := dec.Decode(h); err != nil {
+		return nil, nil, err
+	}
+
+	events := make([]V2Event, 0)
+	var at decimal.Decimal
+	for dec.More() {
+		var e V2Event
+		if err := dec.Decode(&e); err != nil {
+			return nil, nil, err
+		}
+
+		at = at.Add(e.Time.Decimal())
+		e.Time = NewTime(at)
+
+		events = append(events, e)
+	}
+
+	return h, events, nil
+}
+
+// WriteV3 writes asciicast v3 from events timed since the beginning as in v2.
+func WriteV3(w io.Writer, h *V3Header, events []V2Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(h); err != nil {
+		return err
+	}
+
+	var prev decimal.Decimal
+	for _, e := range events {
+		at := e.Time.Decimal()
+		e.Time = NewTime(at.Sub(prev))
+		prev = at
+
+		if err := enc.Encode(&e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
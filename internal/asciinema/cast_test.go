@@ -0,0 +1,323 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadV2(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		header *V2Header
+		events []V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "hello"]
+[1, "r", "100x30"]
+`,
+			},
+			expected: &expected{
+				header: &V2Header{Version: 2, Width: 80, Height: 24},
+				events: []V2Event{
+					{Time: "0.5", Code: "o", Data: "hello"},
+					{Time: "1", Code: "r", Data: "100x30"},
+				},
+			},
+		},
+		{
+			name: "happy path: no events",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24}
+`,
+			},
+			expected: &expected{
+				header: &V2Header{Version: 2, Width: 80, Height: 24},
+				events: []V2Event{},
+			},
+		},
+		{
+			name: "edge path: invalid event",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24}
+["0.5", "o", "hello"]
+`,
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			h, events, err := ReadV2(strings.NewReader(tt.args.data))
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, h)
+				assert.Nil(t, events)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteV2(t *testing.T) {
+	type args struct {
+		header *V2Header
+		events []V2Event
+	}
+
+	type expected struct {
+		data string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				header: &V2Header{Version: 2, Width: 80, Height: 24, Title: "<Demo>"},
+				events: []V2Event{
+					{Time: "0.5", Code: "o", Data: "<b>"},
+					{Time: "1", Code: "r", Data: "100x30"},
+				},
+			},
+			expected: &expected{
+				data: `{"version":2,"width":80,"height":24,"title":"<Demo>"}
+[0.5,"o","<b>"]
+[1,"r","100x30"]
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			buf := new(bytes.Buffer)
+
+			// Act
+			err := WriteV2(buf, tt.args.header, tt.args.events)
+
+			// Assert
+			assert.Equal(t, tt.expected.data, buf.String())
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestReadV1(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		header *V2Header
+		events []V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				data: `{"version": 1, "width": 80, "height": 24, "duration": 1.5, "title": "Demo", "stdout": [[0.5, "hello"], [1, "\r\n"]]}`,
+			},
+			expected: &expected{
+				header: &V2Header{Version: 2, Width: 80, Height: 24, Duration: 1.5, Title: "Demo"},
+				events: []V2Event{
+					{Time: "0.5", Code: "o", Data: "hello"},
+					{Time: "1.5", Code: "o", Data: "\r\n"},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid version",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24}`,
+			},
+			expected: nil,
+		},
+		{
+			name: "edge path: invalid frame",
+			args: &args{
+				data: `{"version": 1, "width": 80, "height": 24, "stdout": [["0.5", "hello"]]}`,
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			h, events, err := ReadV1(strings.NewReader(tt.args.data))
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, h)
+				assert.Nil(t, events)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestReadV3(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		header *V3Header
+		events []V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				data: `{"version": 3, "term": {"cols": 80, "rows": 24, "type": "xterm-256color"}}
+[0.5, "o", "hello"]
+[0.25, "r", "100x30"]
+[1, "x", "0"]
+`,
+			},
+			expected: &expected{
+				header: &V3Header{Version: 3, Term: V3HeaderTerm{Cols: 80, Rows: 24, Type: "xterm-256color"}},
+				events: []V2Event{
+					{Time: "0.5", Code: "o", Data: "hello"},
+					{Time: "0.75", Code: "r", Data: "100x30"},
+					{Time: "1.75", Code: "x", Data: "0"},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid event",
+			args: &args{
+				data: `{"version": 3, "term": {"cols": 80, "rows": 24}}
+["0.5", "o", "hello"]
+`,
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			h, events, err := ReadV3(strings.NewReader(tt.args.data))
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, h)
+				assert.Nil(t, events)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWriteV3(t *testing.T) {
+	type args struct {
+		header *V3Header
+		events []V2Event
+	}
+
+	type expected struct {
+		data string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				header: &V3Header{Version: 3, Term: V3HeaderTerm{Cols: 80, Rows: 24}, Title: "<Demo>"},
+				events: []V2Event{
+					{Time: "0.5", Code: "o", Data: "<b>"},
+					{Time: "1.2", Code: "r", Data: "100x30"},
+				},
+			},
+			expected: &expected{
+				data: `{"version":3,"term":{"cols":80,"rows":24},"title":"<Demo>"}
+[0.5,"o","<b>"]
+[0.7,"r","100x30"]
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			buf := new(bytes.Buffer)
+
+			// Act
+			err := WriteV3(buf, tt.args.header, tt.args.events)
+
+			// Assert
+			assert.Equal(t, tt.expected.data, buf.String())
+			assert.NoError(t, err)
+		})
+	}
+}
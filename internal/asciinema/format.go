@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
+
+// Format is a recording format told apart by DetectFormat.
+type Format int
+
+const (
+	FormatUnknown Format = iota
+	FormatV1
+	FormatV2
+	FormatDeltaV2
+	FormatV3
+	FormatTTYRec
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatV1:
+		return "asciicast v1"
+	case FormatV2:
+		return "asciicast v2"
+	case FormatDeltaV2:
+		return "Δ-asciicast v2"
+	case FormatV3:
+		return "asciicast v3"
+	case FormatTTYRec:
+		return "ttyrec"
+	default:
+		return "unknown format"
+	}
+}
+
+var (
+	// DurationTolerance is how far the header duration may be off the last event
+	// time, since recorders round it.
+	DurationTolerance = decimal.New(1, -3)
+)
+
+// DetectFormat guesses the format of a recording from its content, and reports
+// whether it is sure.
+//
+// A Δ-asciicast v2 is recognized by the deltascii header key. Without it, times
+// going backwards or a header duration matching the sum of its times rather than
+// the last one tell a Δ file, and otherwise a v2 file is reported as v2, but
+// sure only if the duration matches the last time.
+func DetectFormat(b []byte) (f Format, sure bool) {
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) == 0 {
+		return FormatUnknown, false
+	}
+
+	if trimmed[0] != '{' {
+		if isTTYRec(b) {
+			return FormatTTYRec, true
+		}
+		return FormatUnknown, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+
+	var header struct {
+		Version  any             `json:"version"`
+		Duration float64         `json:"duration"`
+		Delta    json.RawMessage `json:"deltascii"`
+	}
+	if err := dec.Decode(&header); err != nil {
+		return FormatUnknown, false
+	}
+
+	switch header.Version {
+	case 1.0:
+		return FormatV1, true
+	case 2.0:
+		if header.Delta != nil {
+			return FormatDeltaV2, true
+		}
+	case 3.0:
+		return FormatV3, true
+	default:
+		return FormatUnknown, false
+	}
+
+	var last, sum decimal.Decimal
+	for dec.More() {
+		var e V2Event
+		if err := dec.Decode(&e); err != nil {
+			return FormatV2, false
+		}
+
+		at := e.Time.Decimal()
+		if at.LessThan(last) {
+			return FormatDeltaV2, true
+		}
+		last = at
+		sum = sum.Add(at)
+	}
+
+	if header.Duration > 0 {
+		d := decimal.NewFromFloat(header.Duration)
+		matchLast := d.Sub(last).Abs().LessThanOrEqual(DurationTolerance)
+		matchSum := d.Sub(sum).Abs().LessThanOrEqual(DurationTolerance)
+
+		switch {
+		case matchLast && !matchSum:
+			return FormatV2, true
+		case matchSum && !matchLast:
+			return FormatDeltaV2, true
+		}
+	}
+
+	return FormatV2, false
+}
+
+// isTTYRec reports whether b is a sequence of ttyrec frames, each a little-endian
+// header of seconds, microseconds and length followed by the data.
+func isTTYRec(b []byte) bool {
+	if len(b) < 12 {
+		return false
+	}
+
+	for len(b) > 0 {
+		if len(b) < 12 {
+			return false
+		}
+
+		usec := binary.LittleEndian.Uint32(b[4:8])
+		n := binary.LittleEndian.Uint32(b[8:12])
+		if usec >= 1_000_000 || uint64(n) > uint64(len(b)-12) {
+			return false
+		}
+
+		b = b[12+n:]
+	}
+
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectFormat(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	type expected struct {
+		format Format
+		sure   bool
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: v1",
+			args: &args{
+				data: []byte(`{"version": 1, "width": 80, "height": 24, "stdout": [[0.5, "hello"]]}`),
+			},
+			expected: &expected{
+				format: FormatV1,
+				sure:   true,
+			},
+		},
+		{
+			name: "happy path: v2 by duration",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "duration": 2}
+[0.5, "o", "a"]
+[1, "o", "b"]
+[2, "o", "c"]
+`),
+			},
+			expected: &expected{
+				format: FormatV2,
+				sure:   true,
+			},
+		},
+		{
+			name: "happy path: v2 without duration",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[1, "o", "b"]
+`),
+			},
+			expected: &expected{
+				format: FormatV2,
+				sure:   false,
+			},
+		},
+		{
+			name: "happy path: Δ by header",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "deltascii": {"version": 1, "duration": 1}}
+[0.5, "o", "a"]
+[0.5, "o", "b"]
+`),
+			},
+			expected: &expected{
+				format: FormatDeltaV2,
+				sure:   true,
+			},
+		},
+		{
+			name: "happy path: Δ by times going backwards",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[0.25, "o", "b"]
+`),
+			},
+			expected: &expected{
+				format: FormatDeltaV2,
+				sure:   true,
+			},
+		},
+		{
+			name: "happy path: Δ by duration",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "duration": 3.5}
+[0.5, "o", "a"]
+[1, "o", "b"]
+[2, "o", "c"]
+`),
+			},
+			expected: &expected{
+				format: FormatDeltaV2,
+				sure:   true,
+			},
+		},
+		{
+			name: "happy path: v3",
+			args: &args{
+				data: []byte(`{"version": 3, "term": {"cols": 80, "rows": 24}}
+[0.5, "o", "a"]
+`),
+			},
+			expected: &expected{
+				format: FormatV3,
+				sure:   true,
+			},
+		},
+		{
+			name: "happy path: ttyrec",
+			args: &args{
+				data: []byte{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 'h', 'i'},
+			},
+			expected: &expected{
+				format: FormatTTYRec,
+				sure:   true,
+			},
+		},
+		{
+			name: "edge path: empty",
+			args: &args{
+				data: []byte("\n"),
+			},
+			expected: &expected{
+				format: FormatUnknown,
+				sure:   false,
+			},
+		},
+		{
+			name: "edge path: text",
+			args: &args{
+				data: []byte("Script started on 2017-09-03 19:35:15+00:00\nhello world\n"),
+			},
+			expected: &expected{
+				format: FormatUnknown,
+				sure:   false,
+			},
+		},
+		{
+			name: "edge path: unknown version",
+			args: &args{
+				data: []byte(`{"version": 4}`),
+			},
+			expected: &expected{
+				format: FormatUnknown,
+				sure:   false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			format, sure := DetectFormat(tt.args.data)
+
+			// Assert
+			assert.Equal(t, tt.expected.format, format)
+			assert.Equal(t, tt.expected.sure, sure)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// TimePrecision is the number of decimals asciinema writes times with, that is
+// microseconds.
+const TimePrecision = 6
+
+// Time is an event time in seconds, kept as the decimal text it is written as, so
+// that times add up exactly and untouched times are written back as they were
+// read.
+type Time string
+
+// NewTime returns the time of d, written without trailing zeros.
+func NewTime(d decimal.Decimal) Time {
+	return Time(d.String())
+}
+
+// TimeOf returns the time of f, written in as few digits as it takes.
+func TimeOf(f float64) Time {
+	return NewTime(decimal.NewFromFloat(f))
+}
+
+// Decimal returns t as a decimal, and zero for the zero value.
+func (t Time) Decimal() decimal.Decimal {
+	d, err := decimal.NewFromString(string(t))
+	if err != nil {
+		return decimal.Zero
+	}
+
+	return d
+}
+
+func (t Time) Float64() float64 {
+	return t.Decimal().InexactFloat64()
+}
+
+// Move returns the time of d, or t as is if d is the same time, so that an
+// event left in place keeps its text.
+func (t Time) Move(d decimal.Decimal) Time {
+	if d.Equal(t.Decimal()) {
+		return t
+	}
+
+	return NewTime(d)
+}
+
+// Equal reports whether t and u are the same time, whatever their text.
+func (t Time) Equal(u Time) bool {
+	return t.Decimal().Equal(u.Decimal())
+}
+
+// Round rounds t to places decimals. A time with no more decimals is returned as
+// is, keeping its text.
+func (t Time) Round(places int32) Time {
+	d := t.Decimal()
+	if -d.Exponent() <= places {
+		return t
+	}
+
+	return NewTime(d.Round(places))
+}
+
+func (t Time) String() string {
+	if t == "" {
+		return "0"
+	}
+
+	return string(t)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTime_Round(t *testing.T) {
+	type args struct {
+		time   Time
+		places int32
+	}
+
+	type expected struct {
+		time Time
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: fewer decimals",
+			args: &args{
+				time:   "1.50",
+				places: 6,
+			},
+			expected: &expected{
+				time: "1.50",
+			},
+		},
+		{
+			name: "happy path: more decimals",
+			args: &args{
+				time:   "0.3333333333333333",
+				places: 6,
+			},
+			expected: &expected{
+				time: "0.333333",
+			},
+		},
+		{
+			name: "happy path: exponent",
+			args: &args{
+				time:   "5e-07",
+				places: 6,
+			},
+			expected: &expected{
+				time: "0.000001",
+			},
+		},
+		{
+			name: "happy path: zero value",
+			args: &args{
+				time:   "",
+				places: 6,
+			},
+			expected: &expected{
+				time: "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := tt.args.time.Round(tt.args.places)
+
+			// Assert
+			assert.Equal(t, tt.expected.time, actual)
+		})
+	}
+}
+
+func TestTime_Move(t *testing.T) {
+	type args struct {
+		time Time
+		to   decimal.Decimal
+	}
+
+	type expected struct {
+		time Time
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: same time",
+			args: &args{
+				time: "1.50",
+				to:   decimal.RequireFromString("1.5"),
+			},
+			expected: &expected{
+				time: "1.50",
+			},
+		},
+		{
+			name: "happy path: other time",
+			args: &args{
+				time: "1.50",
+				to:   decimal.RequireFromString("1.0").Add(decimal.RequireFromString("0.20")),
+			},
+			expected: &expected{
+				time: "1.2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := tt.args.time.Move(tt.args.to)
+
+			// Assert
+			assert.Equal(t, tt.expected.time, actual)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// V1 is an asciicast v1 recording, a single JSON object holding output frames
+// timed by the delay since the previous frame.
+type V1 struct {
+	Version  int               `json:"version"`
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+	Duration float64           `json:"duration,omitempty"`
+	Command  string            `json:"command,omitempty"`
+	Title    string            `json:"title,omitempty"`
+	Env      map[string]string `json:"env,omitempty"`
+	Stdout   []V1Frame         `json:"stdout"`
+}
+
+// V1Frame is an output frame of asciicast v1, written as [delay, data].
+type V1Frame struct {
+	Delay float64
+	Data  string
+}
+
+func (f *V1Frame) UnmarshalJSON(b []byte) error {
+	var v [2]any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	delay, ok := v[0].(float64)
+	if !ok {
+		return fmt.Errorf("invalid frame delay: %v", v[0])
+	}
+
+	data, ok := v[1].(string)
+	if !ok {
+		return fmt.Errorf("invalid frame data: %v", v[1])
+	}
+
+	f.Delay = delay
+	f.Data = data
+
+	return nil
+}
+
+func (f V1Frame) MarshalJSON() ([]byte, error) {
+	return marshalJSON([2]any{f.Delay, f.Data})
+}
@@ -21,8 +21,21 @@
 package asciinema
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+	v2HeaderKeys = []string{"version", "width", "height", "timestamp", "duration", "idle_time_limit", "command", "title", "env", "theme", "deltascii"}
 )
 
 type V2Header struct {
@@ -38,6 +51,118 @@ type V2Header struct {
 	Title         string            `json:"title,omitempty"`
 	Env           map[string]string `json:"env,omitempty"`
 	Theme         *V2HeaderTheme    `json:"theme,omitempty"`
+	// extension marking a Δ-asciicast v2
+	Delta *V2HeaderDelta `json:"deltascii,omitempty"`
+	// unknown fields such as vendor extensions, and known fields with an unexpected type
+	Extra map[string]json.RawMessage `json:"-"`
+
+	warnings []error
+}
+
+func (h *V2Header) UnmarshalJSON(b []byte) error {
+	keys, fields, err := decodeObject(b)
+	if err != nil {
+		return err
+	}
+
+	type alias V2Header
+
+	var v alias
+	for _, key := range keys {
+		raw := fields[key]
+
+		if slices.Contains(v2HeaderKeys, key) {
+			obj, err := encodeObject([]string{key}, fields)
+			if err != nil {
+				return err
+			}
+
+			// NOTE: try first not to leave a partially decoded field
+			var tmp alias
+			if err := json.Unmarshal(obj, &tmp); err == nil {
+				_ = json.Unmarshal(obj, &v)
+				continue
+			}
+
+			v.warnings = append(v.warnings, fmt.Errorf("invalid header %s: %s", key, raw))
+		}
+
+		if v.Extra == nil {
+			v.Extra = make(map[string]json.RawMessage)
+		}
+		v.Extra[key] = raw
+	}
+
+	*h = V2Header(v)
+
+	return nil
+}
+
+func (h V2Header) MarshalJSON() ([]byte, error) {
+	type alias V2Header
+
+	b, err := marshalJSON(alias(h))
+	if err != nil || len(h.Extra) == 0 {
+		return b, err
+	}
+
+	keys, fields, err := decodeObject(b)
+	if err != nil {
+		return nil, err
+	}
+
+	extraKeys := make([]string, 0, len(h.Extra))
+	for key := range h.Extra {
+		extraKeys = append(extraKeys, key)
+	}
+	slices.Sort(extraKeys)
+
+	for _, key := range extraKeys {
+		if v, ok := fields[key]; ok {
+			// NOTE: a known field set by the API takes precedence over the mistyped original
+			if !isZeroJSON(v) {
+				continue
+			}
+		} else {
+			keys = append(keys, key)
+		}
+		fields[key] = h.Extra[key]
+	}
+
+	return encodeObject(keys, fields)
+}
+
+// Warnings returns known fields that could not be decoded, which are kept in Extra as is.
+func (h *V2Header) Warnings() []error {
+	return h.warnings
+}
+
+func (h *V2Header) Validate() error {
+	if h.Version != 2 {
+		return fmt.Errorf("invalid header version: %v", h.Version)
+	}
+
+	if h.Width <= 0 {
+		return fmt.Errorf("invalid header width: %v", h.Width)
+	}
+
+	if h.Height <= 0 {
+		return fmt.Errorf("invalid header height: %v", h.Height)
+	}
+
+	if h.Theme != nil {
+		if err := h.Theme.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if h.Delta != nil {
+		if err := h.Delta.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 type V2HeaderTheme struct {
@@ -46,29 +171,87 @@ type V2HeaderTheme struct {
 	Palette string `json:"palette"`
 }
 
+func (t *V2HeaderTheme) Validate() error {
+	if !colorPattern.MatchString(t.FG) {
+		return fmt.Errorf("invalid theme fg: %v", t.FG)
+	}
+
+	if !colorPattern.MatchString(t.BG) {
+		return fmt.Errorf("invalid theme bg: %v", t.BG)
+	}
+
+	colors := strings.Split(t.Palette, ":")
+	if len(colors) != 8 && len(colors) != 16 {
+		return fmt.Errorf("invalid theme palette: %v (8 or 16 colors required, got %d)", t.Palette, len(colors))
+	}
+
+	for _, c := range colors {
+		if !colorPattern.MatchString(c) {
+			return fmt.Errorf("invalid theme palette color: %v", c)
+		}
+	}
+
+	return nil
+}
+
+// DeltaVersion is the version of Δ-asciicast v2 written by this package.
+const DeltaVersion = 1
+
+// V2HeaderDelta marks a Δ-asciicast v2, whose event times are the time since the
+// previous event. Duration is the duration of the asciicast it was made from, and
+// Times are the event times of it written otherwise than as NewTime writes them,
+// such as 1.0, so that the same times are written back alike.
+type V2HeaderDelta struct {
+	Version  int           `json:"version"`
+	Duration float64       `json:"duration"`
+	Times    []json.Number `json:"times,omitempty"`
+}
+
+func (d *V2HeaderDelta) Validate() error {
+	if d.Version <= 0 || d.Version > DeltaVersion {
+		return fmt.Errorf("unsupported Δ-asciicast version: %v", d.Version)
+	}
+
+	for _, t := range d.Times {
+		if _, err := decimal.NewFromString(t.String()); err != nil {
+			return fmt.Errorf("invalid Δ-asciicast time: %v", t)
+		}
+	}
+
+	return nil
+}
+
 type V2Event struct {
-	Time float64 `json:"time"`
-	Code string  `json:"code"`
-	Data any     `json:"data"`
+	Time Time   `json:"time"`
+	Code string `json:"code"`
+	Data any    `json:"data"`
 }
 
 func (e *V2Event) UnmarshalJSON(b []byte) error {
 	var v [3]any
-	if err := json.Unmarshal(b, &v); err != nil {
+
+	// NOTE: keep the original number text of time and data
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
 		return err
 	}
 
-	t, ok := v[0].(float64)
+	n, ok := v[0].(json.Number)
 	if !ok {
 		return fmt.Errorf("invalid event time: %v", v[0])
 	}
 
+	if _, err := decimal.NewFromString(n.String()); err != nil {
+		return fmt.Errorf("invalid event time: %v", v[0])
+	}
+
 	c, ok := v[1].(string)
 	if !ok {
 		return fmt.Errorf("invalid event code: %v", v[1])
 	}
 
-	e.Time = t
+	e.Time = Time(n)
 	e.Code = c
 	e.Data = v[2]
 
@@ -76,5 +259,409 @@ func (e *V2Event) UnmarshalJSON(b []byte) error {
 }
 
 func (e V2Event) MarshalJSON() ([]byte, error) {
-	return json.Marshal([3]any{e.Time, e.Code, e.Data})
+	return marshalJSON([3]any{json.Number(e.Time.String()), e.Code, e.Data})
+}
+
+// PatchV2Event encodes e the way orig is written, so that the spacing of the
+// event and the text of its untouched elements, escapes included, are kept.
+// Changed elements are encoded afresh, spaced as orig is.
+func PatchV2Event(orig []byte, e *V2Event) ([]byte, error) {
+	var o V2Event
+	if err := json.Unmarshal(orig, &o); err != nil {
+		return marshalJSON(e)
+	}
+
+	bounds, err := scanArray(orig)
+	if err != nil || len(bounds) != 3 {
+		return marshalJSON(e)
+	}
+
+	values := make([][]byte, 0, len(bounds))
+	for _, b := range bounds {
+		values = append(values, orig[b[0]:b[1]])
+	}
+	values[0] = []byte(e.Time.String())
+
+	if o.Code != e.Code {
+		if values[1], err = marshalJSON(e.Code); err != nil {
+			return nil, err
+		}
+	}
+	if !reflect.DeepEqual(o.Data, e.Data) {
+		data, err := marshalJSON(e.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		comma, colon := inlineSeps(orig[bounds[0][1]:bounds[1][0]])
+		values[2] = spaceJSON(data, comma, colon)
+	}
+
+	b := make([]byte, 0, len(orig))
+	end := 0
+	for i, v := range values {
+		b = append(b, orig[end:bounds[i][0]]...)
+		b = append(b, v...)
+		end = bounds[i][1]
+	}
+	b = append(b, orig[end:]...)
+
+	return b, nil
+}
+
+// scanArray returns the start and end offsets of each element of the JSON array b.
+func scanArray(b []byte) ([][2]int, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("invalid array: %s", b)
+	}
+
+	bounds := make([][2]int, 0)
+	for dec.More() {
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		end := int(dec.InputOffset())
+		bounds = append(bounds, [2]int{end - len(value), end})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	return bounds, nil
+}
+
+// PatchV2Header encodes h the way orig is written, so that untouched fields keep
+// their key order and text, whitespace included, and only changed fields differ.
+func PatchV2Header(orig []byte, h *V2Header) ([]byte, error) {
+	b, err := marshalJSON(h)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, fields, err := decodeObject(b)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := scanObject(orig)
+	if err != nil {
+		return nil, err
+	}
+
+	comma, colon := layout.inline()
+
+	members := make([][]byte, 0, len(keys))
+	seen := make([]string, 0, len(keys))
+	for _, m := range layout.members {
+		v, ok := fields[m.key]
+		if !ok {
+			// NOTE: removed
+			continue
+		}
+
+		text := append([]byte(nil), m.prefix...)
+		if equalJSON(m.value, v) {
+			text = append(text, m.value...)
+		} else {
+			text = append(text, spaceJSON(v, comma, colon)...)
+		}
+		members = append(members, text)
+		seen = append(seen, m.key)
+	}
+
+	for _, key := range keys {
+		if slices.Contains(seen, key) {
+			continue
+		}
+
+		k, err := marshalJSON(key)
+		if err != nil {
+			return nil, err
+		}
+
+		text := append(k, layout.colon...)
+		members = append(members, append(text, spaceJSON(fields[key], comma, colon)...))
+	}
+
+	buf := new(bytes.Buffer)
+	buf.Write(layout.open)
+	for i, m := range members {
+		if i > 0 {
+			buf.Write(layout.sep(i - 1))
+		}
+		buf.Write(m)
+	}
+	buf.Write(layout.close)
+
+	return buf.Bytes(), nil
+}
+
+// objectLayout is the text of a JSON object split around its members.
+type objectLayout struct {
+	open    []byte
+	seps    [][]byte
+	close   []byte
+	colon   []byte
+	members []objectMember
+}
+
+// objectMember is a member of a JSON object, where prefix is the text from the
+// key up to the value.
+type objectMember struct {
+	key    string
+	prefix []byte
+	value  json.RawMessage
+}
+
+func (l *objectLayout) sep(i int) []byte {
+	switch {
+	case i < len(l.seps):
+		return l.seps[i]
+	case len(l.seps) > 0:
+		return l.seps[len(l.seps)-1]
+	default:
+		return []byte(",")
+	}
+}
+
+// inline returns the comma and colon to write nested values with, spaced as the
+// members are.
+func (l *objectLayout) inline() (comma, colon []byte) {
+	if len(l.seps) > 0 {
+		comma, colon = inlineSeps(l.seps[0])
+	} else {
+		comma, colon = inlineSeps(l.colon)
+	}
+	if !bytes.ContainsAny(l.colon, "\r\n") {
+		colon = l.colon
+	}
+
+	return comma, colon
+}
+
+// inlineSeps returns the comma and colon of values written on one line, spaced
+// if sep is.
+func inlineSeps(sep []byte) (comma, colon []byte) {
+	if len(bytes.TrimSpace(sep)) < len(sep) {
+		return []byte(", "), []byte(": ")
+	}
+
+	return []byte(","), []byte(":")
+}
+
+func scanObject(b []byte) (*objectLayout, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("invalid object: %s", b)
+	}
+
+	layout := &objectLayout{
+		seps:    make([][]byte, 0),
+		colon:   []byte(":"),
+		members: make([]objectMember, 0),
+	}
+
+	end := int(dec.InputOffset())
+	for dec.More() {
+		start := end
+		for start < len(b) && strings.ContainsRune(" \t\r\n,", rune(b[start])) {
+			start++
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid object: %s", b)
+		}
+		keyEnd := int(dec.InputOffset())
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		valueStart := int(dec.InputOffset()) - len(value)
+
+		if len(layout.members) == 0 {
+			layout.open = b[:start]
+			layout.colon = b[keyEnd:valueStart]
+		} else {
+			layout.seps = append(layout.seps, b[end:start])
+		}
+		end = int(dec.InputOffset())
+
+		if slices.ContainsFunc(layout.members, func(m objectMember) bool { return m.key == key }) {
+			continue
+		}
+		layout.members = append(layout.members, objectMember{key: key, prefix: b[start:valueStart], value: value})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	if len(layout.members) == 0 {
+		layout.open = b[:end]
+	}
+	layout.close = b[end:dec.InputOffset()]
+
+	return layout, nil
+}
+
+func compactJSON(b []byte) []byte {
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, b); err != nil {
+		return b
+	}
+
+	return buf.Bytes()
+}
+
+// spaceJSON writes b on one line with comma and colon between its elements.
+func spaceJSON(b []byte, comma, colon []byte) []byte {
+	b = compactJSON(b)
+
+	out := make([]byte, 0, len(b))
+	inString, escaped := false, false
+	for _, c := range b {
+		if inString {
+			out = append(out, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			out = append(out, c)
+		case ',':
+			out = append(out, comma...)
+		case ':':
+			out = append(out, colon...)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func marshalJSON(v any) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func decodeObject(b []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("invalid object: %s", b)
+	}
+
+	keys := make([]string, 0)
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid object: %s", b)
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+
+		if _, ok := fields[key]; !ok {
+			keys = append(keys, key)
+		}
+		fields[key] = value
+	}
+
+	return keys, fields, nil
+}
+
+func encodeObject(keys []string, fields map[string]json.RawMessage) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		k, err := marshalJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+
+		if err := json.Compact(buf, fields[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func isZeroJSON(b []byte) bool {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return false
+	}
+
+	return v == nil || v == 0.0 || v == "" || v == false
+}
+
+func equalJSON(a, b []byte) bool {
+	var x, y any
+	if err := json.Unmarshal(a, &x); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &y); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(x, y)
 }
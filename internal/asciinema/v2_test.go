@@ -86,6 +86,62 @@ func TestV2Header_UnmarshalJSON(t *testing.T) {
 				err: nil,
 			},
 		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				b: []byte(`{"version": 2, "width": 80, "height": 24, "deltascii": {"version": 1, "duration": 5.5}}`),
+			},
+			expected: &expected{
+				data: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Delta:   &V2HeaderDelta{Version: 1, Duration: 5.5},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "happy path: unknown fields",
+			args: &args{
+				b: []byte(`{"version": 2, "width": 80, "height": 24, "x-vendor": {"term": "xterm"}, "term": {"type": "xterm-256color"}}`),
+			},
+			expected: &expected{
+				data: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Extra: map[string]json.RawMessage{
+						"x-vendor": json.RawMessage(`{"term": "xterm"}`),
+						"term":     json.RawMessage(`{"type": "xterm-256color"}`),
+					},
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "happy path: unexpected type",
+			args: &args{
+				b: []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": "1504467315", "env": {"SHELL": null}}`),
+			},
+			expected: &expected{
+				data: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Env: map[string]string{
+						"SHELL": "",
+					},
+					Extra: map[string]json.RawMessage{
+						"timestamp": json.RawMessage(`"1504467315"`),
+					},
+					warnings: []error{
+						fmt.Errorf("invalid header %s: %s", "timestamp", `"1504467315"`),
+					},
+				},
+				err: nil,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -155,6 +211,24 @@ func TestV2Header_MarshalJSON(t *testing.T) {
 				err:  nil,
 			},
 		},
+		{
+			name: "happy path: extra",
+			data: &V2Header{
+				Version:   2,
+				Width:     80,
+				Height:    24,
+				Timestamp: 0,
+				Extra: map[string]json.RawMessage{
+					"x-vendor":  json.RawMessage(`{"term": "xterm"}`),
+					"timestamp": json.RawMessage(`"1504467315"`),
+					"width":     json.RawMessage(`"80"`),
+				},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"timestamp":"1504467315","x-vendor":{"term":"xterm"}}`),
+				err:  nil,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -174,6 +248,181 @@ func TestV2Header_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestV2Header_Validate(t *testing.T) {
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		data     *V2Header
+		expected *expected
+	}{
+		{
+			name: "happy path: required",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+			},
+			expected: &expected{
+				err: nil,
+			},
+		},
+		{
+			name: "happy path: theme",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Theme: &V2HeaderTheme{
+					FG:      "#d0d0d0",
+					BG:      "#212121",
+					Palette: "#151515:#ac4142:#7e8e50:#e5b567:#6c99bb:#9f4e85:#7dd6cf:#d0d0d0",
+				},
+			},
+			expected: &expected{
+				err: nil,
+			},
+		},
+		{
+			name: "happy path: delta",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Delta:   &V2HeaderDelta{Version: 1, Duration: 5.5},
+			},
+			expected: &expected{
+				err: nil,
+			},
+		},
+		{
+			name: "edge path: invalid version",
+			data: &V2Header{
+				Version: 1,
+				Width:   80,
+				Height:  24,
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid header version: %v", 1),
+			},
+		},
+		{
+			name: "edge path: invalid width",
+			data: &V2Header{
+				Version: 2,
+				Width:   0,
+				Height:  24,
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid header width: %v", 0),
+			},
+		},
+		{
+			name: "edge path: invalid height",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  -1,
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid header height: %v", -1),
+			},
+		},
+		{
+			name: "edge path: invalid theme fg",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Theme: &V2HeaderTheme{
+					FG:      "d0d0d0",
+					BG:      "#212121",
+					Palette: "#151515:#ac4142:#7e8e50:#e5b567:#6c99bb:#9f4e85:#7dd6cf:#d0d0d0",
+				},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid theme fg: %v", "d0d0d0"),
+			},
+		},
+		{
+			name: "edge path: invalid theme bg",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Theme: &V2HeaderTheme{
+					FG:      "#d0d0d0",
+					BG:      "#fff",
+					Palette: "#151515:#ac4142:#7e8e50:#e5b567:#6c99bb:#9f4e85:#7dd6cf:#d0d0d0",
+				},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid theme bg: %v", "#fff"),
+			},
+		},
+		{
+			name: "edge path: invalid theme palette length",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Theme: &V2HeaderTheme{
+					FG:      "#d0d0d0",
+					BG:      "#212121",
+					Palette: "#151515:#ac4142",
+				},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid theme palette: %v (8 or 16 colors required, got %d)", "#151515:#ac4142", 2),
+			},
+		},
+		{
+			name: "edge path: invalid theme palette color",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Theme: &V2HeaderTheme{
+					FG:      "#d0d0d0",
+					BG:      "#212121",
+					Palette: "#151515:#ac4142:#7e8e50:#e5b567:#6c99bb:#9f4e85:#7dd6cf:red",
+				},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid theme palette color: %v", "red"),
+			},
+		},
+		{
+			name: "edge path: unsupported delta version",
+			data: &V2Header{
+				Version: 2,
+				Width:   80,
+				Height:  24,
+				Delta:   &V2HeaderDelta{Version: 2},
+			},
+			expected: &expected{
+				err: fmt.Errorf("unsupported Δ-asciicast version: %v", 2),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			err := tt.data.Validate()
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
 func TestV2Event_UnmarshalJSON(t *testing.T) {
 	type args struct {
 		b []byte
@@ -196,13 +445,27 @@ func TestV2Event_UnmarshalJSON(t *testing.T) {
 			},
 			expected: &expected{
 				data: &V2Event{
-					Time: 0.123456789,
+					Time: "0.123456789",
 					Code: "o",
 					Data: "hello world",
 				},
 				err: nil,
 			},
 		},
+		{
+			name: "happy path: number data",
+			args: &args{
+				b: []byte(`[1.50, "x", 1.50]`),
+			},
+			expected: &expected{
+				data: &V2Event{
+					Time: "1.50",
+					Code: "x",
+					Data: json.Number("1.50"),
+				},
+				err: nil,
+			},
+		},
 		{
 			name: "edge path: invalid event time",
 			args: &args{
@@ -257,7 +520,7 @@ func TestV2Event_MarshalJSON(t *testing.T) {
 		{
 			name: "happy path",
 			data: &V2Event{
-				Time: 0.123456789,
+				Time: "0.123456789",
 				Code: "o",
 				Data: "hello world",
 			},
@@ -266,12 +529,47 @@ func TestV2Event_MarshalJSON(t *testing.T) {
 				err:  nil,
 			},
 		},
+		{
+			name: "happy path: no html escape",
+			data: &V2Event{
+				Time: "1",
+				Code: "o",
+				Data: "<a & b>\u001b[0m",
+			},
+			expected: &expected{
+				data: []byte(`[1,"o","<a & b>\u001b[0m"]`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: number data",
+			data: &V2Event{
+				Time: "1.50",
+				Code: "x",
+				Data: json.Number("1.50"),
+			},
+			expected: &expected{
+				data: []byte(`[1.50,"x",1.50]`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: zero value",
+			data: &V2Event{
+				Code: "m",
+				Data: "",
+			},
+			expected: &expected{
+				data: []byte(`[0,"m",""]`),
+				err:  nil,
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Act
-			actual, err := json.Marshal(tt.data)
+			actual, err := tt.data.MarshalJSON()
 
 			// Assert
 			if strings.HasPrefix(tt.name, "happy") {
@@ -284,3 +582,261 @@ func TestV2Event_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchV2Header(t *testing.T) {
+	type args struct {
+		orig []byte
+		h    *V2Header
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: untouched",
+			args: &args{
+				orig: []byte(`{"width": 80, "version": 2, "height": 24, "duration": 1.50, "x-vendor": {"a": 1}}`),
+				h: &V2Header{
+					Version:  2,
+					Width:    80,
+					Height:   24,
+					Duration: 1.5,
+					Extra: map[string]json.RawMessage{
+						"x-vendor": json.RawMessage(`{"a": 1}`),
+					},
+				},
+			},
+			expected: &expected{
+				data: []byte(`{"width": 80, "version": 2, "height": 24, "duration": 1.50, "x-vendor": {"a": 1}}`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: changed, added and removed",
+			args: &args{
+				orig: []byte(`{"width": 80, "version": 2, "height": 24, "duration": 1.50, "x-vendor": {"a": 1}}`),
+				h: &V2Header{
+					Version: 2,
+					Width:   100,
+					Height:  24,
+					Title:   "<Demo>",
+					Extra: map[string]json.RawMessage{
+						"x-vendor": json.RawMessage(`{"a": 1}`),
+						"x-added":  json.RawMessage(`true`),
+					},
+				},
+			},
+			expected: &expected{
+				data: []byte(`{"width": 100, "version": 2, "height": 24, "x-vendor": {"a": 1}, "title": "<Demo>", "x-added": true}`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: multi-line",
+			args: &args{
+				orig: []byte("{\n  \"version\": 2,\n  \"width\": 80,\n  \"height\": 24\n}"),
+				h: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Title:   "demo",
+				},
+			},
+			expected: &expected{
+				data: []byte("{\n  \"version\": 2,\n  \"width\": 80,\n  \"height\": 24,\n  \"title\": \"demo\"\n}"),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: nested value spaced as the header",
+			args: &args{
+				orig: []byte(`{"version": 2, "width": 80, "height": 24}`),
+				h: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Delta:   &V2HeaderDelta{Version: 1, Duration: 5.5, Times: []json.Number{"1.0"}},
+				},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "deltascii": {"version": 1, "duration": 5.5, "times": [1.0]}}`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: nested value in compact header",
+			args: &args{
+				orig: []byte(`{"version":2,"width":80,"height":24}`),
+				h: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Env:     map[string]string{"TERM": "a:b,c"},
+				},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"env":{"TERM":"a:b,c"}}`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: nested value in multi-line header",
+			args: &args{
+				orig: []byte("{\n  \"version\": 2,\n  \"width\": 80,\n  \"height\": 24\n}"),
+				h: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+					Env:     map[string]string{"TERM": "a:b,c"},
+				},
+			},
+			expected: &expected{
+				data: []byte("{\n  \"version\": 2,\n  \"width\": 80,\n  \"height\": 24,\n  \"env\": {\"TERM\": \"a:b,c\"}\n}"),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: empty original",
+			args: &args{
+				orig: []byte(`{ }`),
+				h: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+				},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24 }`),
+				err:  nil,
+			},
+		},
+		{
+			name: "edge path: invalid original",
+			args: &args{
+				orig: []byte(`[]`),
+				h: &V2Header{
+					Version: 2,
+					Width:   80,
+					Height:  24,
+				},
+			},
+			expected: &expected{
+				data: nil,
+				err:  fmt.Errorf("invalid object: %s", `[]`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := PatchV2Header(tt.args.orig, tt.args.h)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), string(actual))
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, actual)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestPatchV2Event(t *testing.T) {
+	type args struct {
+		orig []byte
+		e    *V2Event
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: time changed",
+			args: &args{
+				orig: []byte(`[ 0.5,  "o", "\u0068i \/"]`),
+				e:    &V2Event{Time: "1.25", Code: "o", Data: "hi /"},
+			},
+			expected: &expected{
+				data: []byte(`[ 1.25,  "o", "\u0068i \/"]`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: data changed",
+			args: &args{
+				orig: []byte(`[0.5, "o", "\u0068i"]`),
+				e:    &V2Event{Time: "0.5", Code: "o", Data: "<b>"},
+			},
+			expected: &expected{
+				data: []byte(`[0.5, "o", "<b>"]`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: code and data changed",
+			args: &args{
+				orig: []byte(`[0.5, "o", "\u0068i"]`),
+				e:    &V2Event{Time: "0.5", Code: "x", Data: map[string]any{"status": 0, "note": "a:b,c"}},
+			},
+			expected: &expected{
+				data: []byte(`[0.5, "x", {"note": "a:b,c", "status": 0}]`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: compact original",
+			args: &args{
+				orig: []byte(`[0.5,"o","a"]`),
+				e:    &V2Event{Time: "1", Code: "o", Data: "b"},
+			},
+			expected: &expected{
+				data: []byte(`[1,"o","b"]`),
+				err:  nil,
+			},
+		},
+		{
+			name: "happy path: no original",
+			args: &args{
+				orig: nil,
+				e:    &V2Event{Time: "0.5", Code: "o", Data: "a"},
+			},
+			expected: &expected{
+				data: []byte(`[0.5,"o","a"]`),
+				err:  nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := PatchV2Event(tt.args.orig, tt.args.e)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), string(actual))
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, actual)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
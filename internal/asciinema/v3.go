@@ -0,0 +1,100 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"maps"
+)
+
+// V3Header is the header of asciicast v3, whose events are timed by the interval
+// since the previous event.
+type V3Header struct {
+	// required
+	Version int          `json:"version"`
+	Term    V3HeaderTerm `json:"term"`
+	// optional
+	Timestamp     int               `json:"timestamp,omitempty"`
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Command       string            `json:"command,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
+}
+
+type V3HeaderTerm struct {
+	Cols    int            `json:"cols"`
+	Rows    int            `json:"rows"`
+	Type    string         `json:"type,omitempty"`
+	Version string         `json:"version,omitempty"`
+	Theme   *V2HeaderTheme `json:"theme,omitempty"`
+}
+
+// NewV3Header converts a v2 header, moving TERM from env into the terminal type.
+// The duration is dropped, since v3 has none.
+func NewV3Header(h *V2Header) *V3Header {
+	v3 := &V3Header{
+		Version: 3,
+		Term: V3HeaderTerm{
+			Cols:  h.Width,
+			Rows:  h.Height,
+			Theme: h.Theme,
+		},
+		Timestamp:     h.Timestamp,
+		IdleTimeLimit: h.IdleTimeLimit,
+		Command:       h.Command,
+		Title:         h.Title,
+		Env:           maps.Clone(h.Env),
+	}
+
+	if term, ok := v3.Env["TERM"]; ok {
+		v3.Term.Type = term
+		delete(v3.Env, "TERM")
+	}
+	if len(v3.Env) == 0 {
+		v3.Env = nil
+	}
+
+	return v3
+}
+
+// V2 converts the header into v2, the inverse of NewV3Header.
+func (h *V3Header) V2() *V2Header {
+	v2 := &V2Header{
+		Version:       2,
+		Width:         h.Term.Cols,
+		Height:        h.Term.Rows,
+		Timestamp:     h.Timestamp,
+		IdleTimeLimit: h.IdleTimeLimit,
+		Command:       h.Command,
+		Title:         h.Title,
+		Env:           maps.Clone(h.Env),
+		Theme:         h.Term.Theme,
+	}
+
+	if h.Term.Type != "" {
+		if v2.Env == nil {
+			v2.Env = make(map[string]string)
+		}
+		v2.Env["TERM"] = h.Term.Type
+	}
+
+	return v2
+}
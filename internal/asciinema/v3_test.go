@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package asciinema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewV3Header(t *testing.T) {
+	theme := &V2HeaderTheme{FG: "#ffffff", BG: "#000000", Palette: "#000000:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf"}
+
+	type args struct {
+		header *V2Header
+	}
+
+	type expected struct {
+		header *V3Header
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				header: &V2Header{Version: 2, Width: 80, Height: 24, Timestamp: 1504467315, Duration: 5.5, Title: "Demo", Env: map[string]string{"SHELL": "/bin/zsh", "TERM": "xterm-256color"}, Theme: theme},
+			},
+			expected: &expected{
+				header: &V3Header{Version: 3, Term: V3HeaderTerm{Cols: 80, Rows: 24, Type: "xterm-256color", Theme: theme}, Timestamp: 1504467315, Title: "Demo", Env: map[string]string{"SHELL": "/bin/zsh"}},
+			},
+		},
+		{
+			name: "happy path: only TERM",
+			args: &args{
+				header: &V2Header{Version: 2, Width: 80, Height: 24, Env: map[string]string{"TERM": "xterm"}},
+			},
+			expected: &expected{
+				header: &V3Header{Version: 3, Term: V3HeaderTerm{Cols: 80, Rows: 24, Type: "xterm"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			h := NewV3Header(tt.args.header)
+
+			// Assert
+			assert.Equal(t, tt.expected.header, h)
+		})
+	}
+}
+
+func TestV3Header_V2(t *testing.T) {
+	type args struct {
+		header *V3Header
+	}
+
+	type expected struct {
+		header *V2Header
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				header: &V3Header{Version: 3, Term: V3HeaderTerm{Cols: 80, Rows: 24, Type: "xterm-256color"}, IdleTimeLimit: 2, Env: map[string]string{"SHELL": "/bin/zsh"}, Tags: []string{"demo"}},
+			},
+			expected: &expected{
+				header: &V2Header{Version: 2, Width: 80, Height: 24, IdleTimeLimit: 2, Env: map[string]string{"SHELL": "/bin/zsh", "TERM": "xterm-256color"}},
+			},
+		},
+		{
+			name: "happy path: no env",
+			args: &args{
+				header: &V3Header{Version: 3, Term: V3HeaderTerm{Cols: 80, Rows: 24}},
+			},
+			expected: &expected{
+				header: &V2Header{Version: 2, Width: 80, Height: 24},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			h := tt.args.header.V2()
+
+			// Assert
+			assert.Equal(t, tt.expected.header, h)
+		})
+	}
+}
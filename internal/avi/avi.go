@@ -0,0 +1,250 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package avi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	avifHasIndex   = 0x10
+	aviifKeyframe  = 0x10
+	mainHeaderSize = 56
+	streamHdrSize  = 56
+	bitmapInfoSize = 40
+	indexEntrySize = 16
+)
+
+var (
+	errTooLarge = errors.New("file too large, avi is limited to 4 GiB")
+)
+
+type mainHeader struct {
+	MicroSecPerFrame    uint32
+	MaxBytesPerSec      uint32
+	PaddingGranularity  uint32
+	Flags               uint32
+	TotalFrames         uint32
+	InitialFrames       uint32
+	Streams             uint32
+	SuggestedBufferSize uint32
+	Width               uint32
+	Height              uint32
+	Reserved            [4]uint32
+}
+
+type streamHeader struct {
+	Type                [4]byte
+	Handler             [4]byte
+	Flags               uint32
+	Priority            uint16
+	Language            uint16
+	InitialFrames       uint32
+	Scale               uint32
+	Rate                uint32
+	Start               uint32
+	Length              uint32
+	SuggestedBufferSize uint32
+	Quality             uint32
+	SampleSize          uint32
+	Frame               [4]uint16
+}
+
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   [4]byte
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// Writer builds a Motion JPEG AVI. Frames are kept in memory until Close,
+// because the headers carry the frame count and the index follows the data.
+type Writer struct {
+	w        io.Writer
+	width    int
+	height   int
+	fps      int
+	images   [][]byte
+	sequence []int
+}
+
+func NewWriter(w io.Writer, width, height, fps int) (*Writer, error) {
+	if width <= 0 || height <= 0 || width > math.MaxUint16 || height > math.MaxUint16 {
+		return nil, fmt.Errorf("invalid size: %dx%d", width, height)
+	}
+
+	if fps <= 0 {
+		return nil, fmt.Errorf("invalid fps: %v", fps)
+	}
+
+	return &Writer{w: w, width: width, height: height, fps: fps}, nil
+}
+
+// WriteFrame appends a JPEG image shown for count video frames.
+func (w *Writer) WriteFrame(jpeg []byte, count int) {
+	if count <= 0 {
+		return
+	}
+
+	w.images = append(w.images, jpeg)
+	for i := 0; i < count; i++ {
+		w.sequence = append(w.sequence, len(w.images)-1)
+	}
+}
+
+func (w *Writer) Close() error {
+	moviSize := uint64(4)
+	maxSize := 0
+	for _, i := range w.sequence {
+		moviSize += 8 + uint64(padded(len(w.images[i])))
+		maxSize = max(maxSize, len(w.images[i]))
+	}
+
+	strlSize := 4 + (8 + streamHdrSize) + (8 + bitmapInfoSize)
+	hdrlSize := 4 + (8 + mainHeaderSize) + (8 + strlSize)
+	idx1Size := indexEntrySize * uint64(len(w.sequence))
+	riffSize := 4 + (8 + uint64(hdrlSize)) + (8 + moviSize) + (8 + idx1Size)
+	if riffSize > math.MaxUint32 {
+		return errTooLarge
+	}
+
+	frames := uint32(len(w.sequence))
+	e := &encoder{w: w.w}
+
+	e.fourCC("RIFF")
+	e.write(uint32(riffSize))
+	e.fourCC("AVI ")
+
+	e.fourCC("LIST")
+	e.write(uint32(hdrlSize))
+	e.fourCC("hdrl")
+
+	e.fourCC("avih")
+	e.write(uint32(mainHeaderSize))
+	e.write(&mainHeader{
+		MicroSecPerFrame:    uint32(1000000 / w.fps),
+		MaxBytesPerSec:      uint32(maxSize * w.fps),
+		Flags:               avifHasIndex,
+		TotalFrames:         frames,
+		Streams:             1,
+		SuggestedBufferSize: uint32(padded(maxSize) + 8),
+		Width:               uint32(w.width),
+		Height:              uint32(w.height),
+	})
+
+	e.fourCC("LIST")
+	e.write(uint32(strlSize))
+	e.fourCC("strl")
+
+	e.fourCC("strh")
+	e.write(uint32(streamHdrSize))
+	e.write(&streamHeader{
+		Type:                [4]byte{'v', 'i', 'd', 's'},
+		Handler:             [4]byte{'M', 'J', 'P', 'G'},
+		Scale:               1,
+		Rate:                uint32(w.fps),
+		Length:              frames,
+		SuggestedBufferSize: uint32(padded(maxSize) + 8),
+		Quality:             math.MaxUint32,
+		Frame:               [4]uint16{0, 0, uint16(w.width), uint16(w.height)},
+	})
+
+	e.fourCC("strf")
+	e.write(uint32(bitmapInfoSize))
+	e.write(&bitmapInfoHeader{
+		Size:        bitmapInfoSize,
+		Width:       int32(w.width),
+		Height:      int32(w.height),
+		Planes:      1,
+		BitCount:    24,
+		Compression: [4]byte{'M', 'J', 'P', 'G'},
+		SizeImage:   uint32(w.width * w.height * 3),
+	})
+
+	e.fourCC("LIST")
+	e.write(uint32(moviSize))
+	e.fourCC("movi")
+	for _, i := range w.sequence {
+		img := w.images[i]
+		e.fourCC("00dc")
+		e.write(uint32(len(img)))
+		e.bytes(img)
+		if len(img)%2 != 0 {
+			e.bytes([]byte{0})
+		}
+	}
+
+	// NOTE: index offsets are relative to the "movi" list type
+	e.fourCC("idx1")
+	e.write(uint32(idx1Size))
+	offset := uint32(4)
+	for _, i := range w.sequence {
+		img := w.images[i]
+		e.fourCC("00dc")
+		e.write(uint32(aviifKeyframe))
+		e.write(offset)
+		e.write(uint32(len(img)))
+		offset += 8 + uint32(padded(len(img)))
+	}
+
+	return e.err
+}
+
+func padded(n int) int {
+	return n + n%2
+}
+
+// encoder writes little endian values and keeps the first error.
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) write(v any) {
+	if e.err != nil {
+		return
+	}
+
+	e.err = binary.Write(e.w, binary.LittleEndian, v)
+}
+
+func (e *encoder) bytes(b []byte) {
+	if e.err != nil {
+		return
+	}
+
+	_, e.err = e.w.Write(b)
+}
+
+func (e *encoder) fourCC(s string) {
+	e.bytes([]byte(s))
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package avi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWriter(t *testing.T) {
+	type args struct {
+		width  int
+		height int
+		fps    int
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path",
+			args:     &args{width: 16, height: 8, fps: 10},
+			expected: &expected{},
+		},
+		{
+			name:     "edge path: invalid size",
+			args:     &args{width: 0, height: 8, fps: 10},
+			expected: &expected{err: fmt.Errorf("invalid size: %dx%d", 0, 8)},
+		},
+		{
+			name:     "edge path: invalid fps",
+			args:     &args{width: 16, height: 8, fps: 0},
+			expected: &expected{err: fmt.Errorf("invalid fps: %v", 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			w, err := NewWriter(new(bytes.Buffer), tt.args.width, tt.args.height, tt.args.fps)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NotNil(t, w)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, w)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestWriter_Close(t *testing.T) {
+	type frame struct {
+		data  []byte
+		count int
+	}
+
+	type args struct {
+		frames []frame
+	}
+
+	type expected struct {
+		totalFrames uint32
+		chunks      [][]byte
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: repeated frames",
+			args: &args{
+				frames: []frame{
+					{data: []byte("abc"), count: 2},
+					{data: []byte("defg"), count: 1},
+					{data: []byte("skip"), count: 0},
+				},
+			},
+			expected: &expected{
+				totalFrames: 3,
+				chunks:      [][]byte{[]byte("abc"), []byte("abc"), []byte("defg")},
+			},
+		},
+		{
+			name: "happy path: no frames",
+			args: &args{},
+			expected: &expected{
+				totalFrames: 0,
+				chunks:      [][]byte{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			buf := new(bytes.Buffer)
+			w, err := NewWriter(buf, 16, 8, 10)
+			assert.NoError(t, err)
+
+			for _, f := range tt.args.frames {
+				w.WriteFrame(f.data, f.count)
+			}
+
+			// Act
+			err = w.Close()
+
+			// Assert
+			assert.NoError(t, err)
+
+			b := buf.Bytes()
+			assert.Equal(t, "RIFF", string(b[0:4]))
+			assert.Equal(t, uint32(len(b)-8), binary.LittleEndian.Uint32(b[4:8]))
+			assert.Equal(t, "AVI ", string(b[8:12]))
+
+			avih := bytes.Index(b, []byte("avih"))
+			assert.Equal(t, uint32(100000), binary.LittleEndian.Uint32(b[avih+8:]))
+			assert.Equal(t, tt.expected.totalFrames, binary.LittleEndian.Uint32(b[avih+8+16:]))
+
+			movi := bytes.Index(b, []byte("movi"))
+			idx1 := bytes.Index(b, []byte("idx1"))
+			assert.Equal(t, uint32(idx1-movi), binary.LittleEndian.Uint32(b[movi-4:]))
+
+			chunks := make([][]byte, 0)
+			for i := 0; i < int(binary.LittleEndian.Uint32(b[idx1+4:]))/16; i++ {
+				entry := b[idx1+8+16*i:]
+				assert.Equal(t, "00dc", string(entry[0:4]))
+				offset := binary.LittleEndian.Uint32(entry[8:])
+				size := binary.LittleEndian.Uint32(entry[12:])
+				chunk := b[movi+int(offset):]
+				assert.Equal(t, "00dc", string(chunk[0:4]))
+				chunks = append(chunks, chunk[8:8+size])
+			}
+			assert.Equal(t, tt.expected.chunks, chunks)
+		})
+	}
+}
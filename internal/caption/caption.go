@@ -0,0 +1,243 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package caption
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/vt"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	DefaultPrompt = regexp.MustCompile(`^.*?[$#%>❯] `)
+)
+
+type Cue struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+type Options struct {
+	// Prompt matches the prompt at the start of a line, used to find commands
+	// in output when the cast has no input events.
+	Prompt *regexp.Regexp
+	// MaxDuration is the longest time a cue stays on screen.
+	MaxDuration float64
+}
+
+// FromV2 builds cues from marker labels and from typed commands, which are
+// read from input events, or from prompt lines in output if there is no input.
+func FromV2(h *asciinema.V2Header, events []asciinema.V2Event, opts *Options) []Cue {
+	cues := make([]Cue, 0)
+	for _, e := range events {
+		if label, ok := e.Data.(string); ok && e.Code == "m" && strings.TrimSpace(label) != "" {
+			cues = append(cues, Cue{Start: e.Time.Float64(), Text: label})
+		}
+	}
+
+	if hasInput(events) {
+		cues = append(cues, inputCommands(events)...)
+	} else {
+		cues = append(cues, promptCommands(h, events, opts.Prompt)...)
+	}
+
+	return arrange(cues, opts.MaxDuration)
+}
+
+func hasInput(events []asciinema.V2Event) bool {
+	for _, e := range events {
+		if e.Code == "i" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// arrange sorts cues, joins cues starting together and ends every cue when
+// the next one starts or maxDuration has passed.
+func arrange(cues []Cue, maxDuration float64) []Cue {
+	sort.SliceStable(cues, func(i, j int) bool {
+		return cues[i].Start < cues[j].Start
+	})
+
+	joined := make([]Cue, 0, len(cues))
+	for _, c := range cues {
+		if n := len(joined); n > 0 && joined[n-1].Start == c.Start {
+			joined[n-1].Text += "\n" + c.Text
+			continue
+		}
+
+		joined = append(joined, c)
+	}
+
+	limit := decimal.NewFromFloat(maxDuration)
+	for i := range joined {
+		end := decimal.NewFromFloat(joined[i].Start).Add(limit)
+		if i+1 < len(joined) {
+			end = decimal.Min(end, decimal.NewFromFloat(joined[i+1].Start))
+		}
+
+		joined[i].End = end.InexactFloat64()
+	}
+
+	return joined
+}
+
+// lineEditor replays keys typed on a line, as a shell line editor would.
+type lineEditor struct {
+	line  []rune
+	start float64
+	esc   int
+}
+
+const (
+	escNone = iota
+	escStart
+	escCSI
+	escSS3
+)
+
+// feed applies keys and returns the lines entered with the time typing began.
+func (l *lineEditor) feed(t float64, keys string) []Cue {
+	cues := make([]Cue, 0)
+	for _, r := range keys {
+		switch l.esc {
+		case escStart:
+			switch r {
+			case '[':
+				l.esc = escCSI
+			case 'O':
+				l.esc = escSS3
+			default:
+				l.esc = escNone
+			}
+
+			continue
+		case escCSI:
+			if r >= 0x40 && r <= 0x7e {
+				l.esc = escNone
+			}
+
+			continue
+		case escSS3:
+			l.esc = escNone
+			continue
+		}
+
+		switch {
+		case r == '\r' || r == '\n':
+			if text := strings.TrimSpace(string(l.line)); text != "" {
+				cues = append(cues, Cue{Start: l.start, Text: text})
+			}
+			l.line = l.line[:0]
+		case r == 0x1b:
+			l.esc = escStart
+		case r == 0x7f || r == '\b':
+			if len(l.line) > 0 {
+				l.line = l.line[:len(l.line)-1]
+			}
+		case r == 0x03 || r == 0x15:
+			// NOTE: Ctrl-C discards and Ctrl-U kills the line
+			l.line = l.line[:0]
+		case r == 0x17:
+			// NOTE: Ctrl-W kills the previous word
+			s := strings.TrimRight(string(l.line), " ")
+			l.line = []rune(s[:strings.LastIndex(s, " ")+1])
+		case r < 0x20:
+		default:
+			if len(l.line) == 0 {
+				l.start = t
+			}
+			l.line = append(l.line, r)
+		}
+	}
+
+	return cues
+}
+
+func inputCommands(events []asciinema.V2Event) []Cue {
+	cues := make([]Cue, 0)
+	l := new(lineEditor)
+	for _, e := range events {
+		if keys, ok := e.Data.(string); ok && e.Code == "i" {
+			cues = append(cues, l.feed(e.Time.Float64(), keys)...)
+		}
+	}
+
+	return cues
+}
+
+// promptCommands plays output on a terminal and takes every line that starts
+// with a prompt and ends with a line feed as an entered command.
+func promptCommands(h *asciinema.V2Header, events []asciinema.V2Event, prompt *regexp.Regexp) []Cue {
+	cues := make([]Cue, 0)
+	term := vt.New(h.Width, h.Height)
+
+	var start float64
+	typing := false
+	for _, e := range events {
+		data, ok := e.Data.(string)
+		if !ok || e.Code != "o" {
+			continue
+		}
+
+		for _, seg := range strings.SplitAfter(data, "\n") {
+			text, ok := strings.CutSuffix(seg, "\n")
+			term.WriteString(text)
+
+			cmd := promptCommand(term, prompt)
+			if cmd != "" && !typing {
+				start, typing = e.Time.Float64(), true
+			} else if cmd == "" {
+				typing = false
+			}
+
+			if !ok {
+				continue
+			}
+
+			if typing {
+				cues = append(cues, Cue{Start: start, Text: cmd})
+			}
+			typing = false
+
+			term.WriteString("\n")
+		}
+	}
+
+	return cues
+}
+
+func promptCommand(term *vt.Terminal, prompt *regexp.Regexp) string {
+	line := term.CursorLine()
+	loc := prompt.FindStringIndex(line)
+	if loc == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(line[loc[1]:])
+}
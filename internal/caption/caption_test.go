@@ -0,0 +1,134 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package caption
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromV2(t *testing.T) {
+	type args struct {
+		events []asciinema.V2Event
+		opts   *Options
+	}
+
+	type expected struct {
+		cues []Cue
+	}
+
+	header := &asciinema.V2Header{Version: 2, Width: 20, Height: 4}
+	opts := &Options{Prompt: DefaultPrompt, MaxDuration: 3}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: markers",
+			args: &args{
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "m", Data: "intro"},
+					{Time: "1", Code: "m", Data: ""},
+					{Time: "2", Code: "m", Data: "setup"},
+				},
+				opts: opts,
+			},
+			expected: &expected{
+				cues: []Cue{
+					{Start: 0.5, End: 2, Text: "intro"},
+					{Start: 2, End: 5, Text: "setup"},
+				},
+			},
+		},
+		{
+			name: "happy path: input",
+			args: &args{
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "i", Data: "l"},
+					{Time: "1.1", Code: "i", Data: "x"},
+					{Time: "1.2", Code: "i", Data: "\x7fs"},
+					{Time: "1.3", Code: "i", Data: "\r"},
+					{Time: "1.3", Code: "o", Data: "$ ls\r\n"},
+					{Time: "2", Code: "i", Data: "\x1b[Agit"},
+					{Time: "2.2", Code: "i", Data: "\x03"},
+					{Time: "3", Code: "i", Data: "echo hi there\x17\r"},
+					{Time: "3", Code: "m", Data: "echo"},
+				},
+				opts: opts,
+			},
+			expected: &expected{
+				cues: []Cue{
+					{Start: 1, End: 3, Text: "ls"},
+					{Start: 3, End: 6, Text: "echo\necho hi"},
+				},
+			},
+		},
+		{
+			name: "happy path: prompt",
+			args: &args{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "\x1b[32muser@host\x1b[m:~$ "},
+					{Time: "1", Code: "o", Data: "l"},
+					{Time: "1.2", Code: "o", Data: "s"},
+					{Time: "1.5", Code: "o", Data: "\r\nfile\r\nuser@host:~$ "},
+					{Time: "2", Code: "o", Data: "\r\nuser@host:~$ "},
+					{Time: "5", Code: "o", Data: "exit\r\n"},
+				},
+				opts: opts,
+			},
+			expected: &expected{
+				cues: []Cue{
+					{Start: 1, End: 4, Text: "ls"},
+					{Start: 5, End: 8, Text: "exit"},
+				},
+			},
+		},
+		{
+			name: "happy path: custom prompt",
+			args: &args{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: ">>> 1 + 1\r\n2\r\n$ nope\r\n"},
+				},
+				opts: &Options{Prompt: regexp.MustCompile(`^>>> `), MaxDuration: 1},
+			},
+			expected: &expected{
+				cues: []Cue{
+					{Start: 0, End: 1, Text: "1 + 1"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := FromV2(header, tt.args.events, tt.args.opts)
+
+			// Assert
+			assert.Equal(t, tt.expected.cues, actual)
+		})
+	}
+}
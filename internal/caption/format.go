@@ -0,0 +1,85 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package caption
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type Format string
+
+const (
+	FormatWebVTT Format = "vtt"
+	FormatSRT    Format = "srt"
+)
+
+var (
+	webVTTEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+func Write(w io.Writer, cues []Cue, format Format) error {
+	switch format {
+	case FormatWebVTT:
+		return writeCues(w, cues, "WEBVTT\n\n", '.', webVTTEscaper.Replace)
+	case FormatSRT:
+		return writeCues(w, cues, "", ',', func(s string) string { return s })
+	default:
+		return fmt.Errorf("invalid caption format: %v", format)
+	}
+}
+
+func writeCues(w io.Writer, cues []Cue, header string, sep byte, escape func(string) string) error {
+	var b strings.Builder
+	b.WriteString(header)
+
+	n := 0
+	for _, c := range cues {
+		start, end := timestamp(c.Start, sep), timestamp(c.End, sep)
+		if start == end {
+			continue
+		}
+
+		n++
+		fmt.Fprintf(&b, "%d\n%s --> %s\n", n, start, end)
+		for _, line := range strings.Split(c.Text, "\n") {
+			// NOTE: a blank line would end the cue
+			if strings.TrimSpace(line) != "" {
+				b.WriteString(escape(line) + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// timestamp formats seconds as hh:mm:ss.ttt with the given fraction separator.
+func timestamp(seconds float64, sep byte) string {
+	ms := decimal.NewFromFloat(seconds).Shift(3).Round(0).IntPart()
+
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
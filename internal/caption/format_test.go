@@ -0,0 +1,114 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package caption
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	type args struct {
+		cues   []Cue
+		format Format
+	}
+
+	type expected struct {
+		data string
+		err  error
+	}
+
+	cues := []Cue{
+		{Start: 1.5, End: 3, Text: "ls <dir> && pwd"},
+		{Start: 3, End: 3.0001, Text: "too short"},
+		{Start: 3723.25, End: 3726.25, Text: "intro\n\nsetup"},
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: webvtt",
+			args: &args{cues: cues, format: FormatWebVTT},
+			expected: &expected{
+				data: `WEBVTT
+
+1
+00:00:01.500 --> 00:00:03.000
+ls &lt;dir&gt; &amp;&amp; pwd
+
+2
+01:02:03.250 --> 01:02:06.250
+intro
+setup
+
+`,
+			},
+		},
+		{
+			name: "happy path: srt",
+			args: &args{cues: cues, format: FormatSRT},
+			expected: &expected{
+				data: `1
+00:00:01,500 --> 00:00:03,000
+ls <dir> && pwd
+
+2
+01:02:03,250 --> 01:02:06,250
+intro
+setup
+
+`,
+			},
+		},
+		{
+			name: "edge path: invalid format",
+			args: &args{cues: cues, format: "ass"},
+			expected: &expected{
+				err: fmt.Errorf("invalid caption format: %v", "ass"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			buf := new(bytes.Buffer)
+
+			// Act
+			err := Write(buf, tt.args.cues, tt.args.format)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, buf.String())
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package castdiff
+
+import (
+	"encoding/json"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpDelete
+	OpInsert
+)
+
+// Edit is a step turning a into b; A and B index the events of a and b, and
+// are -1 where the step has no event on that side.
+type Edit struct {
+	Op Op
+	A  int
+	B  int
+}
+
+// Events aligns the events of a and b by code and data with a longest common
+// subsequence. Equal events are told apart by time first, so that deleting one
+// of several equal events is pinned to the right one; time is ignored otherwise.
+func Events(a, b []asciinema.V2Event) []Edit {
+	timed := make(map[string]int)
+	edits := lcs(eventIDs(a, timed, true), eventIDs(b, timed, true))
+
+	ids := make(map[string]int)
+	ia, ib := eventIDs(a, ids, false), eventIDs(b, ids, false)
+
+	// NOTE: realign every run between events equal in time too, ignoring time
+	result := make([]Edit, 0, len(edits))
+	var as, bs []int
+	realign := func() {
+		sa, sb := make([]int, 0, len(as)), make([]int, 0, len(bs))
+		for _, i := range as {
+			sa = append(sa, ia[i])
+		}
+		for _, i := range bs {
+			sb = append(sb, ib[i])
+		}
+
+		for _, e := range lcs(sa, sb) {
+			if e.A >= 0 {
+				e.A = as[e.A]
+			}
+			if e.B >= 0 {
+				e.B = bs[e.B]
+			}
+			result = append(result, e)
+		}
+
+		as, bs = nil, nil
+	}
+
+	for _, e := range edits {
+		switch e.Op {
+		case OpEqual:
+			realign()
+			result = append(result, e)
+		case OpDelete:
+			as = append(as, e.A)
+		case OpInsert:
+			bs = append(bs, e.B)
+		}
+	}
+	realign()
+
+	return result
+}
+
+func eventIDs(events []asciinema.V2Event, ids map[string]int, timed bool) []int {
+	seq := make([]int, 0, len(events))
+	for _, e := range events {
+		data, err := json.Marshal(e.Data)
+		if err != nil {
+			data = nil
+		}
+
+		key := e.Code + "\x00" + string(data)
+		if timed {
+			key += "\x00" + e.Time.Decimal().String()
+		}
+
+		id, ok := ids[key]
+		if !ok {
+			id = len(ids)
+			ids[key] = id
+		}
+
+		seq = append(seq, id)
+	}
+
+	return seq
+}
+
+// lcs computes a shortest edit script with the Myers O(ND) algorithm.
+func lcs(a, b []int) []Edit {
+	n, m := len(a), len(b)
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+	trace := make([][]int, 0)
+
+	d := 0
+	for ; d <= n+m; d++ {
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[off+k] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+
+		// NOTE: keep the diagonals reachable in d steps only
+		trace = append(trace, append(make([]int, 0, 2*d+1), v[off-d:off+d+1]...))
+		if done {
+			break
+		}
+	}
+
+	edits := make([]Edit, 0, n+m)
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+
+		k := x - y
+		var pk int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+
+		px := at(pk)
+		py := px - pk
+
+		// NOTE: the step lands on (mx, my) and a snake of equal events follows
+		mx, my := px+1, py
+		if pk == k+1 {
+			mx, my = px, py+1
+		}
+
+		for x > mx && y > my {
+			x--
+			y--
+			edits = append(edits, Edit{Op: OpEqual, A: x, B: y})
+		}
+
+		if pk == k+1 {
+			edits = append(edits, Edit{Op: OpInsert, A: -1, B: py})
+		} else {
+			edits = append(edits, Edit{Op: OpDelete, A: px, B: -1})
+		}
+		x, y = px, py
+	}
+
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, Edit{Op: OpEqual, A: x, B: y})
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
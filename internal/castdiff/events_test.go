@@ -0,0 +1,169 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package castdiff
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvents(t *testing.T) {
+	type args struct {
+		a []asciinema.V2Event
+		b []asciinema.V2Event
+	}
+
+	type expected struct {
+		edits []Edit
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: empty",
+			args:     &args{a: []asciinema.V2Event{}, b: []asciinema.V2Event{}},
+			expected: &expected{edits: []Edit{}},
+		},
+		{
+			name: "happy path: time is ignored",
+			args: &args{
+				a: []asciinema.V2Event{{Time: "1", Code: "o", Data: "a"}, {Time: "2", Code: "o", Data: "b"}},
+				b: []asciinema.V2Event{{Time: "1.5", Code: "o", Data: "a"}, {Time: "3", Code: "o", Data: "b"}},
+			},
+			expected: &expected{edits: []Edit{
+				{Op: OpEqual, A: 0, B: 0},
+				{Op: OpEqual, A: 1, B: 1},
+			}},
+		},
+		{
+			name: "happy path: time tells equal events apart",
+			args: &args{
+				a: []asciinema.V2Event{{Time: "0.2", Code: "o", Data: "l"}, {Time: "0.3", Code: "o", Data: "l"}},
+				b: []asciinema.V2Event{{Time: "0.3", Code: "o", Data: "l"}},
+			},
+			expected: &expected{edits: []Edit{
+				{Op: OpDelete, A: 0, B: -1},
+				{Op: OpEqual, A: 1, B: 0},
+			}},
+		},
+		{
+			name: "happy path: insert and delete",
+			args: &args{
+				a: []asciinema.V2Event{{Code: "o", Data: "a"}, {Code: "o", Data: "b"}, {Code: "o", Data: "c"}},
+				b: []asciinema.V2Event{{Code: "o", Data: "a"}, {Code: "o", Data: "c"}, {Code: "o", Data: "d"}},
+			},
+			expected: &expected{edits: []Edit{
+				{Op: OpEqual, A: 0, B: 0},
+				{Op: OpDelete, A: 1, B: -1},
+				{Op: OpEqual, A: 2, B: 1},
+				{Op: OpInsert, A: -1, B: 2},
+			}},
+		},
+		{
+			name: "happy path: code matters",
+			args: &args{
+				a: []asciinema.V2Event{{Code: "o", Data: "a"}},
+				b: []asciinema.V2Event{{Code: "i", Data: "a"}},
+			},
+			expected: &expected{edits: []Edit{
+				{Op: OpDelete, A: 0, B: -1},
+				{Op: OpInsert, A: -1, B: 0},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := Events(tt.args.a, tt.args.b)
+
+			// Assert
+			assert.Equal(t, tt.expected.edits, actual)
+		})
+	}
+}
+
+func TestLCS(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func(n int) []int {
+		seq := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			seq = append(seq, r.Intn(4))
+		}
+
+		return seq
+	}
+
+	for i := 0; i < 200; i++ {
+		a, b := random(r.Intn(30)), random(r.Intn(30))
+
+		// Act
+		edits := lcs(a, b)
+
+		// Assert
+		ai, bi, equal := 0, 0, 0
+		for _, e := range edits {
+			switch e.Op {
+			case OpEqual:
+				assert.Equal(t, ai, e.A)
+				assert.Equal(t, bi, e.B)
+				assert.Equal(t, a[e.A], b[e.B])
+				ai++
+				bi++
+				equal++
+			case OpDelete:
+				assert.Equal(t, ai, e.A)
+				ai++
+			case OpInsert:
+				assert.Equal(t, bi, e.B)
+				bi++
+			}
+		}
+		assert.Equal(t, len(a), ai)
+		assert.Equal(t, len(b), bi)
+		assert.Equal(t, lcsLength(a, b), equal)
+	}
+}
+
+func lcsLength(a, b []int) int {
+	dp := make([][]int, 0, len(a)+1)
+	for i := 0; i <= len(a); i++ {
+		dp = append(dp, make([]int, len(b)+1))
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+
+	return dp[len(a)][len(b)]
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package castdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+// HeaderChange is a header field that differs; Old or New is nil where the
+// field is absent.
+type HeaderChange struct {
+	Key string          `json:"key"`
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+// Header compares the header fields of a and b, in the order a has them
+// followed by the fields only b has.
+func Header(a, b *asciinema.V2Header) ([]HeaderChange, error) {
+	aKeys, aFields, err := headerFields(a)
+	if err != nil {
+		return nil, err
+	}
+
+	bKeys, bFields, err := headerFields(b)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]HeaderChange, 0)
+	for _, k := range aKeys {
+		if nv, ok := bFields[k]; !ok {
+			changes = append(changes, HeaderChange{Key: k, Old: aFields[k]})
+		} else if !bytes.Equal(aFields[k], nv) {
+			changes = append(changes, HeaderChange{Key: k, Old: aFields[k], New: nv})
+		}
+	}
+
+	for _, k := range bKeys {
+		if _, ok := aFields[k]; !ok {
+			changes = append(changes, HeaderChange{Key: k, New: bFields[k]})
+		}
+	}
+
+	return changes, nil
+}
+
+func headerFields(h *asciinema.V2Header) ([]string, map[string]json.RawMessage, error) {
+	b, err := h.MarshalJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	keys := make([]string, 0)
+	fields := make(map[string]json.RawMessage)
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		k, ok := t.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid header key: %v", t)
+		}
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+
+		keys = append(keys, k)
+		fields[k] = v
+	}
+
+	return keys, fields, nil
+}
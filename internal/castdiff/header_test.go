@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package castdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeader(t *testing.T) {
+	type args struct {
+		a *asciinema.V2Header
+		b *asciinema.V2Header
+	}
+
+	type expected struct {
+		changes []HeaderChange
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: same",
+			args: &args{
+				a: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Env: map[string]string{"A": "1", "B": "2"}},
+				b: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Env: map[string]string{"B": "2", "A": "1"}},
+			},
+			expected: &expected{changes: []HeaderChange{}},
+		},
+		{
+			name: "happy path: changed, removed and added",
+			args: &args{
+				a: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Title: "demo"},
+				b: &asciinema.V2Header{
+					Version: 2, Width: 100, Height: 24, IdleTimeLimit: 2,
+					Extra: map[string]json.RawMessage{"x-app": json.RawMessage(`"v"`)},
+				},
+			},
+			expected: &expected{changes: []HeaderChange{
+				{Key: "width", Old: json.RawMessage(`80`), New: json.RawMessage(`100`)},
+				{Key: "title", Old: json.RawMessage(`"demo"`)},
+				{Key: "idle_time_limit", New: json.RawMessage(`2`)},
+				{Key: "x-app", New: json.RawMessage(`"v"`)},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := Header(tt.args.a, tt.args.b)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.changes, actual)
+		})
+	}
+}
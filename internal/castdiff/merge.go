@@ -0,0 +1,256 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package castdiff
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+type Side int
+
+const (
+	SideOurs Side = iota
+	SideTheirs
+)
+
+// Ref points at an event of one side.
+type Ref struct {
+	Side  Side
+	Index int
+}
+
+// Hunk is a run of merged events, or a conflict between ours and theirs.
+type Hunk struct {
+	Conflict bool
+	Events   []Ref
+	Ours     []Ref
+	Theirs   []Ref
+}
+
+// sideMap tells, for every base event, where it went on one side, and which
+// events the side inserted before it.
+type sideMap struct {
+	kept    []int
+	inserts [][]int
+}
+
+func mapSide(base, side []asciinema.V2Event) *sideMap {
+	m := &sideMap{
+		kept:    make([]int, 0, len(base)),
+		inserts: make([][]int, len(base)+1),
+	}
+
+	i := 0
+	for _, e := range Events(base, side) {
+		switch e.Op {
+		case OpEqual:
+			m.kept = append(m.kept, e.B)
+			i++
+		case OpDelete:
+			m.kept = append(m.kept, -1)
+			i++
+		case OpInsert:
+			m.inserts[i] = append(m.inserts[i], e.B)
+		}
+	}
+
+	return m
+}
+
+// Merge merges the events of ours and theirs against their common base.
+// Independent insertions, deletions and retimings are combined, and only the
+// events both sides changed differently conflict.
+func Merge(base, ours, theirs []asciinema.V2Event) []Hunk {
+	om, tm := mapSide(base, ours), mapSide(base, theirs)
+
+	hunks := make([]Hunk, 0)
+	take := func(side Side, indexes ...int) {
+		if len(indexes) == 0 {
+			return
+		}
+
+		if n := len(hunks); n == 0 || hunks[n-1].Conflict {
+			hunks = append(hunks, Hunk{})
+		}
+
+		h := &hunks[len(hunks)-1]
+		for _, i := range indexes {
+			h.Events = append(h.Events, Ref{Side: side, Index: i})
+		}
+	}
+
+	conflict := func(o, t []int) {
+		if n := len(hunks); n == 0 || !hunks[n-1].Conflict {
+			hunks = append(hunks, Hunk{Conflict: true})
+		}
+
+		h := &hunks[len(hunks)-1]
+		for _, i := range o {
+			h.Ours = append(h.Ours, Ref{Side: SideOurs, Index: i})
+		}
+
+		for _, i := range t {
+			h.Theirs = append(h.Theirs, Ref{Side: SideTheirs, Index: i})
+		}
+	}
+
+	// NOTE: a replaced event is a deletion followed by insertions, which belong to
+	// the conflict of the deleted event
+	conflicted := false
+	for i := 0; i <= len(base); i++ {
+		o, t := om.inserts[i], tm.inserts[i]
+		switch {
+		case conflicted:
+			conflict(o, t)
+		case len(o) == 0:
+			take(SideTheirs, t...)
+		case len(t) == 0 || sameEvents(ours, o, theirs, t):
+			take(SideOurs, o...)
+		default:
+			conflict(o, t)
+		}
+
+		if i == len(base) {
+			break
+		}
+
+		conflicted = false
+		oi, ti := om.kept[i], tm.kept[i]
+		oChanged := oi < 0 || !ours[oi].Time.Equal(base[i].Time)
+		tChanged := ti < 0 || !theirs[ti].Time.Equal(base[i].Time)
+		switch {
+		case !tChanged:
+			take(SideOurs, kept(oi)...)
+		case !oChanged:
+			take(SideTheirs, kept(ti)...)
+		case oi < 0 && ti < 0:
+		case oi >= 0 && ti >= 0 && ours[oi].Time.Equal(theirs[ti].Time):
+			take(SideOurs, oi)
+		default:
+			conflict(kept(oi), kept(ti))
+			conflicted = true
+		}
+	}
+
+	return hunks
+}
+
+func kept(i int) []int {
+	if i < 0 {
+		return nil
+	}
+
+	return []int{i}
+}
+
+func sameEvents(a []asciinema.V2Event, ai []int, b []asciinema.V2Event, bi []int) bool {
+	if len(ai) != len(bi) {
+		return false
+	}
+
+	for k := range ai {
+		ea, eb := a[ai[k]], b[bi[k]]
+		if !ea.Time.Equal(eb.Time) || ea.Code != eb.Code {
+			return false
+		}
+
+		da, _ := json.Marshal(ea.Data)
+		db, _ := json.Marshal(eb.Data)
+		if !bytes.Equal(da, db) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MergeHeader merges the header fields of ours and theirs against their common
+// base, and returns the keys both sides changed differently, for which ours is
+// kept.
+func MergeHeader(base, ours, theirs *asciinema.V2Header) (*asciinema.V2Header, []string, error) {
+	_, bFields, err := headerFields(base)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	oKeys, oFields, err := headerFields(ours)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tKeys, tFields, err := headerFields(theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := append(make([]string, 0, len(oKeys)+len(tKeys)), oKeys...)
+	for _, k := range tKeys {
+		if _, ok := oFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	conflicts := make([]string, 0)
+	buf := new(bytes.Buffer)
+	buf.WriteByte('{')
+	for _, k := range keys {
+		b, o, t := bFields[k], oFields[k], tFields[k]
+
+		var v json.RawMessage
+		switch {
+		case bytes.Equal(o, b):
+			v = t
+		case bytes.Equal(t, b), bytes.Equal(o, t):
+			v = o
+		default:
+			v = o
+			conflicts = append(conflicts, k)
+		}
+
+		if v == nil {
+			continue
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	h := new(asciinema.V2Header)
+	if err := json.Unmarshal(buf.Bytes(), h); err != nil {
+		return nil, nil, err
+	}
+
+	return h, conflicts, nil
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package castdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	type args struct {
+		base   []asciinema.V2Event
+		ours   []asciinema.V2Event
+		theirs []asciinema.V2Event
+	}
+
+	type expected struct {
+		hunks []Hunk
+	}
+
+	base := []asciinema.V2Event{
+		{Time: "0.1", Code: "o", Data: "a"},
+		{Time: "0.2", Code: "o", Data: "b"},
+		{Time: "0.3", Code: "o", Data: "c"},
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: unchanged",
+			args: &args{base: base, ours: base, theirs: base},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}, {SideOurs, 1}, {SideOurs, 2}}},
+			}},
+		},
+		{
+			name: "happy path: typo fix and retiming",
+			args: &args{
+				base: base,
+				ours: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "o", Data: "B"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "o", Data: "b"},
+					{Time: "0.1", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideTheirs, 0}, {SideOurs, 1}, {SideTheirs, 2}}},
+			}},
+		},
+		{
+			name: "happy path: independent deletions and insertions",
+			args: &args{
+				base: base,
+				ours: []asciinema.V2Event{
+					{Time: "0.2", Code: "o", Data: "b"},
+					{Time: "0.3", Code: "o", Data: "c"},
+					{Time: "0.4", Code: "m", Data: "end"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.05", Code: "i", Data: "x"},
+					{Time: "0.2", Code: "o", Data: "b"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideTheirs, 1}, {SideOurs, 0}, {SideOurs, 2}}},
+			}},
+		},
+		{
+			name: "happy path: deletions of equal events",
+			args: &args{
+				base: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "e"},
+					{Time: "0.2", Code: "o", Data: "l"},
+					{Time: "0.3", Code: "o", Data: "l"},
+					{Time: "0.4", Code: "o", Data: "o"},
+				},
+				ours: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "e"},
+					{Time: "0.3", Code: "o", Data: "l"},
+					{Time: "0.4", Code: "o", Data: "o"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "e"},
+					{Time: "0.2", Code: "o", Data: "l"},
+					{Time: "0.4", Code: "o", Data: "o"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}, {SideOurs, 2}}},
+			}},
+		},
+		{
+			name: "happy path: same change on both sides",
+			args: &args{
+				base: base,
+				ours: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.9", Code: "o", Data: "b"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.9", Code: "o", Data: "b"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}, {SideOurs, 1}}},
+			}},
+		},
+		{
+			name: "happy path: conflicting retimings",
+			args: &args{
+				base: base,
+				ours: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.4", Code: "o", Data: "b"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.6", Code: "o", Data: "b"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}}},
+				{Conflict: true, Ours: []Ref{{SideOurs, 1}}, Theirs: []Ref{{SideTheirs, 1}}},
+				{Events: []Ref{{SideOurs, 2}}},
+			}},
+		},
+		{
+			name: "happy path: deletion against retiming",
+			args: &args{
+				base: base,
+				ours: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.6", Code: "o", Data: "b"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}}},
+				{Conflict: true, Theirs: []Ref{{SideTheirs, 1}}},
+				{Events: []Ref{{SideOurs, 1}}},
+			}},
+		},
+		{
+			name: "happy path: replacement against retiming",
+			args: &args{
+				base: base,
+				ours: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "o", Data: "B"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+				theirs: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.6", Code: "o", Data: "b"},
+					{Time: "0.3", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}}},
+				{Conflict: true, Ours: []Ref{{SideOurs, 1}}, Theirs: []Ref{{SideTheirs, 1}}},
+				{Events: []Ref{{SideOurs, 2}}},
+			}},
+		},
+		{
+			name: "happy path: different insertions at the same place",
+			args: &args{
+				base:   base,
+				ours:   append(append([]asciinema.V2Event{}, base...), asciinema.V2Event{Time: "1", Code: "o", Data: "x"}),
+				theirs: append(append([]asciinema.V2Event{}, base...), asciinema.V2Event{Time: "1", Code: "o", Data: "y"}),
+			},
+			expected: &expected{hunks: []Hunk{
+				{Events: []Ref{{SideOurs, 0}, {SideOurs, 1}, {SideOurs, 2}}},
+				{Conflict: true, Ours: []Ref{{SideOurs, 3}}, Theirs: []Ref{{SideTheirs, 3}}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := Merge(tt.args.base, tt.args.ours, tt.args.theirs)
+
+			// Assert
+			assert.Equal(t, tt.expected.hunks, actual)
+		})
+	}
+}
+
+func TestMergeHeader(t *testing.T) {
+	type args struct {
+		base   *asciinema.V2Header
+		ours   *asciinema.V2Header
+		theirs *asciinema.V2Header
+	}
+
+	type expected struct {
+		header    *asciinema.V2Header
+		conflicts []string
+	}
+
+	base := &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Title: "demo", Command: "bash"}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: independent changes",
+			args: &args{
+				base:   base,
+				ours:   &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Title: "Demo", Command: "bash"},
+				theirs: &asciinema.V2Header{Version: 2, Width: 100, Height: 24, Title: "demo", IdleTimeLimit: 2},
+			},
+			expected: &expected{
+				header:    &asciinema.V2Header{Version: 2, Width: 100, Height: 24, Title: "Demo", IdleTimeLimit: 2},
+				conflicts: []string{},
+			},
+		},
+		{
+			name: "happy path: conflicting changes keep ours",
+			args: &args{
+				base: base,
+				ours: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Title: "Ours", Command: "bash"},
+				theirs: &asciinema.V2Header{
+					Version: 2, Width: 80, Height: 24, Title: "Theirs", Command: "bash",
+					Extra: map[string]json.RawMessage{"x-app": json.RawMessage(`"v"`)},
+				},
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{
+					Version: 2, Width: 80, Height: 24, Title: "Ours", Command: "bash",
+					Extra: map[string]json.RawMessage{"x-app": json.RawMessage(`"v"`)},
+				},
+				conflicts: []string{"title"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, conflicts, err := MergeHeader(tt.args.base, tt.args.ours, tt.args.theirs)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected.header, actual)
+			assert.Equal(t, tt.expected.conflicts, conflicts)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/spf13/cobra"
+)
+
+type applyFlags struct {
+	batchFlags
+	durationFlags
+	precisionFlags
+}
+
+func newApplyCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(applyFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "apply PIPELINE [FILE]...",
+		Short: "Run a pipeline of transforms over asciicast v2",
+		Long: `Run a pipeline of transforms over asciicast v2.
+
+The pipeline is a YAML file listing transforms in order, each with its parameters:
+
+  transforms:
+    - type: idle-limit   # cap pauses
+      max: 2s
+    - type: redact       # replace matches in output and input data
+      pattern: 'ghp_[0-9A-Za-z]+'
+      replacement: '***'
+      codes: [o, i]
+    - type: speed        # play pauses faster, between markers (from, to) or times (start, end)
+      factor: 4
+      from: install
+      to: installed
+    - type: theme        # set the header theme by bundled name or file
+      name: dracula
+    - type: delta        # turn event times into Δ times (accumulate does the inverse)
+    - type: plugin       # run an external executable speaking the plugin protocol
+      command: ./plugins/trim-prompt
+      args: [--fast]
+    - type: eval         # run a script over every event, as the eval command does
+      script: if code == "o" && delta > 3 { delta = 1 }
+    - type: coalesce     # merge output or input events following within threshold
+      threshold: 50ms
+    - type: explode      # split output and input events into characters or lines
+      unit: char
+      interval: 100ms
+
+Times are given in seconds or as durations such as "500ms".
+`,
+		Example: `deltascii apply pipeline.yaml -i ascii.cast -o edited.cast
+deltascii apply pipeline.yaml docs/casts/*.cast --in-place`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := transform.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
+			}
+			if err := flags.checkPrecision(); err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args[1:], func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				t := transform.Chain(p.Transform(job.stderr), transform.Precision(flags.precision), transform.Duration(mode))
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(r, buf, job.stderr, t); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+	flags.registerPrecision(cmd.Command)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyCommand(t *testing.T) {
+	applied, _ := os.ReadFile("testdata/pipeline.cast")
+
+	type args struct {
+		pipeline string
+		input    string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				pipeline: "testdata/pipeline.yaml",
+				input:    "testdata/test.cast",
+			},
+			expected: &expected{
+				data: applied,
+			},
+		},
+		{
+			name: "edge path: pipeline not exist",
+			args: &args{
+				pipeline: "testdata/not-exist/pipeline.yaml",
+				input:    "testdata/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				pipeline: "testdata/pipeline.yaml",
+				input:    "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newApplyCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs([]string{tt.args.pipeline, "--input", tt.args.input, "--output", "-"})
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
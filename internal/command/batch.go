@@ -0,0 +1,290 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// batchFlags holds the input and output flags of commands converting one file
+// into another, either a single file with --output or many files at once.
+type batchFlags struct {
+	inputs    []string
+	output    string
+	inPlace   bool
+	outputDir string
+	suffix    string
+	jobs      int
+}
+
+// register adds the input and output flags with the given usages, and suffix
+// is the default extension of outputs named after inputs.
+func (f *batchFlags) register(cmd *cobra.Command, input, output, suffix string) {
+	cmd.Flags().StringArrayVarP(&f.inputs, "input", "i", nil, input)
+	cmd.Flags().StringVarP(&f.output, "output", "o", "", output)
+	cmd.Flags().StringVar(&f.outputDir, "output-dir", "", "write outputs named after inputs into directory")
+	cmd.Flags().StringVar(&f.suffix, "suffix", suffix, "extension replacing input extension in output names")
+	cmd.Flags().IntVarP(&f.jobs, "jobs", "j", 0, "number of files processed concurrently (0 uses the number of CPUs)")
+
+	cmd.MarkFlagsMutuallyExclusive("output", "output-dir")
+	cmd.MarkFlagsMutuallyExclusive("output", "suffix")
+}
+
+// registerInPlace adds --in-place for commands whose output format is the same
+// as the input format.
+func (f *batchFlags) registerInPlace(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.inPlace, "in-place", false, "overwrite input files")
+
+	cmd.MarkFlagsMutuallyExclusive("in-place", "output")
+	cmd.MarkFlagsMutuallyExclusive("in-place", "output-dir")
+	cmd.MarkFlagsMutuallyExclusive("in-place", "suffix")
+}
+
+// batch reports whether outputs are named after inputs rather than given by --output.
+func (f *batchFlags) batch(cmd *cobra.Command) bool {
+	return f.inPlace || f.outputDir != "" || cmd.Flags().Changed("suffix")
+}
+
+// batchJob is the conversion of one input file into one output file.
+type batchJob struct {
+	input  string
+	output string
+	stderr io.Writer
+}
+
+// run calls fn for every input given by --input and args. Inputs are globbed,
+// and in batch mode they are processed concurrently, followed by a summary of
+// every file on stderr.
+func (f *batchFlags) run(cmd *cobra.Command, args []string, fn func(job *batchJob) error) error {
+	if f.jobs < 0 {
+		return fmt.Errorf("invalid jobs: %v", f.jobs)
+	}
+
+	inputs, err := expandInputs(append(append(make([]string, 0, len(f.inputs)+len(args)), f.inputs...), args...))
+	if err != nil {
+		return err
+	}
+
+	if len(inputs) == 0 {
+		return errors.New("no input files")
+	}
+
+	if !f.batch(cmd) {
+		if len(inputs) > 1 {
+			return errors.New("multiple inputs require --output-dir, --suffix or --in-place")
+		}
+
+		if f.output == "" {
+			return errors.New(`required flag(s) "output" not set`)
+		}
+
+		return fn(&batchJob{input: inputs[0], output: f.output, stderr: cmd.ErrOrStderr()})
+	}
+
+	jobs, err := f.batchJobs(inputs)
+	if err != nil {
+		return err
+	}
+
+	if f.outputDir != "" {
+		if err := os.MkdirAll(f.outputDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	n := f.jobs
+	if n == 0 {
+		n = runtime.NumCPU()
+	}
+
+	errs := make([]error, len(jobs))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(n, len(jobs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				errs[i] = fn(jobs[i])
+			}
+		}()
+	}
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	// NOTE: print after all jobs so that lines of files running concurrently never interleave
+	w := cmd.ErrOrStderr()
+	failed := 0
+	for i, job := range jobs {
+		if buf, ok := job.stderr.(*bytes.Buffer); ok {
+			writePrefixed(w, job.input, buf)
+		}
+
+		if errs[i] != nil {
+			failed++
+			fmt.Fprintf(w, "failed: %s: %v\n", job.input, errs[i])
+		} else {
+			fmt.Fprintf(w, "ok: %s -> %s\n", job.input, job.output)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(jobs))
+	}
+
+	return nil
+}
+
+func (f *batchFlags) batchJobs(inputs []string) ([]*batchJob, error) {
+	jobs := make([]*batchJob, 0, len(inputs))
+	outputs := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		if input == "-" {
+			return nil, errors.New(`"-" cannot be used with --output-dir, --suffix or --in-place`)
+		}
+
+		output := input
+		if !f.inPlace {
+			dir, base := filepath.Split(input)
+			if f.outputDir != "" {
+				dir = f.outputDir
+			}
+			output = filepath.Join(dir, replaceExt(base, f.suffix))
+
+			if filepath.Clean(output) == filepath.Clean(input) {
+				return nil, fmt.Errorf("output overwrites input: %v", input)
+			}
+		}
+
+		key := filepath.Clean(output)
+		if other, ok := outputs[key]; ok {
+			return nil, fmt.Errorf("duplicate output: %v (from %v and %v)", output, other, input)
+		}
+		outputs[key] = input
+
+		jobs = append(jobs, &batchJob{input: input, output: output, stderr: new(bytes.Buffer)})
+	}
+
+	return jobs, nil
+}
+
+// inputFlags holds the input flag of commands reporting on many files at once.
+type inputFlags struct {
+	inputs []string
+}
+
+func (f *inputFlags) register(cmd *cobra.Command, input string) {
+	cmd.Flags().StringArrayVarP(&f.inputs, "input", "i", nil, input)
+}
+
+// each calls fn for every input given by --input and args, globbed. With several
+// inputs, warnings are prefixed with the file name, and errors too.
+func (f *inputFlags) each(cmd *cobra.Command, args []string, fn func(input string, r io.Reader, errW io.Writer, multi bool) error) error {
+	inputs, err := expandInputs(append(append(make([]string, 0, len(f.inputs)+len(args)), f.inputs...), args...))
+	if err != nil {
+		return err
+	}
+
+	if len(inputs) == 0 {
+		return errors.New("no input files")
+	}
+
+	if len(inputs) == 1 {
+		r, err := readInput(cmd, inputs[0])
+		if err != nil {
+			return err
+		}
+
+		return fn(inputs[0], r, cmd.ErrOrStderr(), false)
+	}
+
+	for _, input := range inputs {
+		r, err := readInput(cmd, input)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+
+		buf := new(bytes.Buffer)
+		err = fn(input, r, buf, true)
+		writePrefixed(cmd.ErrOrStderr(), input, buf)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+	}
+
+	return nil
+}
+
+// writePrefixed copies every line of buf to w, prefixed with the file name.
+func writePrefixed(w io.Writer, input string, buf *bytes.Buffer) {
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line != "" {
+			fmt.Fprintf(w, "%s: %s", input, line)
+		}
+	}
+}
+
+// expandInputs expands glob patterns, keeping other names as they are and
+// dropping duplicates.
+func expandInputs(patterns []string) ([]string, error) {
+	inputs := make([]string, 0, len(patterns))
+	seen := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		names := []string{p}
+		if strings.ContainsAny(p, `*?[`) {
+			matches, err := filepath.Glob(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob: %v", p)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match: %v", p)
+			}
+			names = matches
+		}
+
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				inputs = append(inputs, name)
+			}
+		}
+	}
+
+	return inputs, nil
+}
+
+// replaceExt replaces the extension of name with ext, where ext may be empty.
+func replaceExt(name, ext string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ext
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchFlags(t *testing.T) {
+	cast, _ := os.ReadFile("testdata/test.cast")
+	delta, _ := os.ReadFile("testdata/test.delta.cast")
+
+	type args struct {
+		files map[string][]byte
+		args  []string
+	}
+
+	type expected struct {
+		files  map[string][]byte
+		stderr string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: output dir",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast, "b.cast": cast},
+				args:  []string{"-i", "{dir}/*.cast", "--output-dir", "{dir}/out"},
+			},
+			expected: &expected{
+				files:  map[string][]byte{"a.cast": cast, "out/a.cast": delta, "out/b.cast": delta},
+				stderr: "ok: {dir}/a.cast -> {dir}/out/a.cast\nok: {dir}/b.cast -> {dir}/out/b.cast\n",
+			},
+		},
+		{
+			name: "happy path: suffix",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast},
+				args:  []string{"{dir}/a.cast", "--suffix", ".delta.cast", "-j", "1"},
+			},
+			expected: &expected{
+				files:  map[string][]byte{"a.cast": cast, "a.delta.cast": delta},
+				stderr: "ok: {dir}/a.cast -> {dir}/a.delta.cast\n",
+			},
+		},
+		{
+			name: "happy path: in place",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast, "b.cast": cast},
+				args:  []string{"{dir}/a.cast", "-i", "{dir}/b.cast", "--in-place"},
+			},
+			expected: &expected{
+				files:  map[string][]byte{"a.cast": delta, "b.cast": delta},
+				stderr: "ok: {dir}/b.cast -> {dir}/b.cast\nok: {dir}/a.cast -> {dir}/a.cast\n",
+			},
+		},
+		{
+			name: "edge path: file failed",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast, "b.cast": []byte("broken\n")},
+				args:  []string{"{dir}/*.cast", "--output-dir", "{dir}/out"},
+			},
+			expected: &expected{
+				files:  map[string][]byte{"out/a.cast": delta},
+				stderr: "ok: {dir}/a.cast -> {dir}/out/a.cast\nfailed: {dir}/b.cast: invalid character 'b' looking for beginning of value\nError: 1 of 2 files failed\n",
+				err:    errors.New("1 of 2 files failed"),
+			},
+		},
+		{
+			name: "edge path: multiple inputs without destination",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast, "b.cast": cast},
+				args:  []string{"{dir}/a.cast", "{dir}/b.cast", "-o", "-"},
+			},
+			expected: &expected{
+				err: errors.New("multiple inputs require --output-dir, --suffix or --in-place"),
+			},
+		},
+		{
+			name: "edge path: output not set",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast},
+				args:  []string{"{dir}/a.cast"},
+			},
+			expected: &expected{
+				err: errors.New(`required flag(s) "output" not set`),
+			},
+		},
+		{
+			name: "edge path: output overwrites input",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast},
+				args:  []string{"{dir}/a.cast", "--suffix", ".cast"},
+			},
+			expected: &expected{
+				err: errors.New("output overwrites input: {dir}/a.cast"),
+			},
+		},
+		{
+			name: "edge path: stdin in batch mode",
+			args: &args{
+				args: []string{"-", "--output-dir", "{dir}/out"},
+			},
+			expected: &expected{
+				err: errors.New(`"-" cannot be used with --output-dir, --suffix or --in-place`),
+			},
+		},
+		{
+			name: "edge path: no files match",
+			args: &args{
+				args: []string{"{dir}/*.cast", "--output-dir", "{dir}/out"},
+			},
+			expected: &expected{
+				err: errors.New("no files match: {dir}/*.cast"),
+			},
+		},
+		{
+			name: "edge path: invalid jobs",
+			args: &args{
+				files: map[string][]byte{"a.cast": cast},
+				args:  []string{"{dir}/a.cast", "--output-dir", "{dir}/out", "-j", "-1"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid jobs: %v", -1),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			dir := t.TempDir()
+			for name, data := range tt.args.files {
+				_ = os.WriteFile(filepath.Join(dir, name), data, 0o644)
+			}
+
+			args := make([]string, 0, len(tt.args.args))
+			for _, a := range tt.args.args {
+				args = append(args, strings.ReplaceAll(a, "{dir}", dir))
+			}
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newDeltaCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(args)
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			for name, data := range tt.expected.files {
+				actual, _ := os.ReadFile(filepath.Join(dir, name))
+				assert.Equal(t, string(data), string(actual), name)
+			}
+
+			if tt.expected.stderr != "" {
+				assert.Equal(t, strings.ReplaceAll(tt.expected.stderr, "{dir}", dir), stderr.String())
+			}
+
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, errors.New(strings.ReplaceAll(tt.expected.err.Error(), "{dir}", dir)), err)
+			}
+		})
+	}
+}
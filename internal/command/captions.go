@@ -0,0 +1,114 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/caption"
+	"github.com/spf13/cobra"
+)
+
+type captionsFlags struct {
+	batchFlags
+	format        string
+	prompt        string
+	maxDuration   float64
+	idleTimeLimit float64
+}
+
+func newCaptionsCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(captionsFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "captions [FILE]...",
+		Short: "Generate WebVTT or SRT captions from asciicast v2",
+		Long: `Generate WebVTT or SRT captions from asciicast v2.
+
+Cues come from marker labels and from commands typed in input events.
+If the cast has no input events, commands are read from output lines starting with a prompt.
+Cue times follow the same idle time limit as "export frames" and "export avi", so the cues line up with the video.
+`,
+		Example: `deltascii captions -i ascii.cast -o ascii.vtt
+deltascii captions -i ascii.cast -o ascii.srt --format srt --prompt '^\$ '`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prompt, err := regexp.Compile(flags.prompt)
+			if err != nil {
+				return fmt.Errorf("invalid prompt: %v", flags.prompt)
+			}
+
+			if flags.maxDuration <= 0 {
+				return fmt.Errorf("invalid max duration: %v", flags.maxDuration)
+			}
+
+			if !cmd.Flags().Changed("suffix") {
+				flags.suffix = "." + flags.format
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := asciinema.ReadV2(r)
+				if err != nil {
+					return err
+				}
+				printWarnings(job.stderr, h)
+
+				events, err = limitIdleTime(h, events, idleTimeLimit(cmd, h, flags.idleTimeLimit))
+				if err != nil {
+					return err
+				}
+
+				cues := caption.FromV2(h, events, &caption.Options{Prompt: prompt, MaxDuration: flags.maxDuration})
+
+				buf := new(bytes.Buffer)
+				if err := caption.Write(buf, cues, caption.Format(flags.format)); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output caption file or "-" (write to stdout)`, ".vtt")
+
+	cmd.Flags().StringVar(&flags.format, "format", string(caption.FormatWebVTT), `caption format ("vtt" or "srt")`)
+	cmd.Flags().StringVar(&flags.prompt, "prompt", caption.DefaultPrompt.String(), "regular expression matching the prompt at the start of an output line")
+	cmd.Flags().Float64Var(&flags.maxDuration, "max-duration", 5, "longest time in seconds a cue stays on screen")
+	cmd.Flags().Float64Var(&flags.idleTimeLimit, "idle-time-limit", 0, "cap idle time in seconds (default the header idle_time_limit, 0 disables)")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
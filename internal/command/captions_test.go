@@ -0,0 +1,164 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptionsCommand(t *testing.T) {
+	type args struct {
+		input string
+		flags []string
+	}
+
+	type expected struct {
+		data  string
+		errIs error
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: webvtt",
+			args: &args{
+				input: "testdata/captions.cast",
+				flags: []string{},
+			},
+			expected: &expected{
+				data: `WEBVTT
+
+1
+00:00:00.500 --> 00:00:01.000
+Listing files
+
+2
+00:00:01.000 --> 00:00:03.500
+ls
+
+3
+00:00:03.500 --> 00:00:08.500
+exit
+
+`,
+			},
+		},
+		{
+			name: "happy path: srt without idle time limit",
+			args: &args{
+				input: "testdata/captions.cast",
+				flags: []string{"--format", "srt", "--idle-time-limit", "0", "--max-duration", "1"},
+			},
+			expected: &expected{
+				data: `1
+00:00:00,500 --> 00:00:01,000
+Listing files
+
+2
+00:00:01,000 --> 00:00:02,000
+ls
+
+3
+00:00:06,500 --> 00:00:07,500
+exit
+
+`,
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+		{
+			name: "edge path: invalid format",
+			args: &args{
+				input: "testdata/captions.cast",
+				flags: []string{"--format", "ass"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid caption format: %v", "ass"),
+			},
+		},
+		{
+			name: "edge path: invalid prompt",
+			args: &args{
+				input: "testdata/captions.cast",
+				flags: []string{"--prompt", "("},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid prompt: %v", "("),
+			},
+		},
+		{
+			name: "edge path: invalid max duration",
+			args: &args{
+				input: "testdata/captions.cast",
+				flags: []string{"--max-duration", "0"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid max duration: %v", 0.0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newCaptionsCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input, "--output", "-"}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, stdout.String())
+				assert.NoError(t, err)
+			} else if tt.expected.errIs != nil {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
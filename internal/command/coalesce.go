@@ -0,0 +1,171 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/spf13/cobra"
+)
+
+type coalesceFlags struct {
+	batchFlags
+	durationFlags
+	threshold time.Duration
+}
+
+func newCoalesceCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(coalesceFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "coalesce [FILE]...",
+		Short: "Merge consecutive events of asciicast v2",
+		Long: `Merge consecutive events of asciicast v2.
+
+An output or input event following one of the same code by less than the
+threshold is merged into it, such as characters echoed one by one. The merged
+event keeps the time of the first one. Data is only joined, so neither UTF-8
+characters nor escape sequences are split. Markers and resizes are kept as is.
+`,
+		Example: `deltascii coalesce -i ascii.cast -o ascii.cast --threshold 50ms
+deltascii coalesce docs/casts/*.cast --in-place`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.threshold <= 0 {
+				return fmt.Errorf("invalid threshold: %v", flags.threshold)
+			}
+
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				t := transform.Chain(transform.Coalesce(flags.threshold.Seconds()), transform.Duration(mode))
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(r, buf, job.stderr, t); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+
+	cmd.Flags().DurationVar(&flags.threshold, "threshold", 50*time.Millisecond, "merge events following by less than this")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type explodeFlags struct {
+	batchFlags
+	durationFlags
+	precisionFlags
+	unit     string
+	interval time.Duration
+}
+
+func newExplodeCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(explodeFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "explode [FILE]...",
+		Short: "Split events of asciicast v2 into characters or lines",
+		Long: `Split events of asciicast v2 into characters or lines, the inverse of coalesce.
+
+Output and input events are split into characters or lines, one every interval,
+as if typed. If they do not fit before the next event, they are spaced evenly
+up to it, so that the events after them keep their times. A UTF-8 character is
+never split, and an escape sequence stays whole with the character after it.
+`,
+		Example: `deltascii explode -i ascii.cast -o typed.cast --interval 80ms
+deltascii explode -i ascii.cast -o lines.cast --unit line`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			unit, err := transform.ParseExplodeUnit(flags.unit)
+			if err != nil {
+				return err
+			}
+			if flags.interval <= 0 {
+				return fmt.Errorf("invalid interval: %v", flags.interval)
+			}
+
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
+			}
+			if err := flags.checkPrecision(); err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				t := transform.Chain(transform.Explode(unit, flags.interval.Seconds()), transform.Precision(flags.precision), transform.Duration(mode))
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(r, buf, job.stderr, t); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+	flags.registerPrecision(cmd.Command)
+
+	cmd.Flags().StringVar(&flags.unit, "unit", string(transform.ExplodeChar), "split into characters (char) or lines (line)")
+	cmd.Flags().DurationVar(&flags.interval, "interval", 100*time.Millisecond, "time between the pieces of an event")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
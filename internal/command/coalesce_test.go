@@ -0,0 +1,198 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceCommand(t *testing.T) {
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				args: []string{"--threshold", "250ms"},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}}
+[0, "o", "hel"]
+[0.6, "o", "l"]
+[1, "o", "o"]
+[1.5, "o", " "]
+[2.1, "o", "w"]
+[2.8, "o", "o"]
+[3.6, "o", "r"]
+[4.5, "o", "l"]
+[5.5, "o", "d"]
+`),
+			},
+		},
+		{
+			name: "edge path: invalid threshold",
+			args: &args{
+				args: []string{"--threshold", "-1s"},
+			},
+			expected: &expected{
+				err: errors.New("invalid threshold: -1s"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newCoalesceCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append(tt.args.args, "--input", "testdata/test.cast", "--output", "-"))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
+
+func TestExplodeCommand(t *testing.T) {
+	cast := `{"version":2,"width":80,"height":24,"duration":1}
+[0,"o","$ "]
+[1,"o","ls\r\n"]
+`
+
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: char",
+			args: &args{
+				args: []string{"--interval", "300ms"},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"duration":1.6}
+[0,"o","$"]
+[0.3,"o"," "]
+[1,"o","l"]
+[1.3,"o","s"]
+[1.6,"o","\r\n"]
+`),
+			},
+		},
+		{
+			name: "happy path: char squeezed",
+			args: &args{
+				args: []string{"--interval", "1s", "--precision", "3"},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"duration":3}
+[0,"o","$"]
+[0.5,"o"," "]
+[1,"o","l"]
+[2,"o","s"]
+[3,"o","\r\n"]
+`),
+			},
+		},
+		{
+			name: "happy path: line",
+			args: &args{
+				args: []string{"--unit", "line"},
+			},
+			expected: &expected{
+				data: []byte(cast),
+			},
+		},
+		{
+			name: "edge path: invalid unit",
+			args: &args{
+				args: []string{"--unit", "word"},
+			},
+			expected: &expected{
+				err: errors.New("invalid unit: word"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := strings.NewReader(cast)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newExplodeCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append(tt.args.args, "--input", "-", "--output", "-"))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
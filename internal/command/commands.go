@@ -28,7 +28,7 @@ import (
 	"os"
 
 	"github.com/Aton-Kish/deltascii/internal/asciinema"
-	"github.com/shopspring/decimal"
+	"github.com/Aton-Kish/deltascii/internal/transform"
 	"github.com/spf13/cobra"
 )
 
@@ -44,8 +44,45 @@ func Register() *xcommand {
 	rootCmd := newRootCommand()
 	deltaCmd := newDeltaCommand()
 	accCmd := newAccumulateCommand()
-
-	rootCmd.AddCommand(deltaCmd.Command, accCmd.Command)
+	infoCmd := newInfoCommand()
+	headerCmd := newHeaderCommand()
+	themeCmd := newThemeCommand()
+	importCmd := newImportCommand()
+	exportCmd := newExportCommand()
+	captionsCmd := newCaptionsCommand()
+	diffCmd := newDiffCommand()
+	mergeCmd := newMergeCommand()
+	gitTextconvCmd := newGitTextconvCommand()
+	applyCmd := newApplyCommand()
+	evalCmd := newEvalCommand()
+	convertCmd := newConvertCommand()
+	validateCmd := newValidateCommand()
+	quantizeCmd := newQuantizeCommand()
+	coalesceCmd := newCoalesceCommand()
+	explodeCmd := newExplodeCommand()
+	synthCmd := newSynthCommand()
+
+	rootCmd.AddCommand(
+		deltaCmd.Command,
+		accCmd.Command,
+		infoCmd.Command,
+		headerCmd.Command,
+		themeCmd.Command,
+		importCmd.Command,
+		exportCmd.Command,
+		captionsCmd.Command,
+		diffCmd.Command,
+		mergeCmd.Command,
+		gitTextconvCmd.Command,
+		applyCmd.Command,
+		evalCmd.Command,
+		convertCmd.Command,
+		validateCmd.Command,
+		quantizeCmd.Command,
+		coalesceCmd.Command,
+		explodeCmd.Command,
+		synthCmd.Command,
+	)
 	rootCmd.InitDefaultCompletionCmd()
 
 	return rootCmd
@@ -70,8 +107,9 @@ func newRootCommand(optFns ...func(o *options)) *xcommand {
 }
 
 type deltaFlags struct {
-	input  string
-	output string
+	batchFlags
+	precisionFlags
+	force bool
 }
 
 func newDeltaCommand(optFns ...func(o *options)) *xcommand {
@@ -80,48 +118,49 @@ func newDeltaCommand(optFns ...func(o *options)) *xcommand {
 	flags := new(deltaFlags)
 
 	cmd := newCommand(&cobra.Command{
-		Use:     "Δ",
+		Use:     "Δ [FILE]...",
 		Aliases: []string{"delta"},
 		Short:   "ΔSCII(n) = ASCII(n) - ASCII(n-1)",
-		Args:    cobra.NoArgs,
+		Args:    cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var r io.Reader
-			if flags.input == "-" {
-				r = cmd.InOrStdin()
-			} else {
-				data, err := os.ReadFile(flags.input)
+			if err := flags.checkPrecision(); err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
 				if err != nil {
 					return err
 				}
 
-				r = bytes.NewReader(data)
-			}
-
-			buf := new(bytes.Buffer)
-			if err := convertASCIICast(r, buf, deltaFn); err != nil {
-				return err
-			}
-
-			if flags.output == "-" {
-				if _, err := fmt.Fprint(cmd.OutOrStdout(), buf.String()); err != nil {
+				data, err := io.ReadAll(r)
+				if err != nil {
 					return err
 				}
-			} else {
-				if err := os.WriteFile(flags.output, buf.Bytes(), 0o644); err != nil {
+
+				if !flags.force {
+					if err := checkFormat(data, asciinema.FormatV2, job.stderr); err != nil {
+						return err
+					}
+				}
+
+				t := transform.Chain(transform.Precision(flags.precision), transform.Delta())
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(bytes.NewReader(data), buf, job.stderr, t); err != nil {
 					return err
 				}
-			}
 
-			return nil
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
 		},
 		SilenceUsage: true,
 	})
 
-	cmd.Flags().StringVarP(&flags.input, "input", "i", "", `input asciicast v2 file or "-" (read from stdin)`)
-	_ = cmd.MarkFlagRequired("input")
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output Δ-asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerPrecision(cmd.Command)
 
-	cmd.Flags().StringVarP(&flags.output, "output", "o", "", `output Δ-asciicast v2 file or "-" (write to stdout)`)
-	_ = cmd.MarkFlagRequired("output")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "convert even if the input looks already converted")
 
 	cmd.SetIn(opts.stdio.in)
 	cmd.SetOutput(opts.stdio.out)
@@ -131,8 +170,10 @@ func newDeltaCommand(optFns ...func(o *options)) *xcommand {
 }
 
 type accumulateFlags struct {
-	input  string
-	output string
+	batchFlags
+	durationFlags
+	precisionFlags
+	force bool
 }
 
 func newAccumulateCommand(optFns ...func(o *options)) *xcommand {
@@ -141,48 +182,54 @@ func newAccumulateCommand(optFns ...func(o *options)) *xcommand {
 	flags := new(accumulateFlags)
 
 	cmd := newCommand(&cobra.Command{
-		Use:     "Σ",
+		Use:     "Σ [FILE]...",
 		Aliases: []string{"accumulate"},
 		Short:   "ASCII(n) = ΣΔSCII(n)",
-		Args:    cobra.NoArgs,
+		Args:    cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var r io.Reader
-			if flags.input == "-" {
-				r = cmd.InOrStdin()
-			} else {
-				data, err := os.ReadFile(flags.input)
-				if err != nil {
-					return err
-				}
-
-				r = bytes.NewReader(data)
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
 			}
-
-			buf := new(bytes.Buffer)
-			if err := convertASCIICast(r, buf, accumulateFn); err != nil {
+			if err := flags.checkPrecision(); err != nil {
 				return err
 			}
 
-			if flags.output == "-" {
-				if _, err := fmt.Fprint(cmd.OutOrStdout(), buf.String()); err != nil {
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
 					return err
 				}
-			} else {
-				if err := os.WriteFile(flags.output, buf.Bytes(), 0o644); err != nil {
+
+				data, err := io.ReadAll(r)
+				if err != nil {
 					return err
 				}
-			}
 
-			return nil
+				if !flags.force {
+					if err := checkFormat(data, asciinema.FormatDeltaV2, job.stderr); err != nil {
+						return err
+					}
+				}
+
+				t := transform.Chain(transform.Accumulate(job.stderr), transform.Precision(flags.precision), transform.Duration(mode))
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(bytes.NewReader(data), buf, job.stderr, t); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
 		},
 		SilenceUsage: true,
 	})
 
-	cmd.Flags().StringVarP(&flags.input, "input", "i", "", `input Δ-asciicast v2 file or "-" (read from stdin)`)
-	_ = cmd.MarkFlagRequired("input")
+	flags.register(cmd.Command, `input Δ-asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+	flags.registerPrecision(cmd.Command)
 
-	cmd.Flags().StringVarP(&flags.output, "output", "o", "", `output asciicast v2 file or "-" (write to stdout)`)
-	_ = cmd.MarkFlagRequired("output")
+	cmd.Flags().BoolVar(&flags.force, "force", false, "convert even if the input looks already converted")
 
 	cmd.SetIn(opts.stdio.in)
 	cmd.SetOutput(opts.stdio.out)
@@ -191,46 +238,139 @@ func newAccumulateCommand(optFns ...func(o *options)) *xcommand {
 	return cmd
 }
 
-type calcFn func(acc, val float64) (newAcc, newVal float64)
+type durationFlags struct {
+	duration string
+}
+
+func (f *durationFlags) registerDuration(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.duration, "duration", string(transform.DurationUpdate), "header duration after timing changes (update: recompute if present, set, keep or drop)")
+}
 
-var (
-	deltaFn calcFn = func(acc, val float64) (newAcc, newVal float64) {
-		delta := decimal.NewFromFloat(val).Sub(decimal.NewFromFloat(acc)).InexactFloat64()
-		return val, delta
+type precisionFlags struct {
+	precision int32
+}
+
+func (f *precisionFlags) registerPrecision(cmd *cobra.Command) {
+	cmd.Flags().Int32Var(&f.precision, "precision", asciinema.TimePrecision, "maximum decimals of event times written (6: microseconds, as asciinema writes them)")
+}
+
+func (f *precisionFlags) checkPrecision() error {
+	if f.precision < 0 {
+		return fmt.Errorf("invalid precision: %v", f.precision)
+	}
+
+	return nil
+}
+
+func readInput(cmd *cobra.Command, name string) (io.Reader, error) {
+	if name == "-" {
+		return cmd.InOrStdin(), nil
 	}
 
-	accumulateFn calcFn = func(acc, val float64) (newAcc, newVal float64) {
-		sum := decimal.NewFromFloat(val).Add(decimal.NewFromFloat(acc)).InexactFloat64()
-		return sum, sum
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
 	}
-)
 
-func convertASCIICast(r io.Reader, w io.Writer, fn calcFn) error {
-	dec := json.NewDecoder(r)
-	enc := json.NewEncoder(w)
+	return bytes.NewReader(data), nil
+}
+
+func writeOutput(cmd *cobra.Command, name string, data []byte) error {
+	if name == "-" {
+		_, err := cmd.OutOrStdout().Write(data)
+		return err
+	}
+
+	return os.WriteFile(name, data, 0o644)
+}
+
+func printWarnings(w io.Writer, h *asciinema.V2Header) {
+	for _, err := range h.Warnings() {
+		fmt.Fprintf(w, "warning: %v\n", err)
+	}
+}
+
+// checkFormat refuses input detected as another format than want, such as a Δ
+// file given to Δ again, and warns to errW about input it is unsure of.
+func checkFormat(data []byte, want asciinema.Format, errW io.Writer) error {
+	f, sure := asciinema.DetectFormat(data)
+	switch {
+	case f == want, f == asciinema.FormatUnknown:
+		return nil
+	case f == asciinema.FormatV2 && !sure && want == asciinema.FormatDeltaV2:
+		// NOTE: Σ on v2 doubles its times, but Δ files written before the deltascii key have none
+		fmt.Fprintf(errW, "warning: input has no deltascii key and may be %v, assuming %v\n", f, want)
+		return nil
+	case f == asciinema.FormatV2 && !sure:
+		return nil
+	}
+
+	return fmt.Errorf("input is %v, not %v (use --force to convert anyway)", f, want)
+}
+
+func convertASCIICast(r io.Reader, w io.Writer, errW io.Writer, t transform.Transform) error {
+	defer transform.Close(t)
+
+	line, events, err := splitASCIICast(r)
+	if err != nil {
+		return err
+	}
 
 	var h asciinema.V2Header
-	if err := dec.Decode(&h); err != nil {
+	if err := json.Unmarshal(line, &h); err != nil {
 		return err
 	}
+	printWarnings(errW, &h)
 
-	if err := enc.Encode(&h); err != nil {
+	if err := t.Header(&h); err != nil {
 		return err
 	}
 
-	acc := 0.0
+	// NOTE: buffer events, since transforms may finish the header in Flush
+	buf := new(bytes.Buffer)
+	dec := json.NewDecoder(events)
+
+	// NOTE: patch events against the one being read, or the last one at Flush, to keep their text and spacing
+	var raw json.RawMessage
+	emit := func(e asciinema.V2Event) error {
+		b, err := asciinema.PatchV2Event(raw, &e)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(append(b, '\n'))
+		return nil
+	}
+
 	for dec.More() {
-		var e asciinema.V2Event
-		if err := dec.Decode(&e); err != nil {
+		if err := dec.Decode(&raw); err != nil {
 			return err
 		}
 
-		acc, e.Time = fn(acc, e.Time)
+		var e asciinema.V2Event
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return err
+		}
 
-		if err := enc.Encode(&e); err != nil {
+		if err := t.Event(e, emit); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	if err := t.Flush(emit); err != nil {
+		return err
+	}
+
+	// NOTE: patch the header to keep key order, number text and unknown keys
+	b, err := asciinema.PatchV2Header(line, &h)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
 }
@@ -23,28 +23,31 @@ package command
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/transform"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDeltaCommand(t *testing.T) {
-	deltacast := []byte(`{"version":2,"width":80,"height":24,"timestamp":1504467315,"env":{"SHELL":"/bin/zsh","TERM":"xterm-256color"}}
-[0,"o","h"]
-[0.1,"o","e"]
-[0.2,"o","l"]
-[0.3,"o","l"]
-[0.4,"o","o"]
-[0.5,"o"," "]
-[0.6,"o","w"]
-[0.7,"o","o"]
-[0.8,"o","r"]
-[0.9,"o","l"]
-[1,"o","d"]
+	deltacast := []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}, "deltascii": {"version": 1, "duration": 5.5}}
+[0, "o", "h"]
+[0.1, "o", "e"]
+[0.2, "o", "l"]
+[0.3, "o", "l"]
+[0.4, "o", "o"]
+[0.5, "o", " "]
+[0.6, "o", "w"]
+[0.7, "o", "o"]
+[0.8, "o", "r"]
+[0.9, "o", "l"]
+[1, "o", "d"]
 `)
 
 	type args struct {
@@ -168,19 +171,8 @@ func TestDeltaCommand(t *testing.T) {
 }
 
 func TestAccumulateCommand(t *testing.T) {
-	acccast := []byte(`{"version":2,"width":80,"height":24,"timestamp":1504467315,"env":{"SHELL":"/bin/zsh","TERM":"xterm-256color"}}
-[0,"o","h"]
-[0.1,"o","e"]
-[0.4,"o","l"]
-[1,"o","l"]
-[2,"o","o"]
-[3.5,"o"," "]
-[5.6,"o","w"]
-[8.4,"o","o"]
-[12,"o","r"]
-[16.5,"o","l"]
-[22,"o","d"]
-`)
+	// NOTE: Σ restores the asciicast Δ was made from
+	acccast, _ := os.ReadFile("testdata/test.cast")
 
 	type args struct {
 		input  string
@@ -200,7 +192,7 @@ func TestAccumulateCommand(t *testing.T) {
 		{
 			name: "happy path: input from file / output to file",
 			args: &args{
-				input:  "testdata/test.cast",
+				input:  "testdata/test.delta.cast",
 				output: filepath.Join(t.TempDir(), "output.cast"),
 			},
 			expected: &expected{
@@ -222,7 +214,7 @@ func TestAccumulateCommand(t *testing.T) {
 		{
 			name: "happy path: input from file / output to stdout",
 			args: &args{
-				input:  "testdata/test.cast",
+				input:  "testdata/test.delta.cast",
 				output: "-",
 			},
 			expected: &expected{
@@ -244,7 +236,7 @@ func TestAccumulateCommand(t *testing.T) {
 		{
 			name: "edge path: input not exist",
 			args: &args{
-				input:  "testdata/not-exist/test.cast",
+				input:  "testdata/not-exist/test.delta.cast",
 				output: filepath.Join(t.TempDir(), "output.cast"),
 			},
 			expected: &expected{
@@ -255,7 +247,7 @@ func TestAccumulateCommand(t *testing.T) {
 		{
 			name: "edge path: output not exist",
 			args: &args{
-				input:  "testdata/test.cast",
+				input:  "testdata/test.delta.cast",
 				output: filepath.Join(t.TempDir(), "not-exist/output.cast"),
 			},
 			expected: &expected{
@@ -272,7 +264,7 @@ func TestAccumulateCommand(t *testing.T) {
 
 			var stdin io.Reader
 			if tt.args.input == "-" {
-				data, _ := os.ReadFile("testdata/test.cast")
+				data, _ := os.ReadFile("testdata/test.delta.cast")
 				stdin = bytes.NewReader(data)
 			} else {
 				stdin = new(bytes.Reader)
@@ -301,3 +293,284 @@ func TestAccumulateCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestAccumulateCommand_RoundTrip(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	type expected struct {
+		data []byte
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: mixed scales",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "duration": 5.0}
+[0.5, "o", "a"]
+[1.25, "o", "b"]
+[2, "o", "c"]
+[2.5, "o", "d"]
+[3.10, "o", "e"]
+[4.123456, "o", "f"]
+[5.0, "o", "g"]
+`),
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "duration": 5.0}
+[0.5, "o", "a"]
+[1.25, "o", "b"]
+[2, "o", "c"]
+[2.5, "o", "d"]
+[3.10, "o", "e"]
+[4.123456, "o", "f"]
+[5.0, "o", "g"]
+`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			delta := new(bytes.Buffer)
+			acc := new(bytes.Buffer)
+
+			deltaCmd := newDeltaCommand(WithStdio(bytes.NewReader(tt.args.data), delta, io.Discard))
+			deltaCmd.SetArgs([]string{"--input", "-", "--output", "-"})
+			accCmd := newAccumulateCommand(WithStdio(delta, acc, io.Discard))
+			accCmd.SetArgs([]string{"--input", "-", "--output", "-"})
+
+			// Act
+			err1 := deltaCmd.ExecuteContext(ctx)
+			err2 := accCmd.ExecuteContext(ctx)
+
+			// Assert
+			assert.Equal(t, string(tt.expected.data), acc.String())
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+		})
+	}
+}
+
+func TestConvertASCIICast_RoundTrip(t *testing.T) {
+	type args struct {
+		data []byte
+	}
+
+	type expected struct {
+		data []byte
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: header keys and html",
+			args: &args{
+				data: []byte(`{"width": 80, "version": 2, "height": 24, "duration": 1.50, "x-vendor": {"term": "xterm"}}
+[0.5, "o", "<a & b>"]
+[1.5, "o", "\u001b[0m"]
+`),
+			},
+			expected: &expected{
+				data: []byte(`{"width": 80, "version": 2, "height": 24, "duration": 1.50, "x-vendor": {"term": "xterm"}}
+[0.5, "o", "<a & b>"]
+[1.5, "o", "\u001b[0m"]
+`),
+			},
+		},
+		{
+			name: "happy path: escapes and spacing",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5,  "o", "\u0068i \/ \u00e9"]
+[ 1.5 , "o", "\t"]
+`),
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5,  "o", "\u0068i \/ \u00e9"]
+[ 1.5 , "o", "\t"]
+`),
+			},
+		},
+		{
+			name: "happy path: multi-line header",
+			args: &args{
+				data: []byte(`{
+  "version": 2,
+  "width": 80,
+  "height": 24
+}
+[0.5, "o", "a"]
+`),
+			},
+			expected: &expected{
+				data: []byte(`{
+  "version": 2,
+  "width": 80,
+  "height": 24
+}
+[0.5, "o", "a"]
+`),
+			},
+		},
+		{
+			name: "happy path: trailing zeros",
+			args: &args{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[1.0, "o", "b"]
+[1.25, "o", "c"]
+[2.00, "o", "d"]
+`),
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[1.0, "o", "b"]
+[1.25, "o", "c"]
+[2.00, "o", "d"]
+`),
+			},
+		},
+		{
+			name: "happy path: exact times",
+			args: &args{
+				data: []byte(`{"version":2,"width":80,"height":24}
+[1e-06,"o","a"]
+[0.248848,"o","b"]
+[1234567890.1234567,"o","c"]
+[1234567890.2345678,"o","d"]
+`),
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24}
+[1e-06,"o","a"]
+[0.248848,"o","b"]
+[1234567890.1234567,"o","c"]
+[1234567890.2345678,"o","d"]
+`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			delta := new(bytes.Buffer)
+			acc := new(bytes.Buffer)
+
+			// Act
+			err1 := convertASCIICast(bytes.NewReader(tt.args.data), delta, io.Discard, transform.Delta())
+			err2 := convertASCIICast(delta, acc, io.Discard, transform.Accumulate(io.Discard))
+
+			// Assert
+			assert.Equal(t, string(tt.expected.data), acc.String())
+			assert.NoError(t, err1)
+			assert.NoError(t, err2)
+		})
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	type args struct {
+		input string
+		want  asciinema.Format
+	}
+
+	type expected struct {
+		stderr string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: v2 to Δ",
+			args: &args{
+				input: "testdata/edited.cast",
+				want:  asciinema.FormatV2,
+			},
+			expected: &expected{},
+		},
+		{
+			name: "happy path: Δ to v2",
+			args: &args{
+				input: "testdata/edited.delta.cast",
+				want:  asciinema.FormatDeltaV2,
+			},
+			expected: &expected{},
+		},
+		{
+			name: "happy path: marked Δ without times going backwards",
+			args: &args{
+				input: "testdata/test.delta.cast",
+				want:  asciinema.FormatDeltaV2,
+			},
+			expected: &expected{},
+		},
+		{
+			name: "happy path: unmarked Δ without times going backwards",
+			args: &args{
+				input: "testdata/unmarked.delta.cast",
+				want:  asciinema.FormatDeltaV2,
+			},
+			expected: &expected{
+				stderr: "warning: input has no deltascii key and may be asciicast v2, assuming Δ-asciicast v2\n",
+			},
+		},
+		{
+			name: "edge path: double Δ",
+			args: &args{
+				input: "testdata/edited.delta.cast",
+				want:  asciinema.FormatV2,
+			},
+			expected: &expected{
+				err: errors.New("input is Δ-asciicast v2, not asciicast v2 (use --force to convert anyway)"),
+			},
+		},
+		{
+			name: "edge path: ttyrec",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				want:  asciinema.FormatV2,
+			},
+			expected: &expected{
+				err: errors.New("input is ttyrec, not asciicast v2 (use --force to convert anyway)"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			data, _ := os.ReadFile(tt.args.input)
+			stderr := new(bytes.Buffer)
+
+			// Act
+			err := checkFormat(data, tt.args.want, stderr)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.stderr, stderr.String())
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
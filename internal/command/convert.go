@@ -0,0 +1,241 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/Aton-Kish/deltascii/internal/ttyrec"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertFormats = map[string]asciinema.Format{
+		"v1":     asciinema.FormatV1,
+		"v2":     asciinema.FormatV2,
+		"delta":  asciinema.FormatDeltaV2,
+		"v3":     asciinema.FormatV3,
+		"ttyrec": asciinema.FormatTTYRec,
+	}
+)
+
+type convertFlags struct {
+	batchFlags
+	durationFlags
+	precisionFlags
+	from   string
+	to     string
+	width  int
+	height int
+}
+
+func newConvertCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(convertFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "convert [FILE]...",
+		Short: "Convert between asciicast v2, Δ-asciicast v2 and asciicast v3",
+		Long: `Convert between asciicast v2, Δ-asciicast v2 and asciicast v3.
+
+The input format is detected from the content: asciicast v1, v2 or v3 by the header
+version, ttyrec by its frame headers, and Δ-asciicast v2 by the deltascii header
+key, times going backwards or a header duration matching the sum of its times. A
+v2 file whose times never go backwards is taken as asciicast v2 with a warning,
+unless --from is given.
+
+ttyrec has no terminal size, so it is inferred from the first resize sequence
+("ESC [ 8 ; height ; width t") unless --width and --height are given.
+`,
+		Example: `deltascii convert -i ascii.cast -o ascii.delta.cast --to delta
+deltascii convert -i ascii.delta.cast -o ascii.cast --to v2
+deltascii convert docs/casts/*.cast --output-dir v3 --to v3`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
+			}
+			if err := flags.checkPrecision(); err != nil {
+				return err
+			}
+
+			to, ok := convertFormats[flags.to]
+			if !ok || to == asciinema.FormatV1 || to == asciinema.FormatTTYRec {
+				return fmt.Errorf("invalid format: %v", flags.to)
+			}
+
+			from := asciinema.FormatUnknown
+			if flags.from != "auto" {
+				if from, ok = convertFormats[flags.from]; !ok {
+					return fmt.Errorf("invalid format: %v", flags.from)
+				}
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				data, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+
+				from := from
+				if from == asciinema.FormatUnknown {
+					f, sure := asciinema.DetectFormat(data)
+					if f == asciinema.FormatUnknown {
+						return errors.New("unable to detect input format, --from required")
+					}
+					if !sure {
+						fmt.Fprintf(job.stderr, "warning: assuming %v, use --from otherwise\n", f)
+					}
+					from = f
+				}
+
+				buf := new(bytes.Buffer)
+				if err := convertCast(data, buf, job.stderr, from, to, mode, flags); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input recording files, globs or "-" (read from stdin)`, `output file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+	flags.registerPrecision(cmd.Command)
+
+	cmd.Flags().StringVar(&flags.from, "from", "auto", "input format (auto, v1, v2, delta, v3 or ttyrec)")
+	cmd.Flags().StringVar(&flags.to, "to", "", "output format (v2, delta or v3)")
+	_ = cmd.MarkFlagRequired("to")
+	cmd.Flags().IntVar(&flags.width, "width", 0, "terminal width of ttyrec input (number of columns)")
+	cmd.Flags().IntVar(&flags.height, "height", 0, "terminal height of ttyrec input (number of rows)")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+func convertCast(data []byte, w io.Writer, errW io.Writer, from, to asciinema.Format, mode transform.DurationMode, flags *convertFlags) error {
+	if from == to {
+		_, err := w.Write(data)
+		return err
+	}
+
+	// NOTE: between v2 and Δ, patch the header to keep it as is
+	if from == asciinema.FormatV2 && to == asciinema.FormatDeltaV2 {
+		return convertASCIICast(bytes.NewReader(data), w, errW, transform.Chain(transform.Precision(flags.precision), transform.Delta()))
+	}
+	if from == asciinema.FormatDeltaV2 && to == asciinema.FormatV2 {
+		return convertASCIICast(bytes.NewReader(data), w, errW, transform.Chain(transform.Accumulate(errW), transform.Precision(flags.precision), transform.Duration(mode)))
+	}
+
+	h, events, err := readCast(data, errW, from, flags.width, flags.height)
+	if err != nil {
+		return err
+	}
+
+	t := transform.Precision(flags.precision)
+	if to == asciinema.FormatV2 {
+		// NOTE: v3 has no duration, and a Δ file keeps the one it was made from
+		t = transform.Chain(t, transform.Duration(mode))
+	}
+	if events, err = transform.Apply(t, h, events); err != nil {
+		return err
+	}
+
+	switch to {
+	case asciinema.FormatDeltaV2:
+		if events, err = transform.Apply(transform.Delta(), h, events); err != nil {
+			return err
+		}
+		fallthrough
+	case asciinema.FormatV2:
+		return asciinema.WriteV2(w, h, events)
+	case asciinema.FormatV3:
+		return asciinema.WriteV3(w, asciinema.NewV3Header(h), events)
+	}
+
+	return fmt.Errorf("invalid format: %v", to)
+}
+
+// readCast reads a recording in any format as asciicast v2, where width and
+// height give the terminal size of formats without one.
+func readCast(data []byte, errW io.Writer, f asciinema.Format, width, height int) (*asciinema.V2Header, []asciinema.V2Event, error) {
+	r := bytes.NewReader(data)
+
+	switch f {
+	case asciinema.FormatV1:
+		return asciinema.ReadV1(r)
+	case asciinema.FormatV2, asciinema.FormatDeltaV2:
+		h, events, err := asciinema.ReadV2(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		printWarnings(errW, h)
+
+		if f == asciinema.FormatDeltaV2 {
+			if events, err = transform.Apply(transform.Accumulate(errW), h, events); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		return h, events, nil
+	case asciinema.FormatV3:
+		h, events, err := asciinema.ReadV3(r)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// NOTE: v2 has no exit status
+		kept := make([]asciinema.V2Event, 0, len(events))
+		for _, e := range events {
+			if e.Code != "x" {
+				kept = append(kept, e)
+			}
+		}
+
+		return h.V2(), kept, nil
+	case asciinema.FormatTTYRec:
+		frames, err := ttyrec.Read(r)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return ttyrec.ToV2(frames, width, height)
+	}
+
+	return nil, nil, fmt.Errorf("invalid format: %v", f)
+}
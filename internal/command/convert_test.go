@@ -0,0 +1,239 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertCommand(t *testing.T) {
+	testcast, _ := os.ReadFile("testdata/test.cast")
+	deltacast, _ := os.ReadFile("testdata/edited.delta.cast")
+
+	v3cast := []byte(`{"version":3,"term":{"cols":80,"rows":24,"type":"xterm-256color"},"timestamp":1504467315,"title":"x","env":{"SHELL":"/bin/zsh"}}
+[0,"o","h"]
+[0.1,"o","e"]
+[0.2,"o","L"]
+[0.3,"o","L"]
+[0.4,"o","o"]
+[0.5,"o"," "]
+[0.6,"o","w"]
+[1.5,"o","r"]
+[1.1,"o","L"]
+[0.8,"o","d"]
+`)
+
+	type args struct {
+		input string
+		flags []string
+	}
+
+	type expected struct {
+		data   []byte
+		stderr string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: v2 to Δ",
+			args: &args{
+				input: "testdata/edited.cast",
+				flags: []string{"--to", "delta"},
+			},
+			expected: &expected{
+				data:   deltacast,
+				stderr: "warning: assuming asciicast v2, use --from otherwise\n",
+			},
+		},
+		{
+			name: "happy path: Δ to v3",
+			args: &args{
+				input: "testdata/edited.delta.cast",
+				flags: []string{"--to", "v3"},
+			},
+			expected: &expected{
+				data: v3cast,
+			},
+		},
+		{
+			name: "happy path: v2 to v3",
+			args: &args{
+				input: "testdata/edited.cast",
+				flags: []string{"--from", "v2", "--to", "v3"},
+			},
+			expected: &expected{
+				data: v3cast,
+			},
+		},
+		{
+			name: "happy path: Δ to Δ",
+			args: &args{
+				input: "testdata/edited.delta.cast",
+				flags: []string{"--to", "delta"},
+			},
+			expected: &expected{
+				data: deltacast,
+			},
+		},
+		{
+			name: "happy path: ttyrec to v2",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				flags: []string{"--to", "v2", "--width", "80", "--height", "24"},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"timestamp":1504467315}
+[0,"o","h"]
+[0.1,"o","e"]
+[0.3,"o","l"]
+[0.6,"o","l"]
+[1,"o","o"]
+[1.5,"o"," "]
+[2.1,"o","w"]
+[2.8,"o","o"]
+[3.6,"o","r"]
+[4.5,"o","l"]
+[5.5,"o","d"]
+`),
+			},
+		},
+		{
+			name: "happy path: Δ to v2 with duration set",
+			args: &args{
+				input: "testdata/test.delta.cast",
+				flags: []string{"--to", "v2", "--duration", "set"},
+			},
+			expected: &expected{
+				data: bytes.Replace(testcast, []byte(`"TERM": "xterm-256color"}}`), []byte(`"TERM": "xterm-256color"}, "duration": 5.5}`), 1),
+			},
+		},
+		{
+			name: "happy path: ttyrec to v2 with duration set",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				flags: []string{"--to", "v2", "--width", "80", "--height", "24", "--duration", "set"},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"timestamp":1504467315,"duration":5.5}
+[0,"o","h"]
+[0.1,"o","e"]
+[0.3,"o","l"]
+[0.6,"o","l"]
+[1,"o","o"]
+[1.5,"o"," "]
+[2.1,"o","w"]
+[2.8,"o","o"]
+[3.6,"o","r"]
+[4.5,"o","l"]
+[5.5,"o","d"]
+`),
+			},
+		},
+		{
+			name: "edge path: invalid duration mode",
+			args: &args{
+				input: "testdata/edited.cast",
+				flags: []string{"--to", "v2", "--duration", "always"},
+			},
+			expected: &expected{
+				err: errors.New("invalid duration mode: always"),
+			},
+		},
+		{
+			name: "edge path: invalid output format",
+			args: &args{
+				input: "testdata/edited.cast",
+				flags: []string{"--to", "ttyrec"},
+			},
+			expected: &expected{
+				err: errors.New("invalid format: ttyrec"),
+			},
+		},
+		{
+			name: "edge path: invalid input format",
+			args: &args{
+				input: "testdata/edited.cast",
+				flags: []string{"--from", "v4", "--to", "v2"},
+			},
+			expected: &expected{
+				err: errors.New("invalid format: v4"),
+			},
+		},
+		{
+			name: "edge path: ttyrec without size",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				flags: []string{"--to", "v2"},
+			},
+			expected: &expected{
+				err: errors.New("unable to infer terminal size, width and height required"),
+			},
+		},
+		{
+			name: "edge path: unknown input format",
+			args: &args{
+				input: "testdata/pipeline.yaml",
+				flags: []string{"--to", "v2"},
+			},
+			expected: &expected{
+				err: errors.New("unable to detect input format, --from required"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newConvertCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input, "--output", "-"}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.Equal(t, tt.expected.stderr, stderr.String())
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
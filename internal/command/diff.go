@@ -0,0 +1,258 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/castdiff"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+type diffFlags struct {
+	delta bool
+	json  bool
+}
+
+func newDiffCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(diffFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "diff A B",
+		Short: "Compare two asciicasts by events and timing",
+		Long: `Compare two asciicasts by events and timing.
+
+Events are aligned by code and data, and reported as inserted, deleted or retimed.
+Timing is compared by the time since the previous event (Δ), so deleting an event in a Δ file retimes nothing.
+A Δ file is told by its deltascii header key, and --delta treats the inputs as ones without it.
+`,
+		Example: `deltascii diff original.cast edited.cast
+deltascii diff original.delta.cast edited.delta.cast`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			a, err := readInput(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			b, err := readInput(cmd, args[1])
+			if err != nil {
+				return err
+			}
+
+			d, err := diffASCIICasts(a, b, flags.delta, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+
+			buf := new(bytes.Buffer)
+			if flags.json {
+				enc := json.NewEncoder(buf)
+				enc.SetEscapeHTML(false)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(d); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(buf, "--- %s\n+++ %s\n", args[0], args[1])
+				if err := d.WriteText(buf); err != nil {
+					return err
+				}
+			}
+
+			return writeOutput(cmd, "-", buf.Bytes())
+		},
+		SilenceUsage: true,
+	})
+
+	cmd.Flags().BoolVar(&flags.delta, "delta", false, "treat inputs as Δ files even without a deltascii header key")
+	cmd.Flags().BoolVar(&flags.json, "json", false, "output in JSON format")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type castDiff struct {
+	Header   []castdiff.HeaderChange `json:"header"`
+	Events   []castEventChange       `json:"events"`
+	Duration castDurationChange      `json:"duration"`
+	Inserted int                     `json:"inserted"`
+	Deleted  int                     `json:"deleted"`
+	Retimed  int                     `json:"retimed"`
+}
+
+type castEventChange struct {
+	Op       string   `json:"op"`
+	A        int      `json:"a,omitempty"`
+	B        int      `json:"b,omitempty"`
+	Code     string   `json:"code"`
+	Data     any      `json:"data"`
+	OldDelta *float64 `json:"old_delta,omitempty"`
+	NewDelta *float64 `json:"new_delta,omitempty"`
+}
+
+type castDurationChange struct {
+	Old float64 `json:"old"`
+	New float64 `json:"new"`
+}
+
+func diffASCIICasts(a, b io.Reader, delta bool, errW io.Writer) (*castDiff, error) {
+	ah, aEvents, err := asciinema.ReadV2(a)
+	if err != nil {
+		return nil, err
+	}
+	printWarnings(errW, ah)
+
+	bh, bEvents, err := asciinema.ReadV2(b)
+	if err != nil {
+		return nil, err
+	}
+	printWarnings(errW, bh)
+
+	header, err := castdiff.Header(ah, bh)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: a Δ file is told by its header, and --delta covers one without it
+	aDeltas, aDuration := eventDeltas(aEvents, delta || ah.Delta != nil)
+	bDeltas, bDuration := eventDeltas(bEvents, delta || bh.Delta != nil)
+
+	d := &castDiff{
+		Header:   header,
+		Events:   make([]castEventChange, 0),
+		Duration: castDurationChange{Old: aDuration, New: bDuration},
+	}
+
+	for _, e := range castdiff.Events(aEvents, bEvents) {
+		switch e.Op {
+		case castdiff.OpDelete:
+			d.Deleted++
+			d.Events = append(d.Events, castEventChange{
+				Op: "delete", A: e.A + 1, Code: aEvents[e.A].Code, Data: aEvents[e.A].Data, OldDelta: &aDeltas[e.A],
+			})
+		case castdiff.OpInsert:
+			d.Inserted++
+			d.Events = append(d.Events, castEventChange{
+				Op: "insert", B: e.B + 1, Code: bEvents[e.B].Code, Data: bEvents[e.B].Data, NewDelta: &bDeltas[e.B],
+			})
+		case castdiff.OpEqual:
+			if aDeltas[e.A] == bDeltas[e.B] {
+				continue
+			}
+
+			d.Retimed++
+			d.Events = append(d.Events, castEventChange{
+				Op: "retime", A: e.A + 1, B: e.B + 1, Code: aEvents[e.A].Code, Data: aEvents[e.A].Data,
+				OldDelta: &aDeltas[e.A], NewDelta: &bDeltas[e.B],
+			})
+		}
+	}
+
+	return d, nil
+}
+
+// eventDeltas returns the time since the previous event for each event, and
+// the total duration.
+func eventDeltas(events []asciinema.V2Event, delta bool) ([]float64, float64) {
+	deltas := make([]float64, 0, len(events))
+	prev, total := decimal.Zero, decimal.Zero
+	for _, e := range events {
+		t := e.Time.Decimal()
+		if delta {
+			deltas = append(deltas, e.Time.Float64())
+			total = total.Add(t)
+			continue
+		}
+
+		deltas = append(deltas, t.Sub(prev).InexactFloat64())
+		prev, total = t, t
+	}
+
+	return deltas, total.InexactFloat64()
+}
+
+func (d *castDiff) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if len(d.Header) > 0 {
+		fmt.Fprintln(tw, "Header:")
+		for _, c := range d.Header {
+			switch {
+			case c.Old == nil:
+				fmt.Fprintf(tw, "  + %s:\t%s\n", c.Key, c.New)
+			case c.New == nil:
+				fmt.Fprintf(tw, "  - %s:\t%s\n", c.Key, c.Old)
+			default:
+				fmt.Fprintf(tw, "  ~ %s:\t%s -> %s\n", c.Key, c.Old, c.New)
+			}
+		}
+	}
+
+	if len(d.Events) > 0 {
+		fmt.Fprintln(tw, "Events:")
+		for _, e := range d.Events {
+			data, err := marshalJSON(e.Data)
+			if err != nil {
+				return err
+			}
+
+			switch e.Op {
+			case "delete":
+				fmt.Fprintf(tw, "  - a#%d\tΔ%s\t%q %s\n", e.A, formatSeconds(*e.OldDelta), e.Code, data)
+			case "insert":
+				fmt.Fprintf(tw, "  + b#%d\tΔ%s\t%q %s\n", e.B, formatSeconds(*e.NewDelta), e.Code, data)
+			case "retime":
+				change := subTime(*e.NewDelta, *e.OldDelta)
+				fmt.Fprintf(tw, "  ~ a#%d b#%d\tΔ%s -> %s (%+gs)\t%q %s\n", e.A, e.B, formatSeconds(*e.OldDelta), formatSeconds(*e.NewDelta), change, e.Code, data)
+			}
+		}
+	}
+
+	change := subTime(d.Duration.New, d.Duration.Old)
+	fmt.Fprintf(tw, "Duration: %s -> %s (%+gs)\n", formatSeconds(d.Duration.Old), formatSeconds(d.Duration.New), change)
+	fmt.Fprintf(tw, "Summary: %d inserted, %d deleted, %d retimed\n", d.Inserted, d.Deleted, d.Retimed)
+
+	return tw.Flush()
+}
+
+func marshalJSON(v any) (string, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
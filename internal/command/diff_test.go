@@ -0,0 +1,232 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCommand(t *testing.T) {
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data  string
+		errIs error
+	}
+
+	text := `Header:
+  + title:  "x"
+Events:
+  - a#3        Δ0.2s                  "o" "l"
+  - a#4        Δ0.3s                  "o" "l"
+  + b#3        Δ0.2s                  "o" "L"
+  + b#4        Δ0.3s                  "o" "L"
+  - a#8        Δ0.7s                  "o" "o"
+  ~ a#9 b#8    Δ0.8s -> 1.5s (+0.7s)  "o" "r"
+  - a#10       Δ0.9s                  "o" "l"
+  + b#9        Δ1.1s                  "o" "L"
+  ~ a#11 b#10  Δ1s -> 0.8s (-0.2s)    "o" "d"
+Duration: 5.5s -> 5.5s (+0s)
+Summary: 3 inserted, 4 deleted, 2 retimed
+`
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				args: []string{"testdata/test.cast", "testdata/edited.cast"},
+			},
+			expected: &expected{
+				data: "--- testdata/test.cast\n+++ testdata/edited.cast\n" + text,
+			},
+		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				args: []string{"--delta", "testdata/test.delta.cast", "testdata/edited.delta.cast"},
+			},
+			expected: &expected{
+				data: "--- testdata/test.delta.cast\n+++ testdata/edited.delta.cast\n" + text,
+			},
+		},
+		{
+			name: "happy path: delta detected by header",
+			args: &args{
+				args: []string{"testdata/test.delta.cast", "testdata/edited.delta.cast"},
+			},
+			expected: &expected{
+				data: "--- testdata/test.delta.cast\n+++ testdata/edited.delta.cast\n" + text,
+			},
+		},
+		{
+			name: "happy path: same",
+			args: &args{
+				args: []string{"testdata/test.cast", "testdata/test.cast"},
+			},
+			expected: &expected{
+				data: `--- testdata/test.cast
++++ testdata/test.cast
+Duration: 5.5s -> 5.5s (+0s)
+Summary: 0 inserted, 0 deleted, 0 retimed
+`,
+			},
+		},
+		{
+			name: "happy path: json",
+			args: &args{
+				args: []string{"--json", "testdata/test.cast", "testdata/edited.cast"},
+			},
+			expected: &expected{
+				data: `{
+  "header": [
+    {
+      "key": "title",
+      "new": "x"
+    }
+  ],
+  "events": [
+    {
+      "op": "delete",
+      "a": 3,
+      "code": "o",
+      "data": "l",
+      "old_delta": 0.2
+    },
+    {
+      "op": "delete",
+      "a": 4,
+      "code": "o",
+      "data": "l",
+      "old_delta": 0.3
+    },
+    {
+      "op": "insert",
+      "b": 3,
+      "code": "o",
+      "data": "L",
+      "new_delta": 0.2
+    },
+    {
+      "op": "insert",
+      "b": 4,
+      "code": "o",
+      "data": "L",
+      "new_delta": 0.3
+    },
+    {
+      "op": "delete",
+      "a": 8,
+      "code": "o",
+      "data": "o",
+      "old_delta": 0.7
+    },
+    {
+      "op": "retime",
+      "a": 9,
+      "b": 8,
+      "code": "o",
+      "data": "r",
+      "old_delta": 0.8,
+      "new_delta": 1.5
+    },
+    {
+      "op": "delete",
+      "a": 10,
+      "code": "o",
+      "data": "l",
+      "old_delta": 0.9
+    },
+    {
+      "op": "insert",
+      "b": 9,
+      "code": "o",
+      "data": "L",
+      "new_delta": 1.1
+    },
+    {
+      "op": "retime",
+      "a": 11,
+      "b": 10,
+      "code": "o",
+      "data": "d",
+      "old_delta": 1,
+      "new_delta": 0.8
+    }
+  ],
+  "duration": {
+    "old": 5.5,
+    "new": 5.5
+  },
+  "inserted": 3,
+  "deleted": 4,
+  "retimed": 2
+}
+`,
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				args: []string{"testdata/test.cast", "testdata/not-exist/test.cast"},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newDiffCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(tt.args.args)
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
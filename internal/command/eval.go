@@ -0,0 +1,132 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Aton-Kish/deltascii/internal/script"
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/spf13/cobra"
+)
+
+type evalFlags struct {
+	batchFlags
+	durationFlags
+	precisionFlags
+	file string
+}
+
+func newEvalCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(evalFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "eval [SCRIPT] [FILE]...",
+		Short: "Run a script over every event of asciicast v2",
+		Long: `Run a script over every event of asciicast v2.
+
+The script runs once per event with these variables:
+
+  time    event time in seconds
+  delta   seconds since the previous event
+  code    event code ("o", "i", "m" or "r")
+  data    event data
+  acc     accumulator kept across events, starting at 0
+
+Other assigned variables are kept across events too. An assigned time places the
+event there, otherwise the event follows the previous output event by delta, so
+that a shortened or dropped event shifts the events after it.
+
+Statements are assignments (=, +=, -=, *=, /=), if/else if/else blocks and drop,
+which removes the event. Expressions have numbers, durations such as 500ms,
+strings, true, false, the operators of Go and these functions:
+
+  len contains hasPrefix hasSuffix upper lower trim replace
+  match replaceRegexp min max abs round string
+`,
+		Example: `deltascii eval 'if code=="o" && delta>3 { delta = 1 }' -i ascii.cast -o edited.cast
+deltascii eval 'if code == "i" { drop }' docs/casts/*.cast --in-place
+deltascii eval --file trim.ds -i ascii.cast -o edited.cast`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var src string
+			if flags.file != "" {
+				b, err := os.ReadFile(flags.file)
+				if err != nil {
+					return err
+				}
+				src = string(b)
+			} else {
+				if len(args) == 0 {
+					return errors.New("script is required")
+				}
+				src, args = args[0], args[1:]
+			}
+
+			p, err := script.Parse(src)
+			if err != nil {
+				return fmt.Errorf("invalid script: %w", err)
+			}
+
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
+			}
+			if err := flags.checkPrecision(); err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				t := transform.Chain(transform.Eval(p), transform.Precision(flags.precision), transform.Duration(mode))
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(r, buf, job.stderr, t); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+	flags.registerPrecision(cmd.Command)
+
+	cmd.Flags().StringVar(&flags.file, "file", "", "script file, instead of the SCRIPT argument")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalCommand(t *testing.T) {
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				args: []string{"if delta > 0.5 { delta = 0.5 }"},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}}
+[0, "o", "h"]
+[0.1, "o", "e"]
+[0.3, "o", "l"]
+[0.6, "o", "l"]
+[1, "o", "o"]
+[1.5, "o", " "]
+[2, "o", "w"]
+[2.5, "o", "o"]
+[3, "o", "r"]
+[3.5, "o", "l"]
+[4, "o", "d"]
+`),
+			},
+		},
+		{
+			name: "happy path: script file",
+			args: &args{
+				args: []string{"--file", "{dir}/script.ds"},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}}
+[0, "o", "h"]
+[0.1, "o", "e"]
+[0.3, "o", "l"]
+[0.6, "o", "l"]
+[1, "o", "o"]
+[1.5, "o", " "]
+`),
+			},
+		},
+		{
+			name: "edge path: missing script",
+			args: &args{
+				args: []string{},
+			},
+			expected: &expected{
+				err: errors.New("script is required"),
+			},
+		},
+		{
+			name: "edge path: invalid script",
+			args: &args{
+				args: []string{"if delta > 3 {"},
+			},
+			expected: &expected{
+				err: errors.New("invalid script: 1:15: unexpected end of script"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			dir := t.TempDir()
+			_ = os.WriteFile(filepath.Join(dir, "script.ds"), []byte("# keep the first word\nif time >= 2 {\n\tdrop\n}\n"), 0o644)
+
+			args := make([]string, 0, len(tt.args.args))
+			for _, arg := range tt.args.args {
+				args = append(args, strings.ReplaceAll(arg, "{dir}", dir))
+			}
+
+			cmd := newEvalCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append(args, "--input", "testdata/test.cast", "--output", "-"))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"os"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/terminalizer"
+	"github.com/Aton-Kish/deltascii/internal/ttyrec"
+	"github.com/Aton-Kish/deltascii/internal/typescript"
+	"github.com/spf13/cobra"
+)
+
+func newExportCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "export",
+		Short: "Convert asciicast v2 into other recording formats",
+		Args:  cobra.NoArgs,
+	})
+
+	cmd.AddCommand(
+		newExportTTYRecCommand(optFns...).Command,
+		newExportScriptCommand(optFns...).Command,
+		newExportTerminalizerCommand(optFns...).Command,
+		newExportFramesCommand(optFns...).Command,
+		newExportAVICommand(optFns...).Command,
+	)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type exportTTYRecFlags struct {
+	batchFlags
+}
+
+func newExportTTYRecCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(exportTTYRecFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "ttyrec [FILE]...",
+		Short: "Convert asciicast v2 into ttyrec",
+		Long: `Convert asciicast v2 into ttyrec.
+
+Output events are written as frames and resize events as resize sequences ("ESC [ 8 ; height ; width t").
+Other events are dropped.
+`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := asciinema.ReadV2(r)
+				if err != nil {
+					return err
+				}
+				printWarnings(job.stderr, h)
+
+				frames, err := ttyrec.FromV2(h, events)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := ttyrec.Write(buf, frames); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output ttyrec file or "-" (write to stdout)`, ".ttyrec")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type exportScriptFlags struct {
+	batchFlags
+	timing string
+	format string
+}
+
+func newExportScriptCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(exportScriptFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "script [FILE]...",
+		Short: "Convert asciicast v2 into util-linux script typescript and timing",
+		Long: `Convert asciicast v2 into util-linux script typescript and timing, which scriptreplay can play.
+
+The classic format keeps output events only.
+The advanced format keeps output, input and resize events, input is written into the typescript as "script --log-io" does.
+`,
+		Example: `deltascii export script -i ascii.cast -o typescript -t timing
+scriptreplay -t timing typescript`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.timing == "" && !flags.batch(cmd) {
+				return errors.New(`required flag(s) "timing" not set`)
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := asciinema.ReadV2(r)
+				if err != nil {
+					return err
+				}
+				printWarnings(job.stderr, h)
+
+				// NOTE: in batch mode, the timing file is named after the typescript file
+				timingName := flags.timing
+				if timingName == "" {
+					timingName = replaceExt(job.output, ".timing")
+				}
+
+				log := new(bytes.Buffer)
+				timing := new(bytes.Buffer)
+				if err := typescript.FromV2(h, events, typescript.Format(flags.format), log, timing); err != nil {
+					return err
+				}
+
+				if err := os.WriteFile(timingName, timing.Bytes(), 0o644); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, log.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output typescript file or "-" (write to stdout)`, ".typescript")
+
+	cmd.Flags().StringVarP(&flags.timing, "timing", "t", "", "output timing file (named after output in batch mode)")
+	cmd.MarkFlagsMutuallyExclusive("timing", "output-dir")
+	cmd.MarkFlagsMutuallyExclusive("timing", "suffix")
+
+	cmd.Flags().StringVar(&flags.format, "format", string(typescript.FormatClassic), `timing format, "classic" or "advanced"`)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type exportTerminalizerFlags struct {
+	batchFlags
+}
+
+func newExportTerminalizerCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(exportTerminalizerFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "terminalizer [FILE]...",
+		Short: "Convert asciicast v2 into terminalizer YAML",
+		Long: `Convert asciicast v2 into terminalizer YAML.
+
+Event times are converted into record delays in milliseconds, and other events than output are dropped.
+`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := asciinema.ReadV2(r)
+				if err != nil {
+					return err
+				}
+				printWarnings(job.stderr, h)
+
+				rec, err := terminalizer.FromV2(h, events)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := terminalizer.Write(buf, rec); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output terminalizer YAML file or "-" (write to stdout)`, ".yml")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
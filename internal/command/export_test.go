@@ -0,0 +1,264 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTTYRecCommand(t *testing.T) {
+	ttyrec, _ := os.ReadFile("testdata/test.ttyrec")
+
+	type args struct {
+		input string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "testdata/test.cast",
+			},
+			expected: &expected{
+				data: ttyrec,
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newExportTTYRecCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs([]string{"--input", tt.args.input, "--output", "-"})
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, stdout.Bytes())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
+
+func TestExportScriptCommand(t *testing.T) {
+	type args struct {
+		flags []string
+	}
+
+	type expected struct {
+		data   []byte
+		timing []byte
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: classic",
+			args: &args{
+				flags: []string{},
+			},
+			expected: &expected{
+				data: []byte(`Script started on 2017-09-03 19:35:15+00:00 [TERM="xterm-256color" COLUMNS="80" LINES="24"]
+hello world
+Script done on 2017-09-03 19:35:15+00:00 [COMMAND_EXIT_CODE="0"]
+`),
+				timing: []byte(`0.000000 1
+0.100000 1
+0.200000 1
+0.300000 1
+0.400000 1
+0.500000 1
+0.600000 1
+0.700000 1
+0.800000 1
+0.900000 1
+1.000000 1
+`),
+			},
+		},
+		{
+			name: "edge path: invalid format",
+			args: &args{
+				flags: []string{"--format", "unknown"},
+			},
+			expected: &expected{
+				err: errors.New("invalid format: unknown"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			timing := filepath.Join(t.TempDir(), "timing")
+
+			cmd := newExportScriptCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", "testdata/test.cast", "--output", "-", "--timing", timing}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				data, _ := os.ReadFile(timing)
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.Equal(t, string(tt.expected.timing), string(data))
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestExportTerminalizerCommand(t *testing.T) {
+	type args struct {
+		input string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "testdata/test.cast",
+			},
+			expected: &expected{
+				data: []byte(`config:
+  cols: 80
+  rows: 24
+records:
+  - delay: 0
+    content: h
+  - delay: 100
+    content: e
+  - delay: 200
+    content: l
+  - delay: 300
+    content: l
+  - delay: 400
+    content: o
+  - delay: 500
+    content: ' '
+  - delay: 600
+    content: w
+  - delay: 700
+    content: o
+  - delay: 800
+    content: r
+  - delay: 900
+    content: l
+  - delay: 1000
+    content: d
+`),
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newExportTerminalizerCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs([]string{"--input", tt.args.input, "--output", "-"})
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
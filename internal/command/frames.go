@@ -0,0 +1,316 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/avi"
+	"github.com/Aton-Kish/deltascii/internal/render"
+	"github.com/Aton-Kish/deltascii/internal/theme"
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+const (
+	framesListName = "frames.txt"
+)
+
+type renderFlags struct {
+	theme         string
+	themeFile     string
+	scale         int
+	hold          float64
+	idleTimeLimit float64
+}
+
+func (f *renderFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.theme, "theme", "", "bundled theme name overriding the header theme")
+	cmd.Flags().StringVar(&f.themeFile, "theme-file", "", "theme file overriding the header theme")
+	cmd.MarkFlagsMutuallyExclusive("theme", "theme-file")
+	cmd.Flags().IntVar(&f.scale, "scale", 2, "pixels per font pixel")
+	cmd.Flags().Float64Var(&f.hold, "hold", 1, "seconds to hold the last frame")
+	cmd.Flags().Float64Var(&f.idleTimeLimit, "idle-time-limit", 0, "cap idle time in seconds (default the header idle_time_limit, 0 disables)")
+}
+
+func (f *renderFlags) check() error {
+	if f.hold < 0 {
+		return fmt.Errorf("invalid hold: %v", f.hold)
+	}
+
+	return nil
+}
+
+// frames reads an asciicast and calls fn with every distinct screen state
+// rendered as an image. Every image has the size of the largest terminal in the
+// asciicast, as video frames cannot change size.
+func (f *renderFlags) frames(cmd *cobra.Command, r io.Reader, errW io.Writer, fn func(fr *render.Frame, img *image.RGBA) error) error {
+	h, events, err := asciinema.ReadV2(r)
+	if err != nil {
+		return err
+	}
+	printWarnings(errW, h)
+
+	if err := h.Validate(); err != nil {
+		return err
+	}
+
+	t := h.Theme
+	switch {
+	case f.theme != "":
+		t, err = theme.Lookup(f.theme)
+	case f.themeFile != "":
+		t, err = theme.LoadFile(f.themeFile)
+	case t == nil:
+		t, err = theme.Lookup("asciinema")
+	}
+	if err != nil {
+		return err
+	}
+
+	rd, err := render.New(t, f.scale)
+	if err != nil {
+		return err
+	}
+
+	events, err = limitIdleTime(h, events, idleTimeLimit(cmd, h, f.idleTimeLimit))
+	if err != nil {
+		return err
+	}
+
+	width, height := render.CanvasSize(h, events)
+
+	return render.Frames(h, events, f.hold, func(fr *render.Frame) error {
+		return fn(fr, rd.RenderCanvas(fr.Screen, width, height))
+	})
+}
+
+// idleTimeLimit returns the --idle-time-limit flag if given, or else the header
+// idle_time_limit, so that every rendering shares the same timeline.
+func idleTimeLimit(cmd *cobra.Command, h *asciinema.V2Header, flag float64) float64 {
+	if cmd.Flags().Changed("idle-time-limit") {
+		return flag
+	}
+
+	return h.IdleTimeLimit
+}
+
+// limitIdleTime shortens every pause between events to at most limit seconds,
+// as players do with the header idle_time_limit, and updates the header duration.
+func limitIdleTime(h *asciinema.V2Header, events []asciinema.V2Event, limit float64) ([]asciinema.V2Event, error) {
+	if limit <= 0 {
+		return events, nil
+	}
+
+	return transform.Apply(transform.Chain(transform.IdleLimit(limit), transform.Duration(transform.DurationUpdate)), h, events)
+}
+
+type exportFramesFlags struct {
+	renderFlags
+	batchFlags
+}
+
+func newExportFramesCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(exportFramesFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "frames [FILE]...",
+		Short: "Render asciicast v2 into numbered PNG frames",
+		Long: `Render asciicast v2 into numbered PNG frames.
+
+One PNG is written per distinct screen state, and an ffconcat list (` + framesListName + `) gives how long each frame stays on screen.
+Frames are drawn with the header theme, or the bundled asciinema theme if the header has none.
+Every frame has the size of the largest terminal in the asciicast, with smaller screens drawn at the top left.
+`,
+		Example: `deltascii export frames -i ascii.cast -o frames
+ffmpeg -f concat -i frames/` + framesListName + ` -vf format=yuv420p ascii.mp4`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.check(); err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				if err := os.MkdirAll(job.output, 0o755); err != nil {
+					return err
+				}
+
+				list := new(bytes.Buffer)
+				fmt.Fprintln(list, "ffconcat version 1.0")
+
+				n, last := 0, ""
+				err = flags.frames(cmd, r, job.stderr, func(fr *render.Frame, img *image.RGBA) error {
+					n++
+					last = fmt.Sprintf("frame-%06d.png", n)
+
+					buf := new(bytes.Buffer)
+					if err := png.Encode(buf, img); err != nil {
+						return err
+					}
+
+					if err := os.WriteFile(filepath.Join(job.output, last), buf.Bytes(), 0o644); err != nil {
+						return err
+					}
+
+					fmt.Fprintf(list, "file '%s'\nduration %s\n", last, strconv.FormatFloat(fr.Duration, 'f', -1, 64))
+
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				// NOTE: the concat demuxer ignores the duration of the final entry
+				fmt.Fprintf(list, "file '%s'\n", last)
+
+				return os.WriteFile(filepath.Join(job.output, framesListName), list.Bytes(), 0o644)
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.batchFlags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, "output directory", "")
+
+	flags.renderFlags.register(cmd.Command)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type exportAVIFlags struct {
+	renderFlags
+	batchFlags
+	fps     int
+	quality int
+}
+
+func newExportAVICommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(exportAVIFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "avi [FILE]...",
+		Short: "Render asciicast v2 into Motion JPEG AVI video",
+		Long: `Render asciicast v2 into Motion JPEG AVI video, without ffmpeg.
+
+Frames are drawn as "export frames" does and repeated at a constant frame rate.
+`,
+		Example: `deltascii export avi -i ascii.cast -o ascii.avi --fps 15`,
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := flags.check(); err != nil {
+				return err
+			}
+
+			if flags.quality < 1 || flags.quality > 100 {
+				return fmt.Errorf("invalid quality: %v", flags.quality)
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				var w *avi.Writer
+				var shown int64
+				err = flags.frames(cmd, r, job.stderr, func(fr *render.Frame, img *image.RGBA) error {
+					if w == nil {
+						aw, err := avi.NewWriter(buf, img.Rect.Dx(), img.Rect.Dy(), flags.fps)
+						if err != nil {
+							return err
+						}
+						w = aw
+					}
+
+					// NOTE: count frames from absolute times so that rounding never drifts
+					end := decimal.NewFromFloat(fr.Time).Add(decimal.NewFromFloat(fr.Duration))
+					total := end.Mul(decimal.NewFromInt(int64(flags.fps))).Round(0).IntPart()
+					count := total - shown
+					if count <= 0 {
+						return nil
+					}
+					shown = total
+
+					b := new(bytes.Buffer)
+					if err := jpeg.Encode(b, img, &jpeg.Options{Quality: flags.quality}); err != nil {
+						return err
+					}
+
+					w.WriteFrame(b.Bytes(), int(min(count, math.MaxInt32)))
+
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				if w == nil {
+					return errors.New("no frames to write")
+				}
+
+				if err := w.Close(); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.batchFlags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output AVI file or "-" (write to stdout)`, ".avi")
+
+	cmd.Flags().IntVar(&flags.fps, "fps", 10, "frames per second")
+	cmd.Flags().IntVar(&flags.quality, "quality", 90, "JPEG quality from 1 to 100")
+
+	flags.renderFlags.register(cmd.Command)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
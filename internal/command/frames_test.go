@@ -0,0 +1,336 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitIdleTime(t *testing.T) {
+	type args struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		limit  float64
+	}
+
+	type expected struct {
+		times    []float64
+		duration float64
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: no limit",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Duration: 5},
+				events: []asciinema.V2Event{{Time: "0.5"}, {Time: "5"}},
+				limit:  0,
+			},
+			expected: &expected{times: []float64{0.5, 5}, duration: 5},
+		},
+		{
+			name: "happy path: limit",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Duration: 10.4},
+				events: []asciinema.V2Event{{Time: "3"}, {Time: "3.2"}, {Time: "10.3"}, {Time: "10.4"}},
+				limit:  1.5,
+			},
+			expected: &expected{times: []float64{1.5, 1.7, 3.2, 3.3}, duration: 3.3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := limitIdleTime(tt.args.header, tt.args.events, tt.args.limit)
+
+			// Assert
+			times := make([]float64, 0, len(actual))
+			for _, e := range actual {
+				times = append(times, e.Time.Float64())
+			}
+			assert.Equal(t, tt.expected.times, times)
+			assert.Equal(t, tt.expected.duration, tt.args.header.Duration)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExportFramesCommand(t *testing.T) {
+	type args struct {
+		input string
+		flags []string
+	}
+
+	type expected struct {
+		list   string
+		width  int
+		height int
+		errIs  error
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--scale", "1"},
+			},
+			expected: &expected{
+				list: `ffconcat version 1.0
+file 'frame-000001.png'
+duration 0.1
+file 'frame-000002.png'
+duration 0.2
+file 'frame-000003.png'
+duration 0.3
+file 'frame-000004.png'
+duration 0.4
+file 'frame-000005.png'
+duration 0.5
+file 'frame-000006.png'
+duration 0.6
+file 'frame-000007.png'
+duration 0.7
+file 'frame-000008.png'
+duration 0.8
+file 'frame-000009.png'
+duration 0.9
+file 'frame-000010.png'
+duration 1
+file 'frame-000011.png'
+duration 1
+file 'frame-000011.png'
+`,
+				width:  80 * 8,
+				height: 24 * 12,
+			},
+		},
+		{
+			name: "happy path: idle time limit",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--scale", "1", "--idle-time-limit", "0.25", "--hold", "2", "--theme", "nord"},
+			},
+			expected: &expected{
+				list: `ffconcat version 1.0
+file 'frame-000001.png'
+duration 0.1
+file 'frame-000002.png'
+duration 0.2
+file 'frame-000003.png'
+duration 0.25
+file 'frame-000004.png'
+duration 0.25
+file 'frame-000005.png'
+duration 0.25
+file 'frame-000006.png'
+duration 0.25
+file 'frame-000007.png'
+duration 0.25
+file 'frame-000008.png'
+duration 0.25
+file 'frame-000009.png'
+duration 0.25
+file 'frame-000010.png'
+duration 0.25
+file 'frame-000011.png'
+duration 2
+file 'frame-000011.png'
+`,
+				width:  80 * 8,
+				height: 24 * 12,
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+		{
+			name: "edge path: unknown theme",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--theme", "unknown"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("unknown theme: %v", "unknown"),
+			},
+		},
+		{
+			name: "edge path: negative hold",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--hold", "-1"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid hold: %v", -1.0),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			dir := filepath.Join(t.TempDir(), "frames")
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newExportFramesCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input, "--output", dir}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+
+				list, _ := os.ReadFile(filepath.Join(dir, "frames.txt"))
+				assert.Equal(t, tt.expected.list, string(list))
+
+				f, _ := os.Open(filepath.Join(dir, "frame-000001.png"))
+				defer f.Close()
+				cfg, err := png.DecodeConfig(f)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.width, cfg.Width)
+				assert.Equal(t, tt.expected.height, cfg.Height)
+			} else if tt.expected.errIs != nil {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestExportAVICommand(t *testing.T) {
+	type args struct {
+		input string
+		flags []string
+	}
+
+	type expected struct {
+		frames int
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--scale", "1", "--fps", "10"},
+			},
+			expected: &expected{
+				// NOTE: 5.5s of typing and 1s of hold
+				frames: 65,
+			},
+		},
+		{
+			name: "edge path: invalid quality",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--quality", "0"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid quality: %v", 0),
+			},
+		},
+		{
+			name: "edge path: invalid fps",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--fps", "0"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid fps: %v", 0),
+			},
+		},
+		{
+			name: "edge path: negative hold",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--hold", "-0.5"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid hold: %v", -0.5),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newExportAVICommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input, "--output", "-"}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+				assert.Equal(t, "RIFF", string(stdout.Bytes()[:4]))
+				assert.Equal(t, tt.expected.frames, bytes.Count(stdout.Bytes(), []byte("00dc"))/2)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+func newHeaderCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "header",
+		Short: "Get or set asciicast header",
+		Args:  cobra.NoArgs,
+	})
+
+	cmd.AddCommand(
+		newHeaderGetCommand(optFns...).Command,
+		newHeaderSetCommand(optFns...).Command,
+	)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type headerGetFlags struct {
+	inputFlags
+}
+
+func newHeaderGetCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(headerGetFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "get [FILE]...",
+		Short: "Print asciicast header",
+		Long: `Print asciicast header.
+
+With several files, each header follows a "==> FILE <==" heading.
+`,
+		Example: `deltascii header get -i ascii.cast
+deltascii header get 'docs/casts/*.cast'`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buf := new(bytes.Buffer)
+			err := flags.each(cmd, args, func(input string, r io.Reader, errW io.Writer, multi bool) error {
+				line, _, err := splitASCIICast(r)
+				if err != nil {
+					return err
+				}
+
+				var h asciinema.V2Header
+				if err := json.Unmarshal(line, &h); err != nil {
+					return err
+				}
+				printWarnings(errW, &h)
+
+				b, err := asciinema.PatchV2Header(line, &h)
+				if err != nil {
+					return err
+				}
+
+				if multi {
+					if buf.Len() > 0 {
+						buf.WriteByte('\n')
+					}
+					fmt.Fprintf(buf, "==> %s <==\n", input)
+				}
+
+				if err := json.Indent(buf, b, "", "  "); err != nil {
+					return err
+				}
+				buf.WriteByte('\n')
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			return writeOutput(cmd, "-", buf.Bytes())
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type headerSetFlags struct {
+	batchFlags
+	width         int
+	height        int
+	timestamp     int
+	duration      float64
+	idleTimeLimit float64
+	command       string
+	title         string
+	env           map[string]string
+	envUnset      []string
+	themeFile     string
+	themeFG       string
+	themeBG       string
+	themePalette  string
+	themeUnset    bool
+}
+
+func newHeaderSetCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(headerSetFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "set [FILE]...",
+		Short: "Update asciicast header, keeping events as is",
+		Example: `deltascii header set -i ascii.cast -o ascii.cast --title Demo --width 100
+deltascii header set -i ascii.cast -o ascii.cast --theme-file theme.json --env-unset HOME`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var fileTheme *asciinema.V2HeaderTheme
+			if flags.themeFile != "" {
+				var err error
+				if fileTheme, err = theme.LoadFile(flags.themeFile); err != nil {
+					return err
+				}
+			}
+
+			fs := cmd.Flags()
+			fn := func(h *asciinema.V2Header) error {
+				if fs.Changed("width") {
+					h.Width = flags.width
+				}
+				if fs.Changed("height") {
+					h.Height = flags.height
+				}
+				if fs.Changed("timestamp") {
+					h.Timestamp = flags.timestamp
+				}
+				if fs.Changed("duration") {
+					h.Duration = flags.duration
+				}
+				if fs.Changed("idle-time-limit") {
+					h.IdleTimeLimit = flags.idleTimeLimit
+				}
+				if fs.Changed("command") {
+					h.Command = flags.command
+				}
+				if fs.Changed("title") {
+					h.Title = flags.title
+				}
+
+				for k, v := range flags.env {
+					if h.Env == nil {
+						h.Env = make(map[string]string)
+					}
+					h.Env[k] = v
+				}
+				for _, k := range flags.envUnset {
+					delete(h.Env, k)
+				}
+				if len(h.Env) == 0 {
+					h.Env = nil
+				}
+
+				if flags.themeUnset {
+					h.Theme = nil
+				}
+				if fileTheme != nil {
+					// NOTE: copy the theme, since the flags below may change it for each file
+					t := *fileTheme
+					h.Theme = &t
+				}
+				if fs.Changed("theme-fg") || fs.Changed("theme-bg") || fs.Changed("theme-palette") {
+					if h.Theme == nil {
+						h.Theme = new(asciinema.V2HeaderTheme)
+					}
+					if fs.Changed("theme-fg") {
+						h.Theme.FG = flags.themeFG
+					}
+					if fs.Changed("theme-bg") {
+						h.Theme.BG = flags.themeBG
+					}
+					if fs.Changed("theme-palette") {
+						h.Theme.Palette = flags.themePalette
+					}
+				}
+
+				return nil
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := editASCIICastHeader(r, buf, job.stderr, fn); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+
+	cmd.Flags().IntVar(&flags.width, "width", 0, "terminal width (number of columns)")
+	cmd.Flags().IntVar(&flags.height, "height", 0, "terminal height (number of rows)")
+	cmd.Flags().IntVar(&flags.timestamp, "timestamp", 0, "unix timestamp of the beginning of the recording session")
+	cmd.Flags().Float64Var(&flags.duration, "duration", 0, "duration of the whole recording in seconds (0 to remove)")
+	cmd.Flags().Float64Var(&flags.idleTimeLimit, "idle-time-limit", 0, "idle time limit in seconds (0 to remove)")
+	cmd.Flags().StringVar(&flags.command, "command", "", `command that was recorded ("" to remove)`)
+	cmd.Flags().StringVar(&flags.title, "title", "", `title of the asciicast ("" to remove)`)
+	cmd.Flags().StringToStringVar(&flags.env, "env", nil, "environment variables to set (KEY=VALUE)")
+	cmd.Flags().StringSliceVar(&flags.envUnset, "env-unset", nil, "environment variables to remove")
+	cmd.Flags().StringVar(&flags.themeFile, "theme-file", "", "theme file (asciicast theme JSON, iTerm2 .itermcolors, Windows Terminal JSON or Xresources)")
+	cmd.Flags().StringVar(&flags.themeFG, "theme-fg", "", "theme foreground color (#rrggbb)")
+	cmd.Flags().StringVar(&flags.themeBG, "theme-bg", "", "theme background color (#rrggbb)")
+	cmd.Flags().StringVar(&flags.themePalette, "theme-palette", "", "theme palette (8 or 16 #rrggbb colors joined by colon)")
+	cmd.Flags().BoolVar(&flags.themeUnset, "theme-unset", false, "remove theme")
+
+	cmd.MarkFlagsMutuallyExclusive("theme-file", "theme-unset")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+func splitASCIICast(r io.Reader) (header []byte, events io.Reader, err error) {
+	// NOTE: decode the first JSON value, so that a header may span lines
+	dec := json.NewDecoder(r)
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("missing header")
+		}
+		return nil, nil, err
+	}
+
+	// NOTE: skip the rest of the header line, so that events start on a new line
+	br := bufio.NewReader(io.MultiReader(dec.Buffered(), r))
+	rest, err := br.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, err
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, nil, fmt.Errorf("unexpected data after header: %s", bytes.TrimSpace(rest))
+	}
+
+	return raw, br, nil
+}
+
+func editASCIICastHeader(r io.Reader, w io.Writer, errW io.Writer, fn func(h *asciinema.V2Header) error) error {
+	line, events, err := splitASCIICast(r)
+	if err != nil {
+		return err
+	}
+
+	var h asciinema.V2Header
+	if err := json.Unmarshal(line, &h); err != nil {
+		return err
+	}
+	printWarnings(errW, &h)
+
+	if err := fn(&h); err != nil {
+		return err
+	}
+
+	if err := h.Validate(); err != nil {
+		return err
+	}
+
+	b, err := asciinema.PatchV2Header(line, &h)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	// NOTE: copy events as is to keep them byte-identical
+	if _, err := io.Copy(w, events); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderGetCommand(t *testing.T) {
+	type args struct {
+		input string
+		args  []string
+	}
+
+	type expected struct {
+		data   []byte
+		stderr []byte
+		errIs  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "testdata/test.cast",
+			},
+			expected: &expected{
+				data: []byte(`{
+  "version": 2,
+  "width": 80,
+  "height": 24,
+  "timestamp": 1504467315,
+  "env": {
+    "SHELL": "/bin/zsh",
+    "TERM": "xterm-256color"
+  }
+}
+`),
+				stderr: []byte(``),
+				errIs:  nil,
+			},
+		},
+		{
+			name: "happy path: unknown and mistyped fields",
+			args: &args{
+				input: "testdata/extra.cast",
+			},
+			expected: &expected{
+				data: []byte(`{
+  "version": 2,
+  "width": 80,
+  "height": 24,
+  "timestamp": "1504467315",
+  "term": {
+    "type": "xterm-256color"
+  },
+  "x-vendor": true
+}
+`),
+				stderr: []byte(`warning: invalid header timestamp: "1504467315"
+`),
+				errIs: nil,
+			},
+		},
+		{
+			name: "happy path: multiple files",
+			args: &args{
+				input: "testdata/test.cast",
+				args:  []string{"testdata/extra.cast"},
+			},
+			expected: &expected{
+				data: []byte(`==> testdata/test.cast <==
+{
+  "version": 2,
+  "width": 80,
+  "height": 24,
+  "timestamp": 1504467315,
+  "env": {
+    "SHELL": "/bin/zsh",
+    "TERM": "xterm-256color"
+  }
+}
+
+==> testdata/extra.cast <==
+{
+  "version": 2,
+  "width": 80,
+  "height": 24,
+  "timestamp": "1504467315",
+  "term": {
+    "type": "xterm-256color"
+  },
+  "x-vendor": true
+}
+`),
+				stderr: []byte(`testdata/extra.cast: warning: invalid header timestamp: "1504467315"
+`),
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				data:  nil,
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newHeaderGetCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input}, tt.args.args...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.Equal(t, string(tt.expected.stderr), stderr.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
+
+func TestHeaderSetCommand(t *testing.T) {
+	events, _ := os.ReadFile("testdata/test.cast")
+	events = events[bytes.IndexByte(events, '\n')+1:]
+
+	themeFile := filepath.Join(t.TempDir(), "theme.json")
+	_ = os.WriteFile(themeFile, []byte(`{"fg":"#d0d0d0","bg":"#212121","palette":"#151515:#ac4142:#7e8e50:#e5b567:#6c99bb:#9f4e85:#7dd6cf:#d0d0d0"}`), 0o644)
+
+	type args struct {
+		flags []string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: no changes",
+			args: &args{
+				flags: []string{},
+			},
+			expected: &expected{
+				data: append([]byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}}
+`), events...),
+			},
+		},
+		{
+			name: "happy path: title, size and env",
+			args: &args{
+				flags: []string{"--title", "<Demo>", "--width", "100", "--height", "30", "--env", "LANG=C", "--env-unset", "SHELL"},
+			},
+			expected: &expected{
+				data: append([]byte(`{"version": 2, "width": 100, "height": 30, "timestamp": 1504467315, "env": {"LANG": "C", "TERM": "xterm-256color"}, "title": "<Demo>"}
+`), events...),
+			},
+		},
+		{
+			name: "happy path: theme file",
+			args: &args{
+				flags: []string{"--theme-file", themeFile, "--env-unset", "SHELL,TERM"},
+			},
+			expected: &expected{
+				data: append([]byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "theme": {"fg": "#d0d0d0", "bg": "#212121", "palette": "#151515:#ac4142:#7e8e50:#e5b567:#6c99bb:#9f4e85:#7dd6cf:#d0d0d0"}}
+`), events...),
+			},
+		},
+		{
+			name: "edge path: invalid width",
+			args: &args{
+				flags: []string{"--width", "0"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid header width: %v", 0),
+			},
+		},
+		{
+			name: "edge path: invalid theme palette",
+			args: &args{
+				flags: []string{"--theme-fg", "#ffffff", "--theme-bg", "#000000", "--theme-palette", "#000000:#ffffff"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid theme palette: %v (8 or 16 colors required, got %d)", "#000000:#ffffff", 2),
+			},
+		},
+		{
+			name: "edge path: theme file not exist",
+			args: &args{
+				flags: []string{"--theme-file", "testdata/not-exist/theme.json"},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newHeaderSetCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", "testdata/test.cast", "--output", "-"}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				if tt.expected.errIs != nil {
+					assert.ErrorIs(t, err, tt.expected.errIs)
+				}
+				if tt.expected.err != nil {
+					assert.Equal(t, tt.expected.err, err)
+				}
+			}
+		})
+	}
+}
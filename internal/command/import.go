@@ -0,0 +1,267 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/terminalizer"
+	"github.com/Aton-Kish/deltascii/internal/ttyrec"
+	"github.com/Aton-Kish/deltascii/internal/typescript"
+	"github.com/spf13/cobra"
+)
+
+func newImportCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "import",
+		Short: "Convert other recording formats into asciicast v2",
+		Args:  cobra.NoArgs,
+	})
+
+	cmd.AddCommand(
+		newImportTTYRecCommand(optFns...).Command,
+		newImportScriptCommand(optFns...).Command,
+		newImportTerminalizerCommand(optFns...).Command,
+	)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type importTTYRecFlags struct {
+	batchFlags
+	width  int
+	height int
+}
+
+func newImportTTYRecCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(importTTYRecFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "ttyrec [FILE]...",
+		Short: "Convert ttyrec into asciicast v2",
+		Long: `Convert ttyrec into asciicast v2.
+
+The terminal size is inferred from the first resize sequence ("ESC [ 8 ; height ; width t")
+unless --width and --height are given.
+`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				frames, err := ttyrec.Read(r)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := ttyrec.ToV2(frames, flags.width, flags.height)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := asciinema.WriteV2(buf, h, events); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input ttyrec files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+
+	cmd.Flags().IntVar(&flags.width, "width", 0, "terminal width (number of columns)")
+	cmd.Flags().IntVar(&flags.height, "height", 0, "terminal height (number of rows)")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type importScriptFlags struct {
+	batchFlags
+	timing string
+	logIn  string
+	width  int
+	height int
+}
+
+func newImportScriptCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(importScriptFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "script [FILE]...",
+		Short: "Convert util-linux script typescript and timing into asciicast v2",
+		Long: `Convert util-linux script typescript and timing into asciicast v2.
+
+Both the classic timing ("script -t") and the advanced timing ("script --log-timing") are supported.
+For the advanced timing, input is read from the typescript ("script --log-io")
+or from --log-in ("script --log-in"), SIGWINCH becomes resize events and other signals become markers.
+`,
+		Example: `deltascii import script -i typescript -t timing -o ascii.cast`,
+		Args:    cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.timing == "" && !flags.batch(cmd) {
+				return errors.New(`required flag(s) "timing" not set`)
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				out, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				// NOTE: in batch mode, the timing file is named after the typescript file
+				timingName := flags.timing
+				if timingName == "" {
+					timingName = replaceExt(job.input, ".timing")
+				}
+
+				timing, err := os.ReadFile(timingName)
+				if err != nil {
+					return err
+				}
+
+				var in io.Reader
+				if flags.logIn != "" {
+					data, err := os.ReadFile(flags.logIn)
+					if err != nil {
+						return err
+					}
+					in = bytes.NewReader(data)
+				}
+
+				h, events, err := typescript.ToV2(bytes.NewReader(timing), out, in, flags.width, flags.height)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := asciinema.WriteV2(buf, h, events); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input typescript files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+
+	cmd.Flags().StringVarP(&flags.timing, "timing", "t", "", "input timing file (named after input in batch mode)")
+	cmd.MarkFlagsMutuallyExclusive("timing", "output-dir")
+	cmd.MarkFlagsMutuallyExclusive("timing", "suffix")
+
+	cmd.Flags().StringVar(&flags.logIn, "log-in", "", "input log file when input is logged separately")
+	cmd.MarkFlagsMutuallyExclusive("log-in", "output-dir")
+	cmd.MarkFlagsMutuallyExclusive("log-in", "suffix")
+
+	cmd.Flags().IntVar(&flags.width, "width", 0, "terminal width (number of columns)")
+	cmd.Flags().IntVar(&flags.height, "height", 0, "terminal height (number of rows)")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type importTerminalizerFlags struct {
+	batchFlags
+	width  int
+	height int
+}
+
+func newImportTerminalizerCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(importTerminalizerFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "terminalizer [FILE]...",
+		Short: "Convert terminalizer YAML into asciicast v2",
+		Long: `Convert terminalizer YAML into asciicast v2.
+
+Record delays in milliseconds are accumulated into event times.
+The terminal size is taken from cols and rows of the config unless --width and --height are given.
+`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				rec, err := terminalizer.Read(r)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := terminalizer.ToV2(rec, flags.width, flags.height)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := asciinema.WriteV2(buf, h, events); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input terminalizer YAML files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+
+	cmd.Flags().IntVar(&flags.width, "width", 0, "terminal width (number of columns)")
+	cmd.Flags().IntVar(&flags.height, "height", 0, "terminal height (number of rows)")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
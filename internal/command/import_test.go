@@ -0,0 +1,262 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportTTYRecCommand(t *testing.T) {
+	type args struct {
+		input string
+		flags []string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				flags: []string{"--width", "80", "--height", "24"},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"timestamp":1504467315}
+[0,"o","h"]
+[0.1,"o","e"]
+[0.3,"o","l"]
+[0.6,"o","l"]
+[1,"o","o"]
+[1.5,"o"," "]
+[2.1,"o","w"]
+[2.8,"o","o"]
+[3.6,"o","r"]
+[4.5,"o","l"]
+[5.5,"o","d"]
+`),
+			},
+		},
+		{
+			name: "edge path: unknown size",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				flags: []string{},
+			},
+			expected: &expected{
+				err: errors.New("unable to infer terminal size, width and height required"),
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.ttyrec",
+				flags: []string{},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newImportTTYRecCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input, "--output", "-"}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				if tt.expected.errIs != nil {
+					assert.ErrorIs(t, err, tt.expected.errIs)
+				}
+				if tt.expected.err != nil {
+					assert.Equal(t, tt.expected.err, err)
+				}
+			}
+		})
+	}
+}
+
+func TestImportScriptCommand(t *testing.T) {
+	type args struct {
+		input string
+		flags []string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "../typescript/testdata/advanced.typescript",
+				flags: []string{"--timing", "../typescript/testdata/advanced.timing"},
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"timestamp":1504467315,"env":{"TERM":"xterm-256color"}}
+[0.5,"o","$ "]
+[0.6,"i","ls\r"]
+[0.6001,"o","ls\r\n"]
+[0.8001,"r","100x30"]
+[0.8501,"o","foo\r\n"]
+[0.9501,"m","SIGTERM"]
+[1.8501,"o","$ "]
+`),
+			},
+		},
+		{
+			name: "edge path: timing not exist",
+			args: &args{
+				input: "../typescript/testdata/advanced.typescript",
+				flags: []string{"--timing", "testdata/not-exist/timing"},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newImportScriptCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input, "--output", "-"}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
+
+func TestImportTerminalizerCommand(t *testing.T) {
+	type args struct {
+		input string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				input: "../terminalizer/testdata/demo.yml",
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"idle_time_limit":2,"command":"bash -l"}
+[0.5,"o","$ "]
+[0.6,"o","l"]
+[0.75,"o","s"]
+[1,"o","\r\nfoo\r\n$ "]
+`),
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/demo.yml",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newImportTerminalizerCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs([]string{"--input", tt.args.input, "--output", "-"})
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// NOTE: upper bounds of typing interval bins in seconds, the last bin is unbounded
+	typingIntervalBounds = []float64{0.05, 0.1, 0.2, 0.5, 1}
+)
+
+type infoFlags struct {
+	inputFlags
+	json bool
+	top  int
+}
+
+func newInfoCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(infoFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "info [FILE]...",
+		Short: "Show asciicast header and statistics",
+		Long: `Show asciicast header and statistics.
+
+With several files, each report follows a "==> FILE <==" heading, or with --json
+the reports make an array, each with the file name.
+`,
+		Example: `deltascii info -i ascii.cast
+deltascii info --json 'docs/casts/*.cast'`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buf := new(bytes.Buffer)
+			infos := make([]*castInfo, 0)
+			err := flags.each(cmd, args, func(input string, r io.Reader, errW io.Writer, multi bool) error {
+				info, err := inspectASCIICast(r, flags.top)
+				if err != nil {
+					return err
+				}
+				printWarnings(errW, &info.Header)
+
+				switch {
+				case flags.json && multi:
+					info.File = input
+					infos = append(infos, info)
+				case flags.json:
+					enc := json.NewEncoder(buf)
+					enc.SetIndent("", "  ")
+					return enc.Encode(info)
+				default:
+					if multi {
+						if buf.Len() > 0 {
+							buf.WriteByte('\n')
+						}
+						fmt.Fprintf(buf, "==> %s <==\n", input)
+					}
+					return info.WriteText(buf)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(infos) > 0 {
+				enc := json.NewEncoder(buf)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(infos); err != nil {
+					return err
+				}
+			}
+
+			return writeOutput(cmd, "-", buf.Bytes())
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`)
+
+	cmd.Flags().BoolVar(&flags.json, "json", false, "output in JSON format")
+	cmd.Flags().IntVar(&flags.top, "top", 5, "number of longest idle gaps to show")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type castInfo struct {
+	File        string             `json:"file,omitempty"`
+	Header      asciinema.V2Header `json:"header"`
+	Duration    castDuration       `json:"duration"`
+	Events      map[string]int     `json:"events"`
+	OutputBytes int                `json:"output_bytes"`
+	IdleGaps    []castIdleGap      `json:"idle_gaps"`
+	TypingRate  []castHistogramBin `json:"typing_rate"`
+	Resizes     []castResize       `json:"resizes"`
+	Markers     []castMarker       `json:"markers"`
+}
+
+type castDuration struct {
+	Real   float64 `json:"real"`
+	Header float64 `json:"header"`
+}
+
+type castIdleGap struct {
+	Index    int     `json:"index"`
+	Start    float64 `json:"start"`
+	End      float64 `json:"end"`
+	Duration float64 `json:"duration"`
+}
+
+type castHistogramBin struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int      `json:"count"`
+}
+
+type castResize struct {
+	Time   float64 `json:"time"`
+	Width  int     `json:"width"`
+	Height int     `json:"height"`
+}
+
+type castMarker struct {
+	Time  float64 `json:"time"`
+	Label string  `json:"label"`
+}
+
+func inspectASCIICast(r io.Reader, top int) (*castInfo, error) {
+	dec := json.NewDecoder(r)
+
+	info := &castInfo{
+		Events:     make(map[string]int),
+		IdleGaps:   make([]castIdleGap, 0),
+		TypingRate: make([]castHistogramBin, 0, len(typingIntervalBounds)+1),
+		Resizes:    make([]castResize, 0),
+		Markers:    make([]castMarker, 0),
+	}
+
+	if err := dec.Decode(&info.Header); err != nil {
+		return nil, err
+	}
+	info.Duration.Header = info.Header.Duration
+
+	inputs := make([]float64, 0)
+	echoes := make([]float64, 0)
+
+	prev := 0.0
+	for i := 0; dec.More(); i++ {
+		var e asciinema.V2Event
+		if err := dec.Decode(&e); err != nil {
+			return nil, err
+		}
+
+		at := e.Time.Float64()
+		if gap := subTime(at, prev); gap > 0 {
+			info.IdleGaps = append(info.IdleGaps, castIdleGap{Index: i, Start: prev, End: at, Duration: gap})
+		}
+		prev = at
+		info.Duration.Real = at
+
+		info.Events[e.Code]++
+
+		data, _ := e.Data.(string)
+		switch e.Code {
+		case "o":
+			info.OutputBytes += len(data)
+			if r, size := utf8.DecodeRuneInString(data); size > 0 && size == len(data) && unicode.IsPrint(r) {
+				echoes = append(echoes, at)
+			}
+		case "i":
+			inputs = append(inputs, at)
+		case "r":
+			var w, h int
+			if _, err := fmt.Sscanf(data, "%dx%d", &w, &h); err != nil {
+				return nil, fmt.Errorf("invalid resize event data: %v", e.Data)
+			}
+			info.Resizes = append(info.Resizes, castResize{Time: at, Width: w, Height: h})
+		case "m":
+			info.Markers = append(info.Markers, castMarker{Time: at, Label: data})
+		}
+	}
+
+	slices.SortStableFunc(info.IdleGaps, func(a, b castIdleGap) int {
+		return decimal.NewFromFloat(b.Duration).Cmp(decimal.NewFromFloat(a.Duration))
+	})
+	if len(info.IdleGaps) > top {
+		info.IdleGaps = info.IdleGaps[:max(top, 0)]
+	}
+
+	// NOTE: asciinema does not record stdin by default, so fall back to echoed characters
+	keys := inputs
+	if len(keys) == 0 {
+		keys = echoes
+	}
+
+	lower := 0.0
+	for i := range typingIntervalBounds {
+		info.TypingRate = append(info.TypingRate, castHistogramBin{Min: lower, Max: &typingIntervalBounds[i]})
+		lower = typingIntervalBounds[i]
+	}
+	info.TypingRate = append(info.TypingRate, castHistogramBin{Min: lower})
+
+	for i := 1; i < len(keys); i++ {
+		interval := subTime(keys[i], keys[i-1])
+		idx, _ := slices.BinarySearch(typingIntervalBounds, interval)
+		if idx < len(typingIntervalBounds) && typingIntervalBounds[idx] == interval {
+			idx++
+		}
+		info.TypingRate[idx].Count++
+	}
+
+	return info, nil
+}
+
+func (i *castInfo) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	h := i.Header
+	fmt.Fprintln(tw, "Header:")
+	fmt.Fprintf(tw, "  version:\t%d\n", h.Version)
+	fmt.Fprintf(tw, "  size:\t%dx%d\n", h.Width, h.Height)
+	if h.Timestamp != 0 {
+		fmt.Fprintf(tw, "  timestamp:\t%d (%s)\n", h.Timestamp, time.Unix(int64(h.Timestamp), 0).UTC().Format(time.RFC3339))
+	}
+	if h.IdleTimeLimit != 0 {
+		fmt.Fprintf(tw, "  idle time limit:\t%s\n", formatSeconds(h.IdleTimeLimit))
+	}
+	if h.Command != "" {
+		fmt.Fprintf(tw, "  command:\t%s\n", h.Command)
+	}
+	if h.Title != "" {
+		fmt.Fprintf(tw, "  title:\t%s\n", h.Title)
+	}
+	for _, k := range sortedKeys(h.Env) {
+		fmt.Fprintf(tw, "  env %s:\t%s\n", k, h.Env[k])
+	}
+	if h.Theme != nil {
+		fmt.Fprintf(tw, "  theme:\tfg=%s bg=%s palette=%s\n", h.Theme.FG, h.Theme.BG, h.Theme.Palette)
+	}
+	for _, k := range sortedKeys(h.Extra) {
+		fmt.Fprintf(tw, "  %s:\t%s\n", k, h.Extra[k])
+	}
+
+	fmt.Fprintln(tw, "Duration:")
+	fmt.Fprintf(tw, "  real:\t%s\n", formatSeconds(i.Duration.Real))
+	if i.Duration.Header != 0 {
+		diff := subTime(i.Duration.Header, i.Duration.Real)
+		fmt.Fprintf(tw, "  header:\t%s (%+gs)\n", formatSeconds(i.Duration.Header), diff)
+	} else {
+		fmt.Fprintln(tw, "  header:\t-")
+	}
+
+	fmt.Fprintln(tw, "Events:")
+	for _, code := range sortedKeys(i.Events) {
+		fmt.Fprintf(tw, "  %q:\t%d\n", code, i.Events[code])
+	}
+	fmt.Fprintf(tw, "Output bytes:\t%d\n", i.OutputBytes)
+
+	fmt.Fprintln(tw, "Longest idle gaps:")
+	for _, g := range i.IdleGaps {
+		fmt.Fprintf(tw, "  %s\tat %s (event #%d)\n", formatSeconds(g.Duration), formatSeconds(g.Start), g.Index)
+	}
+
+	fmt.Fprintln(tw, "Typing intervals:")
+	for _, b := range i.TypingRate {
+		var label string
+		if b.Max != nil {
+			label = fmt.Sprintf("%s-%s", formatSeconds(b.Min), formatSeconds(*b.Max))
+		} else {
+			label = fmt.Sprintf(">=%s", formatSeconds(b.Min))
+		}
+		fmt.Fprintf(tw, "  %s\t%d", label, b.Count)
+		if bar := histogramBar(b.Count, i.TypingRate); bar != "" {
+			fmt.Fprintf(tw, "\t%s", bar)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	fmt.Fprintln(tw, "Resizes:")
+	for _, r := range i.Resizes {
+		fmt.Fprintf(tw, "  %s\t%dx%d\n", formatSeconds(r.Time), r.Width, r.Height)
+	}
+
+	fmt.Fprintln(tw, "Markers:")
+	for _, m := range i.Markers {
+		fmt.Fprintf(tw, "  %s\t%s\n", formatSeconds(m.Time), m.Label)
+	}
+
+	return tw.Flush()
+}
+
+func histogramBar(count int, bins []castHistogramBin) string {
+	const width = 40
+
+	peak := 0
+	for _, b := range bins {
+		peak = max(peak, b.Count)
+	}
+	if peak == 0 {
+		return ""
+	}
+
+	n := count * width / peak
+	if n == 0 && count > 0 {
+		n = 1
+	}
+
+	return string(bytes.Repeat([]byte("#"), n))
+}
+
+func subTime(a, b float64) float64 {
+	return decimal.NewFromFloat(a).Sub(decimal.NewFromFloat(b)).InexactFloat64()
+}
+
+func formatSeconds(s float64) string {
+	return fmt.Sprintf("%gs", s)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfoCommand(t *testing.T) {
+	cast := []byte(`{"version": 2, "width": 80, "height": 24, "duration": 3, "title": "Demo"}
+[0.5, "i", "l"]
+[0.6, "o", "l"]
+[0.7, "i", "s"]
+[0.8, "o", "s"]
+[1, "i", "\r"]
+[1.2, "o", "\r\nfoo bar\r\n"]
+[2, "r", "100x30"]
+[2.5, "m", "done"]
+`)
+
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.cast"), []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+`), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "b.cast"), []byte(`{"version": 2, "width": 80, "height": "24"}
+[1, "m", "b"]
+`), 0o644)
+
+	type args struct {
+		input string
+		stdin []byte
+		flags []string
+	}
+
+	type expected struct {
+		data   []byte
+		stderr string
+		errIs  error
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: text",
+			args: &args{
+				input: "-",
+				stdin: cast,
+				flags: []string{"--top", "2"},
+			},
+			expected: &expected{
+				data: []byte(`Header:
+  version:  2
+  size:     80x24
+  title:    Demo
+Duration:
+  real:    2.5s
+  header:  3s (+0.5s)
+Events:
+  "i":         3
+  "m":         1
+  "o":         3
+  "r":         1
+Output bytes:  13
+Longest idle gaps:
+  0.8s  at 1.2s (event #6)
+  0.5s  at 0s (event #0)
+Typing intervals:
+  0s-0.05s    0
+  0.05s-0.1s  0
+  0.1s-0.2s   0
+  0.2s-0.5s   2  ########################################
+  0.5s-1s     0
+  >=1s        0
+Resizes:
+  2s  100x30
+Markers:
+  2.5s  done
+`),
+			},
+		},
+		{
+			name: "happy path: json",
+			args: &args{
+				input: "testdata/test.cast",
+				flags: []string{"--json", "--top", "1"},
+			},
+			expected: &expected{
+				data: []byte(`{
+  "header": {
+    "version": 2,
+    "width": 80,
+    "height": 24,
+    "timestamp": 1504467315,
+    "env": {
+      "SHELL": "/bin/zsh",
+      "TERM": "xterm-256color"
+    }
+  },
+  "duration": {
+    "real": 5.5,
+    "header": 0
+  },
+  "events": {
+    "o": 11
+  },
+  "output_bytes": 11,
+  "idle_gaps": [
+    {
+      "index": 10,
+      "start": 4.5,
+      "end": 5.5,
+      "duration": 1
+    }
+  ],
+  "typing_rate": [
+    {
+      "min": 0,
+      "max": 0.05,
+      "count": 0
+    },
+    {
+      "min": 0.05,
+      "max": 0.1,
+      "count": 0
+    },
+    {
+      "min": 0.1,
+      "max": 0.2,
+      "count": 1
+    },
+    {
+      "min": 0.2,
+      "max": 0.5,
+      "count": 3
+    },
+    {
+      "min": 0.5,
+      "max": 1,
+      "count": 5
+    },
+    {
+      "min": 1,
+      "max": null,
+      "count": 1
+    }
+  ],
+  "resizes": [],
+  "markers": []
+}
+`),
+			},
+		},
+		{
+			name: "happy path: multiple files",
+			args: &args{
+				input: filepath.Join(dir, "*.cast"),
+				flags: []string{"--top", "0"},
+			},
+			expected: &expected{
+				data: []byte(fmt.Sprintf(`==> %s <==
+Header:
+  version:  2
+  size:     80x24
+Duration:
+  real:    0.5s
+  header:  -
+Events:
+  "o":         1
+Output bytes:  1
+Longest idle gaps:
+Typing intervals:
+  0s-0.05s    0
+  0.05s-0.1s  0
+  0.1s-0.2s   0
+  0.2s-0.5s   0
+  0.5s-1s     0
+  >=1s        0
+Resizes:
+Markers:
+
+==> %s <==
+Header:
+  version:  2
+  size:     80x0
+  height:   "24"
+Duration:
+  real:    1s
+  header:  -
+Events:
+  "m":         1
+Output bytes:  0
+Longest idle gaps:
+Typing intervals:
+  0s-0.05s    0
+  0.05s-0.1s  0
+  0.1s-0.2s   0
+  0.2s-0.5s   0
+  0.5s-1s     0
+  >=1s        0
+Resizes:
+Markers:
+  1s  b
+`, filepath.Join(dir, "a.cast"), filepath.Join(dir, "b.cast"))),
+				stderr: fmt.Sprintf("%s: warning: invalid header height: \"24\"\n", filepath.Join(dir, "b.cast")),
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				input: "testdata/not-exist/test.cast",
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+		{
+			name: "edge path: invalid resize event",
+			args: &args{
+				input: "-",
+				stdin: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "r", "large"]
+`),
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid resize event data: %v", "large"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := bytes.NewReader(tt.args.stdin)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newInfoCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", tt.args.input}, tt.args.flags...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.Equal(t, tt.expected.stderr, stderr.String())
+				assert.NoError(t, err)
+			} else {
+				if tt.expected.errIs != nil {
+					assert.ErrorIs(t, err, tt.expected.errIs)
+				}
+				if tt.expected.err != nil {
+					assert.Equal(t, tt.expected.err, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/castdiff"
+	"github.com/spf13/cobra"
+)
+
+const (
+	maxLineSize = 1 << 30
+)
+
+type mergeFlags struct {
+	output     string
+	markerSize int
+}
+
+func newMergeCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(mergeFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "merge BASE OURS THEIRS",
+		Short: "Merge two edited asciicasts against their common base",
+		Long: `Merge two edited asciicasts against their common base, event by event.
+
+Insertions, deletions and retimings made on either side are combined.
+Only events changed differently on both sides conflict, and are written between conflict markers.
+The command fails if any conflict remains, so it can serve as a git merge driver.
+
+Δ files merge best, since deleting or inserting an event leaves the time of every other event untouched.
+`,
+		Example: `deltascii merge base.delta.cast ours.delta.cast theirs.delta.cast -o merged.delta.cast
+
+# .git/config
+[merge "deltascii"]
+	name = deltascii event merge
+	driver = deltascii merge %O %A %B -o %A --marker-size %L`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			casts := make([]*castLines, 0, len(args))
+			for _, name := range args {
+				r, err := readInput(cmd, name)
+				if err != nil {
+					return err
+				}
+
+				c, err := readCastLines(r, cmd.ErrOrStderr())
+				if err != nil {
+					return err
+				}
+
+				casts = append(casts, c)
+			}
+
+			buf := new(bytes.Buffer)
+			conflicts, err := mergeASCIICasts(buf, casts[0], casts[1], casts[2], flags.markerSize)
+			if err != nil {
+				return err
+			}
+
+			if err := writeOutput(cmd, flags.output, buf.Bytes()); err != nil {
+				return err
+			}
+
+			if conflicts > 0 {
+				return fmt.Errorf("merge conflicts: %d", conflicts)
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+	})
+
+	cmd.Flags().StringVarP(&flags.output, "output", "o", "", `output asciicast v2 file or "-" (write to stdout)`)
+	_ = cmd.MarkFlagRequired("output")
+
+	cmd.Flags().IntVar(&flags.markerSize, "marker-size", 7, "length of conflict markers")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+// castLines is an asciicast along with its original lines.
+type castLines struct {
+	headerLine []byte
+	header     *asciinema.V2Header
+	eventLines [][]byte
+	events     []asciinema.V2Event
+}
+
+func readCastLines(r io.Reader, errW io.Writer) (*castLines, error) {
+	line, rest, err := splitASCIICast(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &castLines{
+		headerLine: bytes.TrimSpace(line),
+		header:     new(asciinema.V2Header),
+		eventLines: make([][]byte, 0),
+		events:     make([]asciinema.V2Event, 0),
+	}
+
+	if err := json.Unmarshal(c.headerLine, c.header); err != nil {
+		return nil, err
+	}
+	printWarnings(errW, c.header)
+
+	sc := bufio.NewScanner(rest)
+	sc.Buffer(nil, maxLineSize)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var e asciinema.V2Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, err
+		}
+
+		c.eventLines = append(c.eventLines, append([]byte(nil), line...))
+		c.events = append(c.events, e)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// mergeASCIICasts writes the merge of ours and theirs, keeping the original
+// lines of the side each event comes from, and returns the number of conflicts.
+func mergeASCIICasts(w io.Writer, base, ours, theirs *castLines, markerSize int) (int, error) {
+	sides := map[castdiff.Side]*castLines{
+		castdiff.SideOurs:   ours,
+		castdiff.SideTheirs: theirs,
+	}
+
+	conflicts := 0
+	writeConflict := func(o, t [][]byte) {
+		conflicts++
+		fmt.Fprintf(w, "%s ours\n", strings.Repeat("<", markerSize))
+		for _, line := range o {
+			fmt.Fprintf(w, "%s\n", line)
+		}
+		fmt.Fprintf(w, "%s\n", strings.Repeat("=", markerSize))
+		for _, line := range t {
+			fmt.Fprintf(w, "%s\n", line)
+		}
+		fmt.Fprintf(w, "%s theirs\n", strings.Repeat(">", markerSize))
+	}
+
+	lines := func(refs []castdiff.Ref) [][]byte {
+		ls := make([][]byte, 0, len(refs))
+		for _, r := range refs {
+			ls = append(ls, sides[r.Side].eventLines[r.Index])
+		}
+
+		return ls
+	}
+
+	h, keys, err := castdiff.MergeHeader(base.header, ours.header, theirs.header)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(keys) > 0 {
+		writeConflict([][]byte{ours.headerLine}, [][]byte{theirs.headerLine})
+	} else {
+		line, err := asciinema.PatchV2Header(ours.headerLine, h)
+		if err != nil {
+			return 0, err
+		}
+
+		fmt.Fprintf(w, "%s\n", line)
+	}
+
+	for _, hunk := range castdiff.Merge(base.events, ours.events, theirs.events) {
+		if hunk.Conflict {
+			writeConflict(lines(hunk.Ours), lines(hunk.Theirs))
+			continue
+		}
+
+		for _, line := range lines(hunk.Events) {
+			fmt.Fprintf(w, "%s\n", line)
+		}
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeCommand(t *testing.T) {
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data  string
+		errIs error
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				args: []string{"testdata/merge/base.delta.cast", "testdata/merge/ours.delta.cast", "testdata/merge/theirs.delta.cast"},
+			},
+			expected: &expected{
+				data: `{"version":2,"width":80,"height":24,"title":"hello","timestamp":1504467315,"env":{"SHELL":"/bin/zsh","TERM":"xterm-256color"}}
+[0.5,"o","h"]
+[0.1,"o","e"]
+[0.2,"o","l"]
+[0.3,"o","l"]
+[0.4,"o","o"]
+[0.5,"o"," "]
+[0.6,"o","W"]
+[0.7,"o","o"]
+[0.8,"o","r"]
+[0.9,"o","l"]
+[2,"m","end"]
+`,
+			},
+		},
+		{
+			name: "happy path: deletions of equal events",
+			args: &args{
+				args: []string{"testdata/merge/base.delta.cast", "testdata/merge/delete-ours.delta.cast", "testdata/merge/delete-theirs.delta.cast"},
+			},
+			expected: &expected{
+				data: `{"version":2,"width":80,"height":24,"timestamp":1504467315,"env":{"SHELL":"/bin/zsh","TERM":"xterm-256color"}}
+[0,"o","h"]
+[0.1,"o","e"]
+[0.4,"o","o"]
+[0.5,"o"," "]
+[0.6,"o","w"]
+[0.7,"o","o"]
+[0.8,"o","r"]
+[0.9,"o","l"]
+[1,"o","d"]
+`,
+			},
+		},
+		{
+			name: "edge path: conflict",
+			args: &args{
+				args: []string{"testdata/merge/base.delta.cast", "testdata/merge/ours.delta.cast", "testdata/merge/conflict.delta.cast", "--marker-size", "3"},
+			},
+			expected: &expected{
+				data: `{"version":2,"width":80,"height":24,"title":"hello","timestamp":1504467315,"env":{"SHELL":"/bin/zsh","TERM":"xterm-256color"}}
+[0,"o","h"]
+[0.1,"o","e"]
+[0.2,"o","l"]
+[0.3,"o","l"]
+[0.4,"o","o"]
+[0.5,"o"," "]
+<<< ours
+[0.6,"o","W"]
+===
+[1.2,"o","w"]
+>>> theirs
+[0.7,"o","o"]
+[0.8,"o","r"]
+[0.9,"o","l"]
+[1,"o","d"]
+`,
+				err: fmt.Errorf("merge conflicts: %d", 1),
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				args: []string{"testdata/merge/base.delta.cast", "testdata/not-exist/test.cast", "testdata/merge/theirs.delta.cast"},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newMergeCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--output", "-"}, tt.args.args...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			assert.Equal(t, tt.expected.data, stdout.String())
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+			} else if tt.expected.errIs != nil {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/spf13/cobra"
+)
+
+type quantizeFlags struct {
+	batchFlags
+	durationFlags
+	step time.Duration
+}
+
+func newQuantizeCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(quantizeFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "quantize [FILE]...",
+		Short: "Round Δ times to a grid",
+		Long: `Round Δ times to a grid, so that a Δ-asciicast is easier to edit by hand.
+
+Rounding remainders carry over to the next event, so that every event stays
+within half a step of its original time, as does the total duration. Given
+asciicast v2, event times are rounded the same way and the output is asciicast
+v2, where events already on the grid keep their times as written.
+`,
+		Example: `deltascii quantize -i ascii.delta.cast -o ascii.delta.cast --step 10ms
+deltascii quantize docs/deltas/*.cast --in-place --step 50ms`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.step <= 0 {
+				return fmt.Errorf("invalid step: %v", flags.step)
+			}
+
+			mode, err := transform.ParseDurationMode(flags.duration)
+			if err != nil {
+				return err
+			}
+
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				data, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+
+				var t transform.Transform
+				switch f, _ := asciinema.DetectFormat(data); f {
+				case asciinema.FormatDeltaV2:
+					t = transform.Quantize(flags.step.Seconds())
+				case asciinema.FormatV2:
+					t = transform.Chain(transform.QuantizeTimes(flags.step.Seconds()), transform.Duration(mode))
+				default:
+					return fmt.Errorf("input is %v, not %v or %v", f, asciinema.FormatV2, asciinema.FormatDeltaV2)
+				}
+
+				buf := new(bytes.Buffer)
+				if err := convertASCIICast(bytes.NewReader(data), buf, job.stderr, t); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input Δ-asciicast v2 or asciicast v2 files, globs or "-" (read from stdin)`, `output file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+	flags.registerDuration(cmd.Command)
+
+	cmd.Flags().DurationVar(&flags.step, "step", 10*time.Millisecond, "grid step, such as 10ms")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantizeCommand(t *testing.T) {
+	scales := filepath.Join(t.TempDir(), "scales.cast")
+	_ = os.WriteFile(scales, []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[1.25, "o", "b"]
+[2, "o", "c"]
+[2.5, "o", "d"]
+[3.10, "o", "e"]
+[4.123456, "o", "f"]
+[5.0, "o", "g"]
+`), 0o644)
+
+	type args struct {
+		input string
+		args  []string
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: Δ-asciicast",
+			args: &args{
+				input: "testdata/test.delta.cast",
+				args:  []string{"--step", "250ms"},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}, "deltascii": {"version": 1, "duration": 5.5}}
+[0, "o", "h"]
+[0, "o", "e"]
+[0.25, "o", "l"]
+[0.25, "o", "l"]
+[0.5, "o", "o"]
+[0.5, "o", " "]
+[0.5, "o", "w"]
+[0.75, "o", "o"]
+[0.75, "o", "r"]
+[1, "o", "l"]
+[1, "o", "d"]
+`),
+			},
+		},
+		{
+			name: "happy path: asciicast",
+			args: &args{
+				input: "testdata/test.cast",
+				args:  []string{"--step", "1s"},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}}
+[0, "o", "h"]
+[0, "o", "e"]
+[0, "o", "l"]
+[1, "o", "l"]
+[1, "o", "o"]
+[2, "o", " "]
+[2, "o", "w"]
+[3, "o", "o"]
+[4, "o", "r"]
+[5, "o", "l"]
+[6, "o", "d"]
+`),
+			},
+		},
+		{
+			name: "happy path: asciicast on the grid",
+			args: &args{
+				input: scales,
+				args:  []string{"--step", "500ms"},
+			},
+			expected: &expected{
+				data: []byte(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "a"]
+[1.5, "o", "b"]
+[2, "o", "c"]
+[2.5, "o", "d"]
+[3, "o", "e"]
+[4, "o", "f"]
+[5.0, "o", "g"]
+`),
+			},
+		},
+		{
+			name: "edge path: invalid step",
+			args: &args{
+				input: "testdata/test.cast",
+				args:  []string{"--step", "0s"},
+			},
+			expected: &expected{
+				err: errors.New("invalid step: 0s"),
+			},
+		},
+		{
+			name: "edge path: ttyrec",
+			args: &args{
+				input: "testdata/test.ttyrec",
+				args:  []string{},
+			},
+			expected: &expected{
+				err: errors.New("input is ttyrec, not asciicast v2 or Δ-asciicast v2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newQuantizeCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append(tt.args.args, "--input", tt.args.input, "--output", "-"))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/synth"
+	"github.com/spf13/cobra"
+)
+
+func newSynthCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(batchFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "synth [SCRIPT]...",
+		Short: "Generate asciicast v2 from a script",
+		Long: `Generate asciicast v2 from a script, for casts reproducible from source.
+
+The script is a YAML file with the terminal and the steps to play in order:
+
+  width: 80           # terminal size, 80x24 by default
+  height: 24
+  title: Demo
+  env: {SHELL: /bin/bash, TERM: xterm-256color}
+  theme: dracula      # bundled theme name
+  shell: /bin/bash    # shell running commands, /bin/sh by default
+  cadence: 80ms       # time between typed characters, 100ms by default
+  steps:
+    - print: "$ "     # write output at once
+    - type: ls -la    # type text, then press enter unless enter is false
+      cadence: 120ms
+      run: true       # run the text with the shell and record its output
+      timeout: 10s    # kill the command after this long, 1m by default
+    - pause: 2s       # wait
+    - marker: listed  # add a marker
+    - resize: 100x30  # resize the terminal
+    - clear           # clear the screen
+
+Commands run in the directory of the script, on a pseudo terminal of the
+terminal size, and their output is timed as it comes. Nothing is typed into
+them, so their input ends at once. Running commands is only supported on Linux.
+`,
+		Example: `deltascii synth demo.yaml -o demo.cast
+deltascii synth docs/scripts/*.yaml --output-dir docs/casts`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return flags.run(cmd, args, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				dir := "."
+				if job.input != "-" {
+					dir = filepath.Dir(job.input)
+				}
+
+				s, err := synth.Parse(r, dir)
+				if err != nil {
+					return err
+				}
+
+				h, events, err := s.Generate(cmd.Context())
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := asciinema.WriteV2(buf, h, events); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command, `input script files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynthCommand(t *testing.T) {
+	type args struct {
+		script string
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				script: `title: Demo
+cadence: 200ms
+steps:
+  - print: "$ "
+  - pause: 1s
+  - type: ls
+  - marker: typed
+`,
+			},
+			expected: &expected{
+				data: []byte(`{"version":2,"width":80,"height":24,"title":"Demo"}
+[0,"o","$ "]
+[1,"o","l"]
+[1.2,"o","s"]
+[1.4,"o","\r\n"]
+[1.4,"m","typed"]
+`),
+			},
+		},
+		{
+			name: "edge path: invalid script",
+			args: &args{
+				script: `steps:
+  - pause: soon
+`,
+			},
+			expected: &expected{
+				err: errors.New("line 2: invalid duration: soon"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := strings.NewReader(tt.args.script)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newSynthCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs([]string{"--input", "-", "--output", "-"})
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/vt"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+type gitTextconvFlags struct {
+	delta bool
+}
+
+func newGitTextconvCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(gitTextconvFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "git-textconv FILE",
+		Short: "Render asciicast as stable text for git diff",
+		Long: `Render asciicast as stable text for git diff.
+
+The header is pretty printed, and each event is written on its own line with its Δ time, code and data.
+Control characters and escape sequences are spelled out, such as <CR><LF> and <SGR 1;32>.
+Since Δ times do not ripple, an edit shows up as a change of the edited events only.
+A Δ file is told by its deltascii header key, and --delta treats the input as one without it.
+`,
+		Example: `git config diff.deltascii.textconv "deltascii git-textconv"
+echo '*.cast diff=deltascii' >> .gitattributes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := readInput(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			buf := new(bytes.Buffer)
+			if err := textconvASCIICast(r, buf, cmd.ErrOrStderr(), flags.delta); err != nil {
+				return err
+			}
+
+			return writeOutput(cmd, "-", buf.Bytes())
+		},
+		SilenceUsage: true,
+	})
+
+	cmd.Flags().BoolVar(&flags.delta, "delta", false, "treat input as a Δ file even without a deltascii header key")
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+func textconvASCIICast(r io.Reader, w io.Writer, errW io.Writer, delta bool) error {
+	h, events, err := asciinema.ReadV2(r)
+	if err != nil {
+		return err
+	}
+	printWarnings(errW, h)
+
+	b, err := h.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	header := new(bytes.Buffer)
+	if err := json.Indent(header, b, "", "  "); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s\n", header)
+
+	// NOTE: a Δ file is told by its header, and --delta covers one without it
+	deltas, _ := eventDeltas(events, delta || h.Delta != nil)
+	for i, e := range events {
+		var data string
+		if s, ok := e.Data.(string); ok {
+			data = vt.Describe(s)
+		} else if data, err = marshalJSON(e.Data); err != nil {
+			return err
+		}
+
+		// NOTE: spell out trailing spaces, which a diff would not show
+		text := strings.TrimRight(data, " ")
+		data = text + strings.Repeat("<SP>", len(data)-len(text))
+
+		fmt.Fprintf(w, "%10s %s %s\n", decimal.NewFromFloat(deltas[i]).String(), e.Code, data)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitTextconvCommand(t *testing.T) {
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data  string
+		errIs error
+	}
+
+	text := `{
+  "version": 2,
+  "width": 80,
+  "height": 24,
+  "timestamp": 1504467315,
+  "env": {
+    "SHELL": "/bin/zsh",
+    "TERM": "xterm-256color"
+  }
+}
+         0 o h
+       0.1 o e
+       0.2 o l
+       0.3 o l
+       0.4 o o
+       0.5 o <SP>
+       0.6 o w
+       0.7 o o
+       0.8 o r
+       0.9 o l
+         1 o d
+`
+	deltaText := strings.Replace(text, "  }\n}\n", "  },\n  \"deltascii\": {\n    \"version\": 1,\n    \"duration\": 5.5\n  }\n}\n", 1)
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				args: []string{"testdata/test.cast"},
+			},
+			expected: &expected{
+				data: text,
+			},
+		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				args: []string{"--delta", "testdata/test.delta.cast"},
+			},
+			expected: &expected{
+				data: deltaText,
+			},
+		},
+		{
+			name: "happy path: delta detected by header",
+			args: &args{
+				args: []string{"testdata/test.delta.cast"},
+			},
+			expected: &expected{
+				data: deltaText,
+			},
+		},
+		{
+			name: "happy path: control sequences",
+			args: &args{
+				args: []string{"testdata/captions.cast"},
+			},
+			expected: &expected{
+				data: `{
+  "version": 2,
+  "width": 80,
+  "height": 24,
+  "idle_time_limit": 2
+}
+       0.5 m Listing files
+         0 o $<SP>
+       0.5 i l
+         0 o l
+       0.2 i s
+         0 o s
+       0.3 i <CR>
+         0 o <CR><LF>README.md<CR><LF>$<SP>
+         5 i exit<CR>
+         0 o exit<CR><LF>
+`,
+			},
+		},
+		{
+			name: "edge path: input not exist",
+			args: &args{
+				args: []string{"testdata/not-exist/test.cast"},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newGitTextconvCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(tt.args.args)
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
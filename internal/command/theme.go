@@ -0,0 +1,192 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/theme"
+	"github.com/spf13/cobra"
+)
+
+func newThemeCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "theme",
+		Short: "Manage asciicast header theme",
+		Args:  cobra.NoArgs,
+	})
+
+	cmd.AddCommand(
+		newThemeListCommand(optFns...).Command,
+		newThemeShowCommand(optFns...).Command,
+		newThemeApplyCommand(optFns...).Command,
+	)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+func newThemeListCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List bundled themes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			buf := new(bytes.Buffer)
+			for _, name := range theme.Names() {
+				fmt.Fprintln(buf, name)
+			}
+
+			return writeOutput(cmd, "-", buf.Bytes())
+		},
+		SilenceUsage: true,
+	})
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type themeSourceFlags struct {
+	file string
+}
+
+func (f *themeSourceFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.file, "file", "", "theme file (asciicast theme JSON, iTerm2 .itermcolors, Windows Terminal JSON or Xresources)")
+}
+
+func (f *themeSourceFlags) load(args []string) (*asciinema.V2HeaderTheme, error) {
+	switch {
+	case len(args) == 1 && f.file == "":
+		return theme.Lookup(args[0])
+	case len(args) == 0 && f.file != "":
+		return theme.LoadFile(f.file)
+	default:
+		return nil, errors.New("either theme name or --file is required")
+	}
+}
+
+type themeShowFlags struct {
+	themeSourceFlags
+}
+
+func newThemeShowCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(themeShowFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "show [NAME]",
+		Short: "Print theme as asciicast header theme JSON",
+		Example: `deltascii theme show dracula
+deltascii theme show --file Dracula.itermcolors > theme.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := flags.load(args)
+			if err != nil {
+				return err
+			}
+
+			buf := new(bytes.Buffer)
+			if err := json.NewEncoder(buf).Encode(t); err != nil {
+				return err
+			}
+
+			return writeOutput(cmd, "-", buf.Bytes())
+		},
+		SilenceUsage: true,
+	})
+
+	flags.register(cmd.Command)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+type themeApplyFlags struct {
+	themeSourceFlags
+	batchFlags
+}
+
+func newThemeApplyCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	flags := new(themeApplyFlags)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "apply [NAME]",
+		Short: "Set theme to asciicast header",
+		Example: `deltascii theme apply solarized-dark -i ascii.cast -o ascii.cast
+deltascii theme apply --file Dracula.itermcolors -i ascii.cast -o ascii.cast`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := flags.load(args)
+			if err != nil {
+				return err
+			}
+
+			return flags.run(cmd, nil, func(job *batchJob) error {
+				r, err := readInput(cmd, job.input)
+				if err != nil {
+					return err
+				}
+
+				buf := new(bytes.Buffer)
+				if err := editASCIICastHeader(r, buf, job.stderr, func(h *asciinema.V2Header) error {
+					h.Theme = t
+					return nil
+				}); err != nil {
+					return err
+				}
+
+				return writeOutput(cmd, job.output, buf.Bytes())
+			})
+		},
+		SilenceUsage: true,
+	})
+
+	flags.themeSourceFlags.register(cmd.Command)
+
+	flags.batchFlags.register(cmd.Command, `input asciicast v2 files, globs or "-" (read from stdin)`, `output asciicast v2 file or "-" (write to stdout)`, ".cast")
+	flags.registerInPlace(cmd.Command)
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
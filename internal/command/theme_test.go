@@ -0,0 +1,194 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeShowCommand(t *testing.T) {
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data  []byte
+		errIs error
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: bundled",
+			args: &args{
+				args: []string{"tango"},
+			},
+			expected: &expected{
+				data: []byte(`{"fg":"#d3d7cf","bg":"#2e3436","palette":"#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec"}
+`),
+			},
+		},
+		{
+			name: "happy path: file",
+			args: &args{
+				args: []string{"--file", "../theme/testdata/tango.itermcolors"},
+			},
+			expected: &expected{
+				data: []byte(`{"fg":"#d3d7cf","bg":"#2e3436","palette":"#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec"}
+`),
+			},
+		},
+		{
+			name: "edge path: unknown theme",
+			args: &args{
+				args: []string{"unknown"},
+			},
+			expected: &expected{
+				err: fmt.Errorf("unknown theme: %v", "unknown"),
+			},
+		},
+		{
+			name: "edge path: both name and file",
+			args: &args{
+				args: []string{"tango", "--file", "../theme/testdata/tango.itermcolors"},
+			},
+			expected: &expected{
+				err: errors.New("either theme name or --file is required"),
+			},
+		},
+		{
+			name: "edge path: file not exist",
+			args: &args{
+				args: []string{"--file", "testdata/not-exist/theme.json"},
+			},
+			expected: &expected{
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newThemeShowCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(tt.args.args)
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				if tt.expected.errIs != nil {
+					assert.ErrorIs(t, err, tt.expected.errIs)
+				}
+				if tt.expected.err != nil {
+					assert.Equal(t, tt.expected.err, err)
+				}
+			}
+		})
+	}
+}
+
+func TestThemeApplyCommand(t *testing.T) {
+	events, _ := os.ReadFile("testdata/test.cast")
+	events = events[bytes.IndexByte(events, '\n')+1:]
+
+	type args struct {
+		args []string
+	}
+
+	type expected struct {
+		data []byte
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				args: []string{"tango"},
+			},
+			expected: &expected{
+				data: append([]byte(`{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"SHELL": "/bin/zsh", "TERM": "xterm-256color"}, "theme": {"fg": "#d3d7cf", "bg": "#2e3436", "palette": "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec"}}
+`), events...),
+			},
+		},
+		{
+			name: "edge path: no theme",
+			args: &args{
+				args: []string{},
+			},
+			expected: &expected{
+				err: errors.New("either theme name or --file is required"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			cmd := newThemeApplyCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs(append([]string{"--input", "testdata/test.cast", "--output", "-"}, tt.args.args...))
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, string(tt.expected.data), stdout.String())
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var (
+	resizePattern = regexp.MustCompile(`^[1-9][0-9]*x[1-9][0-9]*$`)
+)
+
+func newValidateCommand(optFns ...func(o *options)) *xcommand {
+	opts := newOptions(optFns...)
+
+	cmd := newCommand(&cobra.Command{
+		Use:   "validate FILE...",
+		Short: "Check asciicast v2 and Δ-asciicast v2 files",
+		Long: `Check asciicast v2 and Δ-asciicast v2 files.
+
+Each problem is reported as FILE: line N: message, such as an invalid header, an
+unknown event code, an invalid resize, times going backwards or a header duration
+not matching the last event time. The command fails if any file has a problem.
+`,
+		Example: `deltascii validate ascii.cast
+deltascii validate 'docs/casts/*.cast'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inputs, err := expandInputs(args)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			invalid := 0
+			for _, input := range inputs {
+				r, err := readInput(cmd, input)
+				if err != nil {
+					return err
+				}
+
+				data, err := io.ReadAll(r)
+				if err != nil {
+					return err
+				}
+
+				problems := validateASCIICast(data)
+				for _, p := range problems {
+					fmt.Fprintf(out, "%v: %v\n", input, p)
+				}
+				if len(problems) > 0 {
+					invalid++
+				}
+			}
+
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d files invalid", invalid, len(inputs))
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+	})
+
+	cmd.SetIn(opts.stdio.in)
+	cmd.SetOutput(opts.stdio.out)
+	cmd.SetErr(opts.stdio.err)
+
+	return cmd
+}
+
+func validateASCIICast(data []byte) []error {
+	switch f, _ := asciinema.DetectFormat(data); f {
+	case asciinema.FormatV1, asciinema.FormatV3, asciinema.FormatTTYRec:
+		return []error{fmt.Errorf("line 1: %v, not asciicast v2", f)}
+	}
+
+	line, events, err := splitASCIICast(bytes.NewReader(data))
+	if err != nil {
+		return []error{fmt.Errorf("line 1: %w", err)}
+	}
+
+	rest, err := io.ReadAll(events)
+	if err != nil {
+		return []error{err}
+	}
+	// NOTE: a header may span lines, so number events after the lines it takes up
+	first := bytes.Count(data[:len(data)-len(rest)], []byte("\n")) + 1
+
+	var h asciinema.V2Header
+	if err := json.Unmarshal(line, &h); err != nil {
+		return []error{fmt.Errorf("line 1: invalid header: %w", err)}
+	}
+
+	problems := make([]error, 0)
+	for _, err := range h.Warnings() {
+		problems = append(problems, fmt.Errorf("line 1: %w", err))
+	}
+	if err := h.Validate(); err != nil {
+		problems = append(problems, fmt.Errorf("line 1: %w", err))
+	}
+
+	// NOTE: a Δ file is told by its header, as validation should not guess
+	delta := h.Delta != nil
+
+	eventProblems := make([]error, 0)
+	br := bufio.NewReader(bytes.NewReader(rest))
+	var last decimal.Decimal
+	for n := first; ; n++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return append(append(problems, eventProblems...), err)
+		}
+
+		if len(bytes.TrimSpace(b)) > 0 {
+			eventProblems = append(eventProblems, validateEvent(n, b, delta, &last)...)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	if !delta && h.Duration != 0 {
+		d := decimal.NewFromFloat(h.Duration)
+		if d.Sub(last).Abs().GreaterThan(asciinema.DurationTolerance) {
+			problems = append(problems, fmt.Errorf("line 1: header duration %v does not match last event time %v", d, last))
+		}
+	}
+
+	return append(problems, eventProblems...)
+}
+
+func validateEvent(n int, b []byte, delta bool, last *decimal.Decimal) []error {
+	var e asciinema.V2Event
+	if err := json.Unmarshal(b, &e); err != nil {
+		return []error{fmt.Errorf("line %d: invalid event: %w", n, err)}
+	}
+
+	problems := make([]error, 0)
+
+	at := e.Time.Decimal()
+	switch {
+	case at.IsNegative():
+		problems = append(problems, fmt.Errorf("line %d: negative time: %v", n, at))
+	case !delta && at.LessThan(*last):
+		problems = append(problems, fmt.Errorf("line %d: time goes backwards: %v < %v", n, at, *last))
+	}
+	if !delta {
+		*last = at
+	}
+
+	data, ok := e.Data.(string)
+	if !ok {
+		return append(problems, fmt.Errorf("line %d: invalid event data: %v", n, e.Data))
+	}
+
+	switch e.Code {
+	case "o", "i", "m":
+	case "r":
+		if !resizePattern.MatchString(data) {
+			problems = append(problems, fmt.Errorf("line %d: invalid resize: %v", n, data))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("line %d: unknown event code: %v", n, e.Code))
+	}
+
+	return problems
+}
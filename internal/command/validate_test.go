@@ -0,0 +1,167 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCommand(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		data string
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24, "duration": 1.5}
+[0.5, "o", "a"]
+[1.5, "r", "100x30"]
+`,
+			},
+			expected: &expected{
+				data: "",
+			},
+		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24, "duration": 9, "deltascii": {"version": 1, "duration": 9}}
+[0.5, "o", "a"]
+[0.25, "o", "b"]
+`,
+			},
+			expected: &expected{
+				data: "",
+			},
+		},
+		{
+			name: "edge path: duration mismatch",
+			args: &args{
+				data: `{"version": 2, "width": 80, "height": 24, "duration": 9}
+[0.5, "o", "a"]
+[1.5, "o", "b"]
+`,
+			},
+			expected: &expected{
+				data: "{file}: line 1: header duration 9 does not match last event time 1.5\n",
+				err:  errors.New("1 of 1 files invalid"),
+			},
+		},
+		{
+			name: "edge path: invalid events",
+			args: &args{
+				data: `{"version": 2, "width": 0, "height": 24}
+[1, "o", "a"]
+[0.5, "q", "b"]
+[2, "r", "100"]
+
+["2", "o", "c"]
+`,
+			},
+			expected: &expected{
+				data: `{file}: line 1: invalid header width: 0
+{file}: line 3: time goes backwards: 0.5 < 1
+{file}: line 3: unknown event code: q
+{file}: line 4: invalid resize: 100
+{file}: line 6: invalid event: invalid event time: 2
+`,
+				err: errors.New("1 of 1 files invalid"),
+			},
+		},
+		{
+			name: "edge path: pretty-printed header",
+			args: &args{
+				data: `{
+  "version": 2,
+  "width": 80,
+  "height": 24
+}
+[1, "o", "a"]
+[0.5, "q", "b"]
+`,
+			},
+			expected: &expected{
+				data: `{file}: line 7: time goes backwards: 0.5 < 1
+{file}: line 7: unknown event code: q
+`,
+				err: errors.New("1 of 1 files invalid"),
+			},
+		},
+		{
+			name: "edge path: v3",
+			args: &args{
+				data: `{"version": 3, "term": {"cols": 80, "rows": 24}}
+`,
+			},
+			expected: &expected{
+				data: "{file}: line 1: asciicast v3, not asciicast v2\n",
+				err:  errors.New("1 of 1 files invalid"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			stdin := new(bytes.Reader)
+			stdout := new(bytes.Buffer)
+			stderr := new(bytes.Buffer)
+
+			name := filepath.Join(t.TempDir(), "test.cast")
+			_ = os.WriteFile(name, []byte(tt.args.data), 0o644)
+
+			cmd := newValidateCommand(WithStdio(stdin, stdout, stderr))
+			cmd.SetArgs([]string{name})
+
+			// Act
+			err := cmd.ExecuteContext(ctx)
+
+			// Assert
+			assert.Equal(t, strings.ReplaceAll(tt.expected.data, "{file}", name), stdout.String())
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package render
+
+var (
+	// NOTE: font8x8 by Daniel Hepper (public domain), printable ASCII from U+0020;
+	// each byte is a row and the least significant bit is the leftmost pixel
+	asciiGlyphs = [95][8]byte{
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0020
+		{0x18, 0x3c, 0x3c, 0x18, 0x18, 0x00, 0x18, 0x00}, // U+0021 !
+		{0x36, 0x36, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0022 "
+		{0x36, 0x36, 0x7f, 0x36, 0x7f, 0x36, 0x36, 0x00}, // U+0023 #
+		{0x0c, 0x3e, 0x03, 0x1e, 0x30, 0x1f, 0x0c, 0x00}, // U+0024 $
+		{0x00, 0x63, 0x33, 0x18, 0x0c, 0x66, 0x63, 0x00}, // U+0025 %
+		{0x1c, 0x36, 0x1c, 0x6e, 0x3b, 0x33, 0x6e, 0x00}, // U+0026 &
+		{0x06, 0x06, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0027 '
+		{0x18, 0x0c, 0x06, 0x06, 0x06, 0x0c, 0x18, 0x00}, // U+0028 (
+		{0x06, 0x0c, 0x18, 0x18, 0x18, 0x0c, 0x06, 0x00}, // U+0029 )
+		{0x00, 0x66, 0x3c, 0xff, 0x3c, 0x66, 0x00, 0x00}, // U+002A *
+		{0x00, 0x0c, 0x0c, 0x3f, 0x0c, 0x0c, 0x00, 0x00}, // U+002B +
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x0c, 0x06}, // U+002C ,
+		{0x00, 0x00, 0x00, 0x3f, 0x00, 0x00, 0x00, 0x00}, // U+002D -
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x0c, 0x00}, // U+002E .
+		{0x60, 0x30, 0x18, 0x0c, 0x06, 0x03, 0x01, 0x00}, // U+002F /
+		{0x3e, 0x63, 0x73, 0x7b, 0x6f, 0x67, 0x3e, 0x00}, // U+0030 0
+		{0x0c, 0x0e, 0x0c, 0x0c, 0x0c, 0x0c, 0x3f, 0x00}, // U+0031 1
+		{0x1e, 0x33, 0x30, 0x1c, 0x06, 0x33, 0x3f, 0x00}, // U+0032 2
+		{0x1e, 0x33, 0x30, 0x1c, 0x30, 0x33, 0x1e, 0x00}, // U+0033 3
+		{0x38, 0x3c, 0x36, 0x33, 0x7f, 0x30, 0x78, 0x00}, // U+0034 4
+		{0x3f, 0x03, 0x1f, 0x30, 0x30, 0x33, 0x1e, 0x00}, // U+0035 5
+		{0x1c, 0x06, 0x03, 0x1f, 0x33, 0x33, 0x1e, 0x00}, // U+0036 6
+		{0x3f, 0x33, 0x30, 0x18, 0x0c, 0x0c, 0x0c, 0x00}, // U+0037 7
+		{0x1e, 0x33, 0x33, 0x1e, 0x33, 0x33, 0x1e, 0x00}, // U+0038 8
+		{0x1e, 0x33, 0x33, 0x3e, 0x30, 0x18, 0x0e, 0x00}, // U+0039 9
+		{0x00, 0x0c, 0x0c, 0x00, 0x00, 0x0c, 0x0c, 0x00}, // U+003A :
+		{0x00, 0x0c, 0x0c, 0x00, 0x00, 0x0c, 0x0c, 0x06}, // U+003B ;
+		{0x18, 0x0c, 0x06, 0x03, 0x06, 0x0c, 0x18, 0x00}, // U+003C <
+		{0x00, 0x00, 0x3f, 0x00, 0x00, 0x3f, 0x00, 0x00}, // U+003D =
+		{0x06, 0x0c, 0x18, 0x30, 0x18, 0x0c, 0x06, 0x00}, // U+003E >
+		{0x1e, 0x33, 0x30, 0x18, 0x0c, 0x00, 0x0c, 0x00}, // U+003F ?
+		{0x3e, 0x63, 0x7b, 0x7b, 0x7b, 0x03, 0x1e, 0x00}, // U+0040 @
+		{0x0c, 0x1e, 0x33, 0x33, 0x3f, 0x33, 0x33, 0x00}, // U+0041 A
+		{0x3f, 0x66, 0x66, 0x3e, 0x66, 0x66, 0x3f, 0x00}, // U+0042 B
+		{0x3c, 0x66, 0x03, 0x03, 0x03, 0x66, 0x3c, 0x00}, // U+0043 C
+		{0x1f, 0x36, 0x66, 0x66, 0x66, 0x36, 0x1f, 0x00}, // U+0044 D
+		{0x7f, 0x46, 0x16, 0x1e, 0x16, 0x46, 0x7f, 0x00}, // U+0045 E
+		{0x7f, 0x46, 0x16, 0x1e, 0x16, 0x06, 0x0f, 0x00}, // U+0046 F
+		{0x3c, 0x66, 0x03, 0x03, 0x73, 0x66, 0x7c, 0x00}, // U+0047 G
+		{0x33, 0x33, 0x33, 0x3f, 0x33, 0x33, 0x33, 0x00}, // U+0048 H
+		{0x1e, 0x0c, 0x0c, 0x0c, 0x0c, 0x0c, 0x1e, 0x00}, // U+0049 I
+		{0x78, 0x30, 0x30, 0x30, 0x33, 0x33, 0x1e, 0x00}, // U+004A J
+		{0x67, 0x66, 0x36, 0x1e, 0x36, 0x66, 0x67, 0x00}, // U+004B K
+		{0x0f, 0x06, 0x06, 0x06, 0x46, 0x66, 0x7f, 0x00}, // U+004C L
+		{0x63, 0x77, 0x7f, 0x7f, 0x6b, 0x63, 0x63, 0x00}, // U+004D M
+		{0x63, 0x67, 0x6f, 0x7b, 0x73, 0x63, 0x63, 0x00}, // U+004E N
+		{0x1c, 0x36, 0x63, 0x63, 0x63, 0x36, 0x1c, 0x00}, // U+004F O
+		{0x3f, 0x66, 0x66, 0x3e, 0x06, 0x06, 0x0f, 0x00}, // U+0050 P
+		{0x1e, 0x33, 0x33, 0x33, 0x3b, 0x1e, 0x38, 0x00}, // U+0051 Q
+		{0x3f, 0x66, 0x66, 0x3e, 0x36, 0x66, 0x67, 0x00}, // U+0052 R
+		{0x1e, 0x33, 0x07, 0x0e, 0x38, 0x33, 0x1e, 0x00}, // U+0053 S
+		{0x3f, 0x2d, 0x0c, 0x0c, 0x0c, 0x0c, 0x1e, 0x00}, // U+0054 T
+		{0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x3f, 0x00}, // U+0055 U
+		{0x33, 0x33, 0x33, 0x33, 0x33, 0x1e, 0x0c, 0x00}, // U+0056 V
+		{0x63, 0x63, 0x63, 0x6b, 0x7f, 0x77, 0x63, 0x00}, // U+0057 W
+		{0x63, 0x63, 0x36, 0x1c, 0x1c, 0x36, 0x63, 0x00}, // U+0058 X
+		{0x33, 0x33, 0x33, 0x1e, 0x0c, 0x0c, 0x1e, 0x00}, // U+0059 Y
+		{0x7f, 0x63, 0x31, 0x18, 0x4c, 0x66, 0x7f, 0x00}, // U+005A Z
+		{0x1e, 0x06, 0x06, 0x06, 0x06, 0x06, 0x1e, 0x00}, // U+005B [
+		{0x03, 0x06, 0x0c, 0x18, 0x30, 0x60, 0x40, 0x00}, // U+005C \
+		{0x1e, 0x18, 0x18, 0x18, 0x18, 0x18, 0x1e, 0x00}, // U+005D ]
+		{0x08, 0x1c, 0x36, 0x63, 0x00, 0x00, 0x00, 0x00}, // U+005E ^
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff}, // U+005F _
+		{0x0c, 0x0c, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+0060 `
+		{0x00, 0x00, 0x1e, 0x30, 0x3e, 0x33, 0x6e, 0x00}, // U+0061 a
+		{0x07, 0x06, 0x06, 0x3e, 0x66, 0x66, 0x3b, 0x00}, // U+0062 b
+		{0x00, 0x00, 0x1e, 0x33, 0x03, 0x33, 0x1e, 0x00}, // U+0063 c
+		{0x38, 0x30, 0x30, 0x3e, 0x33, 0x33, 0x6e, 0x00}, // U+0064 d
+		{0x00, 0x00, 0x1e, 0x33, 0x3f, 0x03, 0x1e, 0x00}, // U+0065 e
+		{0x1c, 0x36, 0x06, 0x0f, 0x06, 0x06, 0x0f, 0x00}, // U+0066 f
+		{0x00, 0x00, 0x6e, 0x33, 0x33, 0x3e, 0x30, 0x1f}, // U+0067 g
+		{0x07, 0x06, 0x36, 0x6e, 0x66, 0x66, 0x67, 0x00}, // U+0068 h
+		{0x0c, 0x00, 0x0e, 0x0c, 0x0c, 0x0c, 0x1e, 0x00}, // U+0069 i
+		{0x30, 0x00, 0x30, 0x30, 0x30, 0x33, 0x33, 0x1e}, // U+006A j
+		{0x07, 0x06, 0x66, 0x36, 0x1e, 0x36, 0x67, 0x00}, // U+006B k
+		{0x0e, 0x0c, 0x0c, 0x0c, 0x0c, 0x0c, 0x1e, 0x00}, // U+006C l
+		{0x00, 0x00, 0x33, 0x7f, 0x7f, 0x6b, 0x63, 0x00}, // U+006D m
+		{0x00, 0x00, 0x1f, 0x33, 0x33, 0x33, 0x33, 0x00}, // U+006E n
+		{0x00, 0x00, 0x1e, 0x33, 0x33, 0x33, 0x1e, 0x00}, // U+006F o
+		{0x00, 0x00, 0x3b, 0x66, 0x66, 0x3e, 0x06, 0x0f}, // U+0070 p
+		{0x00, 0x00, 0x6e, 0x33, 0x33, 0x3e, 0x30, 0x78}, // U+0071 q
+		{0x00, 0x00, 0x3b, 0x6e, 0x66, 0x06, 0x0f, 0x00}, // U+0072 r
+		{0x00, 0x00, 0x3e, 0x03, 0x1e, 0x30, 0x1f, 0x00}, // U+0073 s
+		{0x08, 0x0c, 0x3e, 0x0c, 0x0c, 0x2c, 0x18, 0x00}, // U+0074 t
+		{0x00, 0x00, 0x33, 0x33, 0x33, 0x33, 0x6e, 0x00}, // U+0075 u
+		{0x00, 0x00, 0x33, 0x33, 0x33, 0x1e, 0x0c, 0x00}, // U+0076 v
+		{0x00, 0x00, 0x63, 0x6b, 0x7f, 0x7f, 0x36, 0x00}, // U+0077 w
+		{0x00, 0x00, 0x63, 0x36, 0x1c, 0x36, 0x63, 0x00}, // U+0078 x
+		{0x00, 0x00, 0x33, 0x33, 0x33, 0x3e, 0x30, 0x1f}, // U+0079 y
+		{0x00, 0x00, 0x3f, 0x19, 0x0c, 0x26, 0x3f, 0x00}, // U+007A z
+		{0x38, 0x0c, 0x0c, 0x07, 0x0c, 0x0c, 0x38, 0x00}, // U+007B {
+		{0x18, 0x18, 0x18, 0x00, 0x18, 0x18, 0x18, 0x00}, // U+007C |
+		{0x07, 0x0c, 0x0c, 0x38, 0x0c, 0x0c, 0x07, 0x00}, // U+007D }
+		{0x6e, 0x3b, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // U+007E ~
+	}
+)
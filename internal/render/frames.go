@@ -0,0 +1,129 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package render
+
+import (
+	"fmt"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/vt"
+	"github.com/shopspring/decimal"
+)
+
+type Frame struct {
+	Time     float64
+	Duration float64
+	Screen   *vt.Screen
+}
+
+// CanvasSize returns the largest terminal size in cells over the header and every
+// resize event.
+func CanvasSize(h *asciinema.V2Header, events []asciinema.V2Event) (int, int) {
+	width, height := h.Width, h.Height
+	for _, e := range events {
+		data, ok := e.Data.(string)
+		if !ok || e.Code != "r" {
+			continue
+		}
+
+		var w, h int
+		if _, err := fmt.Sscanf(data, "%dx%d", &w, &h); err != nil {
+			continue
+		}
+
+		width, height = max(width, w), max(height, h)
+	}
+
+	return width, height
+}
+
+// Frames plays events on a terminal of the header size and calls fn once for
+// every distinct screen state, with how long the state stays on screen; the
+// final state lasts until the header duration or the last event, whichever is
+// later, and is then held for hold seconds.
+func Frames(h *asciinema.V2Header, events []asciinema.V2Event, hold float64, fn func(f *Frame) error) error {
+	term := vt.New(h.Width, h.Height)
+
+	cur := &Frame{Time: 0, Screen: term.Snapshot()}
+	flush := func(next *Frame) error {
+		if next.Screen.Equal(cur.Screen) {
+			return nil
+		}
+
+		cur.Duration = decimal.NewFromFloat(next.Time).Sub(decimal.NewFromFloat(cur.Time)).InexactFloat64()
+		if cur.Duration > 0 {
+			if err := fn(cur); err != nil {
+				return err
+			}
+		}
+
+		cur = next
+
+		return nil
+	}
+
+	// NOTE: events at the same time make a single state
+	dirty, last := false, 0.0
+	end := decimal.NewFromFloat(h.Duration)
+	for _, e := range events {
+		end = decimal.Max(end, e.Time.Decimal())
+
+		if dirty && e.Time.Float64() != last {
+			if err := flush(&Frame{Time: last, Screen: term.Snapshot()}); err != nil {
+				return err
+			}
+
+			dirty = false
+		}
+
+		data, ok := e.Data.(string)
+		if !ok {
+			continue
+		}
+
+		switch e.Code {
+		case "o":
+			term.WriteString(data)
+		case "r":
+			var w, h int
+			if _, err := fmt.Sscanf(data, "%dx%d", &w, &h); err != nil {
+				return fmt.Errorf("invalid resize event: %v", data)
+			}
+
+			term.Resize(w, h)
+		default:
+			continue
+		}
+
+		dirty, last = true, e.Time.Float64()
+	}
+
+	if dirty {
+		if err := flush(&Frame{Time: last, Screen: term.Snapshot()}); err != nil {
+			return err
+		}
+	}
+
+	// NOTE: the final state lasts until the recording ends, then is held
+	cur.Duration = end.Sub(decimal.NewFromFloat(cur.Time)).Add(decimal.NewFromFloat(hold)).InexactFloat64()
+
+	return fn(cur)
+}
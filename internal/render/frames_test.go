@@ -0,0 +1,253 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package render
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrames(t *testing.T) {
+	type args struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		hold   float64
+	}
+
+	type frame struct {
+		time     float64
+		duration float64
+		text     string
+	}
+
+	type expected struct {
+		frames []frame
+		err    error
+	}
+
+	header := &asciinema.V2Header{Version: 2, Width: 4, Height: 1}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: distinct states",
+			args: &args{
+				header: header,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "o", Data: "b"},
+				},
+				hold: 1,
+			},
+			expected: &expected{
+				frames: []frame{
+					{time: 0, duration: 0.1, text: ""},
+					{time: 0.1, duration: 0.2, text: "a"},
+					{time: 0.3, duration: 1, text: "ab"},
+				},
+			},
+		},
+		{
+			name: "happy path: unchanged states merge",
+			args: &args{
+				header: header,
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "\x1b[?25la"},
+					{Time: "0.5", Code: "o", Data: "\x1b[?25l"},
+					{Time: "0.7", Code: "i", Data: "b"},
+					{Time: "0.9", Code: "o", Data: "b"},
+				},
+				hold: 0.5,
+			},
+			expected: &expected{
+				frames: []frame{
+					{time: 0, duration: 0.9, text: "a"},
+					{time: 0.9, duration: 0.5, text: "ab"},
+				},
+			},
+		},
+		{
+			name: "happy path: same time events make one state",
+			args: &args{
+				header: header,
+				events: []asciinema.V2Event{
+					{Time: "0.2", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "o", Data: "b"},
+					{Time: "0.2", Code: "m", Data: ""},
+				},
+				hold: 1,
+			},
+			expected: &expected{
+				frames: []frame{
+					{time: 0, duration: 0.2, text: ""},
+					{time: 0.2, duration: 1, text: "ab"},
+				},
+			},
+		},
+		{
+			name: "happy path: final state lasts until the end",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 4, Height: 1, Duration: 2},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "1.5", Code: "m", Data: "end"},
+				},
+				hold: 1,
+			},
+			expected: &expected{
+				frames: []frame{
+					{time: 0, duration: 0.1, text: ""},
+					{time: 0.1, duration: 2.9, text: "a"},
+				},
+			},
+		},
+		{
+			name: "happy path: final state lasts until the last event",
+			args: &args{
+				header: header,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "1.5", Code: "i", Data: "b"},
+				},
+				hold: 0.5,
+			},
+			expected: &expected{
+				frames: []frame{
+					{time: 0, duration: 0.1, text: ""},
+					{time: 0.1, duration: 1.9, text: "a"},
+				},
+			},
+		},
+		{
+			name: "happy path: resize",
+			args: &args{
+				header: header,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "abcd"},
+					{Time: "0.2", Code: "r", Data: "2x1"},
+				},
+				hold: 1,
+			},
+			expected: &expected{
+				frames: []frame{
+					{time: 0, duration: 0.1, text: ""},
+					{time: 0.1, duration: 0.1, text: "abcd"},
+					{time: 0.2, duration: 1, text: "ab"},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid resize",
+			args: &args{
+				header: header,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "r", Data: "wide"},
+				},
+				hold: 1,
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid resize event: %v", "wide"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := make([]frame, 0)
+			err := Frames(tt.args.header, tt.args.events, tt.args.hold, func(f *Frame) error {
+				var b strings.Builder
+				for x := 0; x < f.Screen.Width; x++ {
+					b.WriteRune(f.Screen.Cell(x, 0).Rune)
+				}
+
+				actual = append(actual, frame{time: f.Time, duration: f.Duration, text: strings.TrimRight(b.String(), " ")})
+
+				return nil
+			})
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.frames, actual)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestCanvasSize(t *testing.T) {
+	type args struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		width  int
+		height int
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: no resize",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{{Time: "0.1", Code: "o", Data: "a"}},
+			},
+			expected: &expected{width: 80, height: 24},
+		},
+		{
+			name: "happy path: resizes",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "r", Data: "100x20"},
+					{Time: "0.2", Code: "r", Data: "40x30"},
+					{Time: "0.3", Code: "r", Data: "wide"},
+				},
+			},
+			expected: &expected{width: 100, height: 30},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			width, height := CanvasSize(tt.args.header, tt.args.events)
+
+			// Assert
+			assert.Equal(t, tt.expected.width, width)
+			assert.Equal(t, tt.expected.height, height)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package render
+
+import (
+	"image/color"
+)
+
+type lineSegments struct {
+	up, down, left, right bool
+	heavy                 bool
+}
+
+var (
+	boxDrawing = map[rune]lineSegments{
+		'─': {left: true, right: true},
+		'━': {left: true, right: true, heavy: true},
+		'═': {left: true, right: true, heavy: true},
+		'│': {up: true, down: true},
+		'┃': {up: true, down: true, heavy: true},
+		'║': {up: true, down: true, heavy: true},
+		'┌': {down: true, right: true},
+		'╭': {down: true, right: true},
+		'┏': {down: true, right: true, heavy: true},
+		'╔': {down: true, right: true, heavy: true},
+		'┐': {down: true, left: true},
+		'╮': {down: true, left: true},
+		'┓': {down: true, left: true, heavy: true},
+		'╗': {down: true, left: true, heavy: true},
+		'└': {up: true, right: true},
+		'╰': {up: true, right: true},
+		'┗': {up: true, right: true, heavy: true},
+		'╚': {up: true, right: true, heavy: true},
+		'┘': {up: true, left: true},
+		'╯': {up: true, left: true},
+		'┛': {up: true, left: true, heavy: true},
+		'╝': {up: true, left: true, heavy: true},
+		'├': {up: true, down: true, right: true},
+		'┣': {up: true, down: true, right: true, heavy: true},
+		'╠': {up: true, down: true, right: true, heavy: true},
+		'┤': {up: true, down: true, left: true},
+		'┫': {up: true, down: true, left: true, heavy: true},
+		'╣': {up: true, down: true, left: true, heavy: true},
+		'┬': {down: true, left: true, right: true},
+		'┳': {down: true, left: true, right: true, heavy: true},
+		'╦': {down: true, left: true, right: true, heavy: true},
+		'┴': {up: true, left: true, right: true},
+		'┻': {up: true, left: true, right: true, heavy: true},
+		'╩': {up: true, left: true, right: true, heavy: true},
+		'┼': {up: true, down: true, left: true, right: true},
+		'╋': {up: true, down: true, left: true, right: true, heavy: true},
+		'╬': {up: true, down: true, left: true, right: true, heavy: true},
+		'╴': {left: true},
+		'╵': {up: true},
+		'╶': {right: true},
+		'╷': {down: true},
+	}
+)
+
+// box draws the line segments of a box drawing rune.
+func (c *cellCanvas) box(r rune) bool {
+	seg, ok := boxDrawing[r]
+	if !ok {
+		return false
+	}
+
+	cx, cy, t := cellWidth/2-1, cellHeight/2-1, 1
+	if seg.heavy {
+		t = 2
+	}
+
+	if seg.up {
+		c.rect(cx, 0, cx+t, cy+t, c.fg)
+	}
+
+	if seg.down {
+		c.rect(cx, cy, cx+t, cellHeight, c.fg)
+	}
+
+	if seg.left {
+		c.rect(0, cy, cx+t, cy+t, c.fg)
+	}
+
+	if seg.right {
+		c.rect(cx, cy, cellWidth, cy+t, c.fg)
+	}
+
+	return true
+}
+
+// block draws the block elements from U+2580 to U+2593.
+func (c *cellCanvas) block(r rune, bg color.RGBA) bool {
+	switch {
+	case r == '▀':
+		c.rect(0, 0, cellWidth, cellHeight/2, c.fg)
+	case r >= '▁' && r <= '█':
+		// NOTE: lower one eighth to full block
+		h := cellHeight * int(r-'▁'+1) / 8
+		c.rect(0, cellHeight-h, cellWidth, cellHeight, c.fg)
+	case r >= '▉' && r <= '▏':
+		// NOTE: left seven eighths to left one eighth
+		w := cellWidth * int('▏'-r+1) / 8
+		c.rect(0, 0, w, cellHeight, c.fg)
+	case r == '▐':
+		c.rect(cellWidth/2, 0, cellWidth, cellHeight, c.fg)
+	case r >= '░' && r <= '▓':
+		c.rect(0, 0, cellWidth, cellHeight, blend(c.fg, bg, float64(r-'░'+1)/4))
+	default:
+		return false
+	}
+
+	return true
+}
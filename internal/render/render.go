@@ -0,0 +1,246 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/vt"
+)
+
+const (
+	cellWidth  = 8
+	cellHeight = 12
+	glyphTop   = 2
+)
+
+type Renderer struct {
+	fg      color.RGBA
+	bg      color.RGBA
+	palette [256]color.RGBA
+	scale   int
+}
+
+func New(theme *asciinema.V2HeaderTheme, scale int) (*Renderer, error) {
+	if err := theme.Validate(); err != nil {
+		return nil, err
+	}
+
+	if scale < 1 {
+		return nil, fmt.Errorf("invalid scale: %v", scale)
+	}
+
+	r := &Renderer{
+		fg:    parseColor(theme.FG),
+		bg:    parseColor(theme.BG),
+		scale: scale,
+	}
+
+	colors := strings.Split(theme.Palette, ":")
+	for i := 0; i < 16; i++ {
+		// NOTE: an 8 color palette reuses the normal colors as the bright ones
+		r.palette[i] = parseColor(colors[i%len(colors)])
+	}
+
+	levels := [6]uint8{0, 95, 135, 175, 215, 255}
+	for i := 0; i < 216; i++ {
+		r.palette[16+i] = color.RGBA{R: levels[i/36], G: levels[i/6%6], B: levels[i%6], A: 0xff}
+	}
+
+	for i := 0; i < 24; i++ {
+		v := uint8(8 + 10*i)
+		r.palette[232+i] = color.RGBA{R: v, G: v, B: v, A: 0xff}
+	}
+
+	return r, nil
+}
+
+func parseColor(s string) color.RGBA {
+	v, _ := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+// Size returns the image size in pixels for a screen of width by height cells.
+func (r *Renderer) Size(width, height int) (int, int) {
+	return width * cellWidth * r.scale, height * cellHeight * r.scale
+}
+
+func (r *Renderer) Render(s *vt.Screen) *image.RGBA {
+	return r.RenderCanvas(s, s.Width, s.Height)
+}
+
+// RenderCanvas draws s at the top left of a canvas of at least width by height
+// cells, so that a resized terminal keeps one image size.
+func (r *Renderer) RenderCanvas(s *vt.Screen, width, height int) *image.RGBA {
+	w, h := r.Size(max(width, s.Width), max(height, s.Height))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	r.fill(img, img.Rect, r.bg)
+
+	for y := 0; y < s.Height; y++ {
+		for x := 0; x < s.Width; x++ {
+			c := s.Cell(x, y)
+			if c.Width == 0 {
+				continue
+			}
+
+			cursor := s.CursorVisible && s.CursorX == x && s.CursorY == y
+			r.drawCell(img, x, y, c, cursor)
+		}
+	}
+
+	return img
+}
+
+func (r *Renderer) colors(a vt.Attr, cursor bool) (color.RGBA, color.RGBA) {
+	fg := r.color(a.FG, r.fg)
+	if a.FG.Kind == vt.ColorIndexed && a.FG.Index < 8 && a.Flags&vt.FlagBold != 0 {
+		fg = r.palette[a.FG.Index+8]
+	}
+
+	bg := r.color(a.BG, r.bg)
+	if a.Flags&vt.FlagFaint != 0 {
+		fg = blend(fg, bg, 0.5)
+	}
+
+	if a.Flags&vt.FlagInverse != 0 {
+		fg, bg = bg, fg
+	}
+
+	if a.Flags&vt.FlagInvisible != 0 {
+		fg = bg
+	}
+
+	if cursor {
+		fg, bg = bg, fg
+	}
+
+	return fg, bg
+}
+
+func (r *Renderer) color(c vt.Color, def color.RGBA) color.RGBA {
+	switch c.Kind {
+	case vt.ColorIndexed:
+		return r.palette[c.Index]
+	case vt.ColorRGB:
+		return color.RGBA{R: c.R, G: c.G, B: c.B, A: 0xff}
+	default:
+		return def
+	}
+}
+
+func blend(a, b color.RGBA, ratio float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x)*ratio + float64(y)*(1-ratio))
+	}
+
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: 0xff}
+}
+
+func (r *Renderer) drawCell(img *image.RGBA, x, y int, c vt.Cell, cursor bool) {
+	fg, bg := r.colors(c.Attr, cursor)
+	cell := cellCanvas{
+		r:     r,
+		img:   img,
+		x:     x * cellWidth,
+		y:     y * cellHeight,
+		width: int(c.Width) * cellWidth,
+		fg:    fg,
+	}
+
+	cell.rect(0, 0, cell.width, cellHeight, bg)
+
+	bold := c.Attr.Flags&vt.FlagBold != 0
+	switch {
+	case c.Rune >= 0x20 && c.Rune <= 0x7e:
+		cell.glyph(asciiGlyphs[c.Rune-0x20], bold)
+	case c.Rune >= 0xa0 && c.Width == 1 && !cell.box(c.Rune) && !cell.block(c.Rune, bg):
+		cell.tofu()
+	case c.Width == 2:
+		cell.tofu()
+	}
+
+	if c.Attr.Flags&vt.FlagUnderline != 0 {
+		cell.rect(0, cellHeight-2, cell.width, cellHeight-1, fg)
+	}
+
+	if c.Attr.Flags&vt.FlagStrike != 0 {
+		cell.rect(0, cellHeight/2, cell.width, cellHeight/2+1, fg)
+	}
+}
+
+func (r *Renderer) fill(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	rect = rect.Intersect(img.Rect)
+	if rect.Empty() {
+		return
+	}
+
+	row := img.Pix[img.PixOffset(rect.Min.X, rect.Min.Y):img.PixOffset(rect.Min.X, rect.Min.Y)]
+	for x := rect.Min.X; x < rect.Max.X; x++ {
+		row = append(row, c.R, c.G, c.B, c.A)
+	}
+
+	for y := rect.Min.Y + 1; y < rect.Max.Y; y++ {
+		i := img.PixOffset(rect.Min.X, y)
+		copy(img.Pix[i:i+len(row)], row)
+	}
+}
+
+// cellCanvas draws in unscaled pixel coordinates relative to a cell.
+type cellCanvas struct {
+	r     *Renderer
+	img   *image.RGBA
+	x, y  int
+	width int
+	fg    color.RGBA
+}
+
+func (c *cellCanvas) rect(x0, y0, x1, y1 int, col color.RGBA) {
+	s := c.r.scale
+	c.r.fill(c.img, image.Rect((c.x+x0)*s, (c.y+y0)*s, (c.x+x1)*s, (c.y+y1)*s), col)
+}
+
+func (c *cellCanvas) glyph(g [8]byte, bold bool) {
+	for gy, bits := range g {
+		if bold {
+			bits |= bits << 1
+		}
+
+		for gx := 0; gx < cellWidth; gx++ {
+			if bits&(1<<gx) != 0 {
+				c.rect(gx, glyphTop+gy, gx+1, glyphTop+gy+1, c.fg)
+			}
+		}
+	}
+}
+
+// tofu draws a hollow box for a rune the font does not cover.
+func (c *cellCanvas) tofu() {
+	c.rect(1, glyphTop, c.width-1, glyphTop+1, c.fg)
+	c.rect(1, glyphTop+7, c.width-1, glyphTop+8, c.fg)
+	c.rect(1, glyphTop, 2, glyphTop+8, c.fg)
+	c.rect(c.width-2, glyphTop, c.width-1, glyphTop+8, c.fg)
+}
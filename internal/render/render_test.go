@@ -0,0 +1,247 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/vt"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testTheme = &asciinema.V2HeaderTheme{
+		FG:      "#ffffff",
+		BG:      "#000000",
+		Palette: "#000000:#ff0000:#00ff00:#ffff00:#0000ff:#ff00ff:#00ffff:#ffffff",
+	}
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		theme *asciinema.V2HeaderTheme
+		scale int
+	}
+
+	type expected struct {
+		width  int
+		height int
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: scale 1",
+			args:     &args{theme: testTheme, scale: 1},
+			expected: &expected{width: 80 * 8, height: 24 * 12},
+		},
+		{
+			name:     "happy path: scale 2",
+			args:     &args{theme: testTheme, scale: 2},
+			expected: &expected{width: 80 * 16, height: 24 * 24},
+		},
+		{
+			name: "edge path: invalid theme",
+			args: &args{theme: &asciinema.V2HeaderTheme{FG: "white", BG: "#000000", Palette: testTheme.Palette}, scale: 1},
+			expected: &expected{
+				err: fmt.Errorf("invalid theme fg: %v", "white"),
+			},
+		},
+		{
+			name:     "edge path: invalid scale",
+			args:     &args{theme: testTheme, scale: 0},
+			expected: &expected{err: fmt.Errorf("invalid scale: %v", 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			r, err := New(tt.args.theme, tt.args.scale)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+				w, h := r.Size(80, 24)
+				assert.Equal(t, tt.expected.width, w)
+				assert.Equal(t, tt.expected.height, h)
+			} else {
+				assert.Nil(t, r)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestRenderer_Render(t *testing.T) {
+	type args struct {
+		data string
+		x    int
+		y    int
+	}
+
+	type expected struct {
+		color color.RGBA
+	}
+
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	black := color.RGBA{A: 0xff}
+	red := color.RGBA{R: 0xff, A: 0xff}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: background",
+			args:     &args{data: "\x1b[?25l", x: 0, y: 0},
+			expected: &expected{color: black},
+		},
+		{
+			name:     "happy path: glyph pixel",
+			args:     &args{data: "\x1b[?25lA", x: 2, y: glyphTop},
+			expected: &expected{color: white},
+		},
+		{
+			name:     "happy path: glyph gap",
+			args:     &args{data: "\x1b[?25lA", x: 0, y: glyphTop},
+			expected: &expected{color: black},
+		},
+		{
+			name:     "happy path: indexed background",
+			args:     &args{data: "\x1b[?25l\x1b[41m ", x: 0, y: 0},
+			expected: &expected{color: red},
+		},
+		{
+			name:     "happy path: bold bright color",
+			args:     &args{data: "\x1b[?25l\x1b[1;31mA", x: 2, y: glyphTop},
+			expected: &expected{color: red},
+		},
+		{
+			name:     "happy path: true color",
+			args:     &args{data: "\x1b[?25l\x1b[48;2;1;2;3m ", x: 0, y: 0},
+			expected: &expected{color: color.RGBA{R: 1, G: 2, B: 3, A: 0xff}},
+		},
+		{
+			name:     "happy path: inverse",
+			args:     &args{data: "\x1b[?25l\x1b[7m ", x: 0, y: 0},
+			expected: &expected{color: white},
+		},
+		{
+			name:     "happy path: cursor",
+			args:     &args{data: "", x: 0, y: 0},
+			expected: &expected{color: white},
+		},
+		{
+			name:     "happy path: underline",
+			args:     &args{data: "\x1b[?25l\x1b[4m ", x: 0, y: cellHeight - 2},
+			expected: &expected{color: white},
+		},
+		{
+			name:     "happy path: box drawing",
+			args:     &args{data: "\x1b[?25l─", x: 0, y: cellHeight/2 - 1},
+			expected: &expected{color: white},
+		},
+		{
+			name:     "happy path: block element",
+			args:     &args{data: "\x1b[?25l▄", x: 0, y: cellHeight - 1},
+			expected: &expected{color: white},
+		},
+		{
+			name:     "happy path: tofu",
+			args:     &args{data: "\x1b[?25lあ", x: 1, y: glyphTop},
+			expected: &expected{color: white},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			r, err := New(testTheme, 1)
+			assert.NoError(t, err)
+
+			term := vt.New(4, 2)
+			term.WriteString(tt.args.data)
+
+			// Act
+			img := r.Render(term.Snapshot())
+
+			// Assert
+			assert.Equal(t, tt.expected.color, img.RGBAAt(tt.args.x, tt.args.y))
+		})
+	}
+}
+
+func TestRenderer_RenderCanvas(t *testing.T) {
+	type args struct {
+		width  int
+		height int
+	}
+
+	type expected struct {
+		width  int
+		height int
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: larger canvas",
+			args:     &args{width: 6, height: 3},
+			expected: &expected{width: 6 * cellWidth, height: 3 * cellHeight},
+		},
+		{
+			name:     "happy path: smaller canvas",
+			args:     &args{width: 2, height: 1},
+			expected: &expected{width: 4 * cellWidth, height: 2 * cellHeight},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			r, err := New(testTheme, 1)
+			assert.NoError(t, err)
+
+			term := vt.New(4, 2)
+
+			// Act
+			img := r.RenderCanvas(term.Snapshot(), tt.args.width, tt.args.height)
+
+			// Assert
+			assert.Equal(t, tt.expected.width, img.Rect.Dx())
+			assert.Equal(t, tt.expected.height, img.Rect.Dy())
+			assert.Equal(t, color.RGBA{A: 0xff}, img.RGBAAt(img.Rect.Dx()-1, img.Rect.Dy()-1))
+		})
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type builtin struct {
+	args []string
+	fn   func(p *Program, args values) (any, error)
+}
+
+var (
+	builtins = map[string]builtin{
+		"len": {[]string{"string"}, func(_ *Program, args values) (any, error) {
+			return float64(utf8.RuneCountInString(args.str(0))), nil
+		}},
+		"contains":  {[]string{"string", "string"}, stringFn2(strings.Contains)},
+		"hasPrefix": {[]string{"string", "string"}, stringFn2(strings.HasPrefix)},
+		"hasSuffix": {[]string{"string", "string"}, stringFn2(strings.HasSuffix)},
+		"upper":     {[]string{"string"}, stringFn1(strings.ToUpper)},
+		"lower":     {[]string{"string"}, stringFn1(strings.ToLower)},
+		"trim":      {[]string{"string"}, stringFn1(strings.TrimSpace)},
+		"replace": {[]string{"string", "string", "string"}, func(_ *Program, args values) (any, error) {
+			return strings.ReplaceAll(args.str(0), args.str(1), args.str(2)), nil
+		}},
+		"match": {[]string{"string", "string"}, func(p *Program, args values) (any, error) {
+			re, err := p.regexp(args.str(1))
+			if err != nil {
+				return nil, err
+			}
+			return re.MatchString(args.str(0)), nil
+		}},
+		"replaceRegexp": {[]string{"string", "string", "string"}, func(p *Program, args values) (any, error) {
+			re, err := p.regexp(args.str(1))
+			if err != nil {
+				return nil, err
+			}
+			return re.ReplaceAllString(args.str(0), args.str(2)), nil
+		}},
+		"min": {[]string{"number", "number"}, numberFn2(math.Min)},
+		"max": {[]string{"number", "number"}, numberFn2(math.Max)},
+		"abs": {[]string{"number"}, numberFn1(math.Abs)},
+		"round": {[]string{"number", "number"}, numberFn2(func(x, step float64) float64 {
+			if step <= 0 {
+				return x
+			}
+			return math.Round(x/step) * step
+		})},
+		"string": {[]string{"any"}, func(_ *Program, args values) (any, error) {
+			return format(args[0]), nil
+		}},
+	}
+)
+
+func stringFn1(fn func(s string) string) func(p *Program, args values) (any, error) {
+	return func(_ *Program, args values) (any, error) {
+		return fn(args.str(0)), nil
+	}
+}
+
+func stringFn2(fn func(s, t string) bool) func(p *Program, args values) (any, error) {
+	return func(_ *Program, args values) (any, error) {
+		return fn(args.str(0), args.str(1)), nil
+	}
+}
+
+func numberFn1(fn func(x float64) float64) func(p *Program, args values) (any, error) {
+	return func(_ *Program, args values) (any, error) {
+		return fn(args.num(0)), nil
+	}
+}
+
+func numberFn2(fn func(x, y float64) float64) func(p *Program, args values) (any, error) {
+	return func(_ *Program, args values) (any, error) {
+		return fn(args.num(0), args.num(1)), nil
+	}
+}
+
+// values are builtin arguments, whose types are checked by call.
+type values []any
+
+func (v values) str(i int) string {
+	s, _ := v[i].(string)
+	return s
+}
+
+func (v values) num(i int) float64 {
+	x, _ := v[i].(float64)
+	return x
+}
+
+var (
+	errDrop = errors.New("drop")
+)
+
+// Run runs the program over vars, which holds numbers, strings and bools and is updated by assignments.
+// It reports whether the program dropped the event.
+func (p *Program) Run(vars map[string]any) (dropped bool, err error) {
+	r := &runner{p: p, vars: vars}
+	if err := r.stmts(p.stmts); err != nil {
+		if errors.Is(err, errDrop) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return false, nil
+}
+
+func (p *Program) regexp(pattern string) (*regexp.Regexp, error) {
+	// NOTE: programs may run concurrently over several files
+	if re, ok := p.regexps.Load(pattern); ok {
+		if re, ok := re.(*regexp.Regexp); ok {
+			return re, nil
+		}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", pattern)
+	}
+	p.regexps.Store(pattern, re)
+
+	return re, nil
+}
+
+type runner struct {
+	p    *Program
+	vars map[string]any
+}
+
+func (r *runner) stmts(stmts []stmt) error {
+	for _, s := range stmts {
+		if err := r.stmt(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *runner) stmt(s stmt) error {
+	switch s := s.(type) {
+	case *assignStmt:
+		v, err := r.expr(s.expr)
+		if err != nil {
+			return err
+		}
+
+		if s.op != "=" {
+			old, ok := r.vars[s.name]
+			if !ok {
+				return fmt.Errorf("%v: undefined: %v", s.pos, s.name)
+			}
+			if v, err = operate(s.pos, strings.TrimSuffix(s.op, "="), old, v); err != nil {
+				return err
+			}
+		}
+
+		r.vars[s.name] = v
+	case *ifStmt:
+		v, err := r.expr(s.cond)
+		if err != nil {
+			return err
+		}
+
+		cond, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%v: non-bool condition: %v", s.cond.position(), typeName(v))
+		}
+
+		if cond {
+			return r.stmts(s.then)
+		}
+		return r.stmts(s.els)
+	case *dropStmt:
+		return errDrop
+	}
+
+	return nil
+}
+
+func (r *runner) expr(x expr) (any, error) {
+	switch x := x.(type) {
+	case *literal:
+		return x.value, nil
+	case *ident:
+		v, ok := r.vars[x.name]
+		if !ok {
+			return nil, fmt.Errorf("%v: undefined: %v", x.pos, x.name)
+		}
+		return v, nil
+	case *unary:
+		v, err := r.expr(x.x)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := v.(type) {
+		case bool:
+			if x.op == "!" {
+				return !v, nil
+			}
+		case float64:
+			if x.op == "-" {
+				return -v, nil
+			}
+		}
+
+		return nil, fmt.Errorf("%v: invalid operation: %v%v", x.pos, x.op, typeName(v))
+	case *binary:
+		a, err := r.expr(x.x)
+		if err != nil {
+			return nil, err
+		}
+
+		// NOTE: && and || short-circuit
+		if x.op == "&&" || x.op == "||" {
+			va, ok := a.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%v: invalid operation: %v %v", x.pos, typeName(a), x.op)
+			}
+			if va == (x.op == "||") {
+				return va, nil
+			}
+
+			b, err := r.expr(x.y)
+			if err != nil {
+				return nil, err
+			}
+			vb, ok := b.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%v: invalid operation: %v %v", x.pos, x.op, typeName(b))
+			}
+			return vb, nil
+		}
+
+		b, err := r.expr(x.y)
+		if err != nil {
+			return nil, err
+		}
+
+		return operate(x.pos, x.op, a, b)
+	case *call:
+		fn, ok := builtins[x.name]
+		if !ok {
+			return nil, fmt.Errorf("%v: undefined function: %v", x.pos, x.name)
+		}
+		if len(x.args) != len(fn.args) {
+			return nil, fmt.Errorf("%v: %v takes %d arguments, got %d", x.pos, x.name, len(fn.args), len(x.args))
+		}
+
+		args := make(values, 0, len(x.args))
+		for i, arg := range x.args {
+			v, err := r.expr(arg)
+			if err != nil {
+				return nil, err
+			}
+			if fn.args[i] != "any" && typeName(v) != fn.args[i] {
+				return nil, fmt.Errorf("%v: %v argument %d must be %v, got %v", x.pos, x.name, i+1, fn.args[i], typeName(v))
+			}
+			args = append(args, v)
+		}
+
+		v, err := fn.fn(r.p, args)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", x.pos, err)
+		}
+		return v, nil
+	}
+
+	return nil, nil
+}
+
+func operate(pos Pos, op string, a, b any) (any, error) {
+	switch op {
+	case "==":
+		return a == b, nil
+	case "!=":
+		return a != b, nil
+	}
+
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok {
+			switch op {
+			case "+":
+				return a + b, nil
+			case "-":
+				return a - b, nil
+			case "*":
+				return a * b, nil
+			case "/", "%":
+				if b == 0 {
+					return nil, fmt.Errorf("%v: division by zero", pos)
+				}
+				if op == "/" {
+					return a / b, nil
+				}
+				return math.Mod(a, b), nil
+			case "<":
+				return a < b, nil
+			case "<=":
+				return a <= b, nil
+			case ">":
+				return a > b, nil
+			case ">=":
+				return a >= b, nil
+			}
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			switch op {
+			case "+":
+				return a + b, nil
+			case "<":
+				return a < b, nil
+			case "<=":
+				return a <= b, nil
+			case ">":
+				return a > b, nil
+			case ">=":
+				return a >= b, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%v: invalid operation: %v %v %v", pos, typeName(a), op, typeName(b))
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func format(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	type args struct {
+		src  string
+		vars map[string]any
+	}
+
+	type expected struct {
+		vars    map[string]any
+		dropped bool
+		err     error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: condition true",
+			args: &args{
+				src:  `if code=="o" && delta>3 { delta = 1 }`,
+				vars: map[string]any{"code": "o", "delta": 4.5},
+			},
+			expected: &expected{
+				vars: map[string]any{"code": "o", "delta": float64(1)},
+			},
+		},
+		{
+			name: "happy path: condition false",
+			args: &args{
+				src:  `if code=="o" && delta>3 { delta = 1 }`,
+				vars: map[string]any{"code": "i", "delta": 4.5},
+			},
+			expected: &expected{
+				vars: map[string]any{"code": "i", "delta": 4.5},
+			},
+		},
+		{
+			name: "happy path: else if",
+			args: &args{
+				src:  "if delta > 1 { delta = 1 } else if delta < 100ms { delta = 100ms } else { delta = round(delta, 0.25) }",
+				vars: map[string]any{"delta": 0.6},
+			},
+			expected: &expected{
+				vars: map[string]any{"delta": 0.5},
+			},
+		},
+		{
+			name: "happy path: arithmetic and assignment operators",
+			args: &args{
+				src:  "acc += delta * 2 - 1\nn = -(acc % 3) / 2",
+				vars: map[string]any{"acc": float64(1), "delta": float64(3)},
+			},
+			expected: &expected{
+				vars: map[string]any{"acc": float64(6), "delta": float64(3), "n": float64(0)},
+			},
+		},
+		{
+			name: "happy path: functions",
+			args: &args{
+				src: `if hasPrefix(data, "$") && !contains(data, "rm") {
+	data = replaceRegexp(upper(data), "\\s+", " ") + string(len(data))
+}`,
+				vars: map[string]any{"data": "$  ls\t-la"},
+			},
+			expected: &expected{
+				vars: map[string]any{"data": "$ LS -LA9"},
+			},
+		},
+		{
+			name: "happy path: short-circuit",
+			args: &args{
+				src:  `if code == "r" || undefined { drop }`,
+				vars: map[string]any{"code": "r"},
+			},
+			expected: &expected{
+				vars:    map[string]any{"code": "r"},
+				dropped: true,
+			},
+		},
+		{
+			name: "edge path: undefined variable",
+			args: &args{
+				src:  `delta = dleta`,
+				vars: map[string]any{"delta": float64(1)},
+			},
+			expected: &expected{
+				err: errors.New("1:9: undefined: dleta"),
+			},
+		},
+		{
+			name: "edge path: mismatched types",
+			args: &args{
+				src:  `if data > 3 { drop }`,
+				vars: map[string]any{"data": "a"},
+			},
+			expected: &expected{
+				err: errors.New("1:9: invalid operation: string > number"),
+			},
+		},
+		{
+			name: "edge path: non-bool condition",
+			args: &args{
+				src:  `if delta { drop }`,
+				vars: map[string]any{"delta": float64(1)},
+			},
+			expected: &expected{
+				err: errors.New("1:4: non-bool condition: number"),
+			},
+		},
+		{
+			name: "edge path: wrong argument",
+			args: &args{
+				src:  `data = upper(delta)`,
+				vars: map[string]any{"data": "a", "delta": float64(1)},
+			},
+			expected: &expected{
+				err: errors.New("1:8: upper argument 1 must be string, got number"),
+			},
+		},
+		{
+			name: "edge path: invalid pattern",
+			args: &args{
+				src:  `if match(data, "(") { drop }`,
+				vars: map[string]any{"data": "a"},
+			},
+			expected: &expected{
+				err: errors.New("1:4: invalid pattern: ("),
+			},
+		},
+		{
+			name: "edge path: division by zero",
+			args: &args{
+				src:  `delta = delta / 0`,
+				vars: map[string]any{"delta": float64(1)},
+			},
+			expected: &expected{
+				err: errors.New("1:15: division by zero"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			p, err := Parse(tt.args.src)
+			assert.NoError(t, err)
+
+			// Act
+			dropped, err := p.Run(tt.args.vars)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.vars, tt.args.vars)
+				assert.Equal(t, tt.expected.dropped, dropped)
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOp
+	tokenNewline
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  Pos
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of script"
+	case tokenNewline:
+		return "newline"
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// Pos is a position in a script, counted from 1.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Col)
+}
+
+var (
+	// NOTE: longer operators first so that "<=" is not read as "<" and "="
+	operators = []string{
+		"&&", "||", "==", "!=", "<=", ">=", "+=", "-=", "*=", "/=",
+		"+", "-", "*", "/", "%", "<", ">", "=", "!", "(", ")", "{", "}", ",", ";",
+	}
+)
+
+func lex(src string) ([]token, error) {
+	tokens := make([]token, 0)
+	pos := Pos{Line: 1, Col: 1}
+
+	advance := func(s string) {
+		for _, r := range s {
+			if r == '\n' {
+				pos.Line++
+				pos.Col = 1
+			} else {
+				pos.Col++
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := pos
+
+		switch {
+		case r == '\n':
+			tokens = append(tokens, token{kind: tokenNewline, text: "\n", pos: start})
+			advance(src[i : i+size])
+			i += size
+		case unicode.IsSpace(r):
+			advance(src[i : i+size])
+			i += size
+		case r == '#':
+			n := strings.IndexByte(src[i:], '\n')
+			if n < 0 {
+				n = len(src) - i
+			}
+			advance(src[i : i+n])
+			i += n
+		case r == '_' || unicode.IsLetter(r):
+			n := i + size
+			for n < len(src) {
+				r, size := utf8.DecodeRuneInString(src[n:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				n += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[i:n], pos: start})
+			advance(src[i:n])
+			i = n
+		case unicode.IsDigit(r) || r == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			n := i
+			for n < len(src) && (isDigit(src[n]) || src[n] == '.' || src[n] == '_') {
+				n++
+			}
+			// NOTE: a unit suffix such as "500ms" belongs to the number
+			for n < len(src) && unicode.IsLetter(rune(src[n])) {
+				n++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:n], pos: start})
+			advance(src[i:n])
+			i = n
+		case r == '"' || r == '`':
+			n := i + 1
+			for n < len(src) && src[n] != byte(r) && src[n] != '\n' {
+				if r == '"' && src[n] == '\\' {
+					n++
+				}
+				n++
+			}
+			if n >= len(src) || src[n] != byte(r) {
+				return nil, fmt.Errorf("%v: unterminated string", start)
+			}
+			n++
+			tokens = append(tokens, token{kind: tokenString, text: src[i:n], pos: start})
+			advance(src[i:n])
+			i = n
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("%v: unexpected character %q", start, r)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start})
+			advance(op)
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: pos}), nil
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLex(t *testing.T) {
+	type args struct {
+		src string
+	}
+
+	type expected struct {
+		texts []string
+		err   error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				src: `if code=="o" && delta>=500ms { delta = 1 } # comment`,
+			},
+			expected: &expected{
+				texts: []string{"if", "code", "==", `"o"`, "&&", "delta", ">=", "500ms", "{", "delta", "=", "1", "}", ""},
+			},
+		},
+		{
+			name: "happy path: raw string and newline",
+			args: &args{
+				src: "data = `\\r`\nacc += .5",
+			},
+			expected: &expected{
+				texts: []string{"data", "=", "`\\r`", "\n", "acc", "+=", ".5", ""},
+			},
+		},
+		{
+			name: "edge path: unterminated string",
+			args: &args{
+				src: `code == "o`,
+			},
+			expected: &expected{
+				err: errors.New("1:9: unterminated string"),
+			},
+		},
+		{
+			name: "edge path: unexpected character",
+			args: &args{
+				src: "delta\n  = @",
+			},
+			expected: &expected{
+				err: errors.New(`2:5: unexpected character '@'`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+
+			// Act
+			tokens, err := lex(tt.args.src)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				texts := make([]string, 0, len(tokens))
+				for _, tok := range tokens {
+					texts = append(texts, tok.text)
+				}
+				assert.Equal(t, tt.expected.texts, texts)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type stmt interface {
+	stmt()
+}
+
+type (
+	assignStmt struct {
+		pos  Pos
+		name string
+		op   string
+		expr expr
+	}
+
+	ifStmt struct {
+		cond expr
+		then []stmt
+		els  []stmt
+	}
+
+	dropStmt struct{}
+)
+
+func (*assignStmt) stmt() {}
+func (*ifStmt) stmt()     {}
+func (*dropStmt) stmt()   {}
+
+type expr interface {
+	position() Pos
+}
+
+type (
+	literal struct {
+		pos   Pos
+		value any
+	}
+
+	ident struct {
+		pos  Pos
+		name string
+	}
+
+	unary struct {
+		pos Pos
+		op  string
+		x   expr
+	}
+
+	binary struct {
+		pos  Pos
+		op   string
+		x, y expr
+	}
+
+	call struct {
+		pos  Pos
+		name string
+		args []expr
+	}
+)
+
+func (e *literal) position() Pos { return e.pos }
+func (e *ident) position() Pos   { return e.pos }
+func (e *unary) position() Pos   { return e.pos }
+func (e *binary) position() Pos  { return e.pos }
+func (e *call) position() Pos    { return e.pos }
+
+var (
+	// NOTE: operator precedence from the loosest, as in Go
+	precedences = [][]string{
+		{"||"},
+		{"&&"},
+		{"==", "!=", "<", "<=", ">", ">="},
+		{"+", "-"},
+		{"*", "/", "%"},
+	}
+)
+
+// Program is a parsed script.
+type Program struct {
+	stmts   []stmt
+	regexps sync.Map
+}
+
+// Parse parses a script.
+func Parse(src string) (*Program, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	stmts, err := p.stmts(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{stmts: stmts}, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) is(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && t.text == text
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if !p.is(kind, text) {
+		return unexpected(p.peek())
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) skipNewlines() {
+	for p.peek().kind == tokenNewline || p.is(tokenOp, ";") {
+		p.next()
+	}
+}
+
+func unexpected(t token) error {
+	return fmt.Errorf("%v: unexpected %v", t.pos, t)
+}
+
+func (p *parser) stmts(block bool) ([]stmt, error) {
+	stmts := make([]stmt, 0)
+
+	for {
+		p.skipNewlines()
+
+		t := p.peek()
+		if t.kind == tokenEOF {
+			if block {
+				return nil, unexpected(t)
+			}
+			return stmts, nil
+		}
+		if block && p.is(tokenOp, "}") {
+			p.next()
+			return stmts, nil
+		}
+
+		s, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s)
+
+		// NOTE: statements are separated by newlines or semicolons
+		if t := p.peek(); t.kind != tokenEOF && t.kind != tokenNewline && !p.is(tokenOp, ";") && !(block && p.is(tokenOp, "}")) {
+			return nil, unexpected(t)
+		}
+	}
+}
+
+func (p *parser) block() ([]stmt, error) {
+	if err := p.expect(tokenOp, "{"); err != nil {
+		return nil, err
+	}
+
+	return p.stmts(true)
+}
+
+func (p *parser) stmt() (stmt, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return nil, unexpected(t)
+	}
+
+	switch t.text {
+	case "if":
+		return p.ifStmt()
+	case "drop":
+		return new(dropStmt), nil
+	}
+
+	op := p.peek()
+	switch {
+	case op.kind == tokenOp && (op.text == "=" || op.text == "+=" || op.text == "-=" || op.text == "*=" || op.text == "/="):
+		p.next()
+	default:
+		return nil, unexpected(op)
+	}
+
+	x, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &assignStmt{pos: t.pos, name: t.text, op: op.text, expr: x}, nil
+}
+
+func (p *parser) ifStmt() (stmt, error) {
+	cond, err := p.expr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	then, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ifStmt{cond: cond, then: then}
+	if !p.is(tokenIdent, "else") {
+		return s, nil
+	}
+	p.next()
+
+	if p.is(tokenIdent, "if") {
+		p.next()
+		els, err := p.ifStmt()
+		if err != nil {
+			return nil, err
+		}
+		s.els = []stmt{els}
+		return s, nil
+	}
+
+	if s.els, err = p.block(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (p *parser) expr(level int) (expr, error) {
+	if level == len(precedences) {
+		return p.unary()
+	}
+
+	x, err := p.expr(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenOp || !slices.Contains(precedences[level], t.text) {
+			return x, nil
+		}
+		p.next()
+
+		y, err := p.expr(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		x = &binary{pos: t.pos, op: t.text, x: x, y: y}
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	if t := p.peek(); p.is(tokenOp, "!") || p.is(tokenOp, "-") {
+		p.next()
+
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unary{pos: t.pos, op: t.text, x: x}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		v, err := parseNumber(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", t.pos, err)
+		}
+		return &literal{pos: t.pos, value: v}, nil
+	case tokenString:
+		v, err := strconv.Unquote(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("%v: invalid string: %v", t.pos, t.text)
+		}
+		return &literal{pos: t.pos, value: v}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return &literal{pos: t.pos, value: true}, nil
+		case "false":
+			return &literal{pos: t.pos, value: false}, nil
+		case "if", "else", "drop":
+			return nil, unexpected(t)
+		}
+
+		if !p.is(tokenOp, "(") {
+			return &ident{pos: t.pos, name: t.text}, nil
+		}
+		p.next()
+
+		args := make([]expr, 0)
+		for !p.is(tokenOp, ")") {
+			x, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, x)
+
+			if !p.is(tokenOp, ",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(tokenOp, ")"); err != nil {
+			return nil, err
+		}
+
+		return &call{pos: t.pos, name: t.text, args: args}, nil
+	case tokenOp:
+		if t.text == "(" {
+			x, err := p.expr(0)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(tokenOp, ")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+
+	return nil, unexpected(t)
+}
+
+// parseNumber parses a number in seconds, with an optional Go duration unit such as "500ms".
+func parseNumber(s string) (float64, error) {
+	s = strings.ReplaceAll(s, "_", "")
+
+	n := strings.IndexFunc(s, func(r rune) bool { return r != '.' && (r < '0' || r > '9') })
+	if n < 0 {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number: %v", s)
+		}
+		return v, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %v", s)
+	}
+
+	return d.Seconds(), nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package script
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		src string
+	}
+
+	type expected struct {
+		err error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: one line",
+			args: &args{
+				src: `if code=="o" && delta>3 { delta = 1 }`,
+			},
+			expected: &expected{},
+		},
+		{
+			name: "happy path: multiple lines",
+			args: &args{
+				src: `# shorten long pauses
+if delta > 3 {
+	delta = 1
+} else if code == "i" {
+	acc += 1; data = upper(data)
+} else {
+	drop
+}
+`,
+			},
+			expected: &expected{},
+		},
+		{
+			name: "happy path: empty",
+			args: &args{
+				src: "",
+			},
+			expected: &expected{},
+		},
+		{
+			name: "edge path: missing block",
+			args: &args{
+				src: `if delta > 3 delta = 1`,
+			},
+			expected: &expected{
+				err: errors.New(`1:14: unexpected "delta"`),
+			},
+		},
+		{
+			name: "edge path: unclosed block",
+			args: &args{
+				src: "if delta > 3 {\n\tdelta = 1\n",
+			},
+			expected: &expected{
+				err: errors.New("3:1: unexpected end of script"),
+			},
+		},
+		{
+			name: "edge path: expression statement",
+			args: &args{
+				src: `delta > 3`,
+			},
+			expected: &expected{
+				err: errors.New(`1:7: unexpected ">"`),
+			},
+		},
+		{
+			name: "edge path: two statements on a line",
+			args: &args{
+				src: `delta = 1 time = 2`,
+			},
+			expected: &expected{
+				err: errors.New(`1:11: unexpected "time"`),
+			},
+		},
+		{
+			name: "edge path: invalid number",
+			args: &args{
+				src: `delta = 5years`,
+			},
+			expected: &expected{
+				err: errors.New("1:9: invalid number: 5years"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+
+			// Act
+			p, err := Parse(tt.args.src)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NotNil(t, p)
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
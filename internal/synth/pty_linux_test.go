@@ -0,0 +1,139 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build linux
+
+package synth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScript_Generate_Run(t *testing.T) {
+	type args struct {
+		script string
+	}
+
+	type expected struct {
+		output string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				script: `cadence: 0s
+steps:
+  - type: printf 'héllo\n'; exit 3
+    run: true
+`,
+			},
+			expected: &expected{
+				output: "héllo\r\n",
+			},
+		},
+		{
+			name: "happy path: terminal size",
+			args: &args{
+				script: `cadence: 0s
+steps:
+  - resize: 100x30
+  - type: stty size
+    run: true
+`,
+			},
+			expected: &expected{
+				output: "30 100\r\n",
+			},
+		},
+		{
+			name: "happy path: command reading input",
+			args: &args{
+				script: `cadence: 0s
+steps:
+  - type: cat
+    run: true
+`,
+			},
+			expected: &expected{
+				output: "",
+			},
+		},
+		{
+			name: "edge path: timeout",
+			args: &args{
+				script: `cadence: 0s
+steps:
+  - type: sleep 10
+    run: true
+    timeout: 100ms
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("run %v: %w", "sleep 10", errors.New("timed out after 100ms")),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			s, _ := Parse(strings.NewReader(tt.args.script), t.TempDir())
+
+			// Act
+			_, events, err := s.Generate(ctx)
+
+			// Assert
+			if !strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			output := new(strings.Builder)
+			entered := false
+			for i, e := range events {
+				if i > 0 {
+					assert.False(t, e.Time.Decimal().LessThan(events[i-1].Time.Decimal()))
+				}
+
+				if data, _ := e.Data.(string); entered {
+					output.WriteString(data)
+				} else if data == "\r\n" {
+					entered = true
+				}
+			}
+			assert.Equal(t, tt.expected.output, output.String())
+		})
+	}
+}
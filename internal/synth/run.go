@@ -0,0 +1,102 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package synth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/Aton-Kish/deltascii/internal/xutf8"
+	"github.com/shopspring/decimal"
+)
+
+type chunk struct {
+	at   decimal.Decimal
+	data string
+}
+
+// run runs command with shell on a pseudo terminal of width by height, and
+// returns its output as it was read and how long it ran, timed in microseconds
+// since the start. The exit status is ignored, so that failing commands can be
+// shown too, and a command still running after timeout is killed.
+func run(ctx context.Context, shell, command, dir string, env []string, width, height int, timeout time.Duration) ([]chunk, decimal.Decimal, error) {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, shell, "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+
+	start := time.Now()
+	elapsed := func() decimal.Decimal {
+		return decimal.New(time.Since(start).Microseconds(), -6)
+	}
+
+	pty, err := startPTY(cmd, width, height)
+	if err != nil {
+		return nil, decimal.Zero, err
+	}
+	defer pty.Close()
+
+	// NOTE: nothing is typed into the command, so end its input for commands reading it such as cat
+	if _, err := pty.Write([]byte("\x04")); err != nil {
+		_ = cmd.Wait()
+		return nil, decimal.Zero, err
+	}
+
+	chunks := make([]chunk, 0)
+	var dec xutf8.Decoder
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := pty.Read(buf)
+		if s := dec.Decode(buf[:n]); s != "" {
+			chunks = append(chunks, chunk{at: elapsed(), data: s})
+		}
+
+		if err != nil {
+			// NOTE: reading a pseudo terminal fails rather than ends once the command closes it
+			if !errors.Is(err, io.EOF) && !ptyClosed(err) {
+				_ = cmd.Wait()
+				return nil, decimal.Zero, err
+			}
+			break
+		}
+	}
+
+	if s := dec.Flush(); s != "" {
+		chunks = append(chunks, chunk{at: elapsed(), data: s})
+	}
+
+	_ = cmd.Wait()
+	if err := parent.Err(); err != nil {
+		return nil, decimal.Zero, err
+	}
+	if ctx.Err() != nil {
+		return nil, decimal.Zero, fmt.Errorf("timed out after %v", timeout)
+	}
+
+	return chunks, elapsed(), nil
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package synth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/theme"
+	"github.com/Aton-Kish/deltascii/internal/transform"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	resizePattern = regexp.MustCompile(`^([1-9][0-9]*)x([1-9][0-9]*)$`)
+
+	stepKinds   = []string{"type", "print", "pause", "clear", "marker", "resize"}
+	typeOptions = []string{"cadence", "enter", "run", "timeout"}
+)
+
+const (
+	// clearScreen is what clear writes, homing the cursor and erasing the screen
+	// and the scrollback.
+	clearScreen = "\x1b[H\x1b[2J\x1b[3J"
+
+	// defaultTimeout is how long a command may run before it is killed.
+	defaultTimeout = time.Minute
+)
+
+// Script is a terminal session read from a script file such as
+//
+//	width: 80
+//	height: 24
+//	steps:
+//	  - print: "$ "
+//	  - type: ls -la
+//	    run: true
+//	  - pause: 2s
+//	  - clear
+type Script struct {
+	header *asciinema.V2Header
+	shell  string
+	dir    string
+	steps  []step
+}
+
+type scriptFile struct {
+	Width   int               `yaml:"width"`
+	Height  int               `yaml:"height"`
+	Title   string            `yaml:"title"`
+	Env     map[string]string `yaml:"env"`
+	Theme   string            `yaml:"theme"`
+	Shell   string            `yaml:"shell"`
+	Cadence transform.Seconds `yaml:"cadence"`
+	Steps   []yaml.Node       `yaml:"steps"`
+}
+
+type step struct {
+	kind    string
+	text    string
+	pause   decimal.Decimal
+	cadence float64
+	enter   bool
+	run     bool
+	timeout time.Duration
+}
+
+// Load reads a script file. Commands run in the directory of the script file.
+func Load(name string) (*Script, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f, filepath.Dir(name))
+}
+
+// Parse reads a script, whose commands run in dir.
+func Parse(r io.Reader, dir string) (*Script, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	sf := scriptFile{Width: 80, Height: 24, Shell: "/bin/sh", Cadence: 0.1}
+	if err := dec.Decode(&sf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(sf.Steps) == 0 {
+		return nil, errors.New("no steps in script")
+	}
+
+	if sf.Width <= 0 || sf.Height <= 0 {
+		return nil, fmt.Errorf("invalid size: %vx%v", sf.Width, sf.Height)
+	}
+
+	if sf.Cadence < 0 {
+		return nil, fmt.Errorf("invalid cadence: %v", sf.Cadence)
+	}
+
+	h := &asciinema.V2Header{Version: 2, Width: sf.Width, Height: sf.Height, Title: sf.Title, Env: sf.Env}
+	if sf.Theme != "" {
+		t, err := theme.Lookup(sf.Theme)
+		if err != nil {
+			return nil, err
+		}
+		h.Theme = t
+	}
+
+	s := &Script{header: h, shell: sf.Shell, dir: dir, steps: make([]step, 0, len(sf.Steps))}
+	for i := range sf.Steps {
+		node := &sf.Steps[i]
+
+		st, err := parseStep(node, float64(sf.Cadence))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+
+		s.steps = append(s.steps, *st)
+	}
+
+	return s, nil
+}
+
+// parseStep reads a step, a mapping keyed by its kind with options for type, or
+// the bare word clear.
+func parseStep(node *yaml.Node, cadence float64) (*step, error) {
+	if node.Kind == yaml.ScalarNode && node.Value == "clear" {
+		return &step{kind: "clear"}, nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil, errors.New("invalid step")
+	}
+
+	st := &step{cadence: cadence, enter: true, timeout: defaultTimeout}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if !slices.Contains(stepKinds, key) {
+			if !slices.Contains(typeOptions, key) {
+				return nil, fmt.Errorf("invalid parameter: %v", key)
+			}
+			continue
+		}
+
+		if st.kind != "" {
+			return nil, fmt.Errorf("either %v or %v is allowed for each step", st.kind, key)
+		}
+		st.kind = key
+	}
+
+	if st.kind == "" {
+		return nil, fmt.Errorf("one of %v is required for each step", strings.Join(stepKinds, ", "))
+	}
+
+	var p struct {
+		Type    string             `yaml:"type"`
+		Print   string             `yaml:"print"`
+		Pause   transform.Seconds  `yaml:"pause"`
+		Marker  string             `yaml:"marker"`
+		Resize  string             `yaml:"resize"`
+		Cadence *transform.Seconds `yaml:"cadence"`
+		Enter   *bool              `yaml:"enter"`
+		Run     bool               `yaml:"run"`
+		Timeout *transform.Seconds `yaml:"timeout"`
+	}
+	if err := node.Decode(&p); err != nil {
+		return nil, err
+	}
+
+	if st.kind != "type" && (p.Cadence != nil || p.Enter != nil || p.Run || p.Timeout != nil) {
+		return nil, fmt.Errorf("%v are only allowed for type", strings.Join(typeOptions, ", "))
+	}
+
+	switch st.kind {
+	case "type":
+		st.text, st.run = p.Type, p.Run
+		if p.Cadence != nil {
+			if *p.Cadence < 0 {
+				return nil, fmt.Errorf("invalid cadence: %v", *p.Cadence)
+			}
+			st.cadence = float64(*p.Cadence)
+		}
+		if p.Enter != nil {
+			st.enter = *p.Enter
+		}
+		if p.Timeout != nil {
+			if *p.Timeout <= 0 {
+				return nil, fmt.Errorf("invalid timeout: %v", *p.Timeout)
+			}
+			st.timeout = time.Duration(float64(*p.Timeout) * float64(time.Second))
+		}
+	case "print":
+		st.text = p.Print
+	case "pause":
+		if p.Pause < 0 {
+			return nil, fmt.Errorf("invalid pause: %v", p.Pause)
+		}
+		st.pause = decimal.NewFromFloat(float64(p.Pause))
+	case "marker":
+		st.text = p.Marker
+	case "resize":
+		if !resizePattern.MatchString(p.Resize) {
+			return nil, fmt.Errorf("invalid resize: %v", p.Resize)
+		}
+		st.text = p.Resize
+	}
+
+	return st, nil
+}
+
+// Generate plays the script into asciicast v2. Typed text is echoed character by
+// character at the cadence, and commands marked to run are run with the shell
+// on a pseudo terminal of the current size, their output timed as it came.
+func (s *Script) Generate(ctx context.Context) (*asciinema.V2Header, []asciinema.V2Event, error) {
+	h := *s.header
+	width, height := h.Width, h.Height
+
+	env := os.Environ()
+	for k, v := range h.Env {
+		env = append(env, k+"="+v)
+	}
+
+	events := make([]asciinema.V2Event, 0)
+	clock := decimal.Zero
+	emit := func(code, data string) {
+		events = append(events, asciinema.V2Event{Time: asciinema.NewTime(clock), Code: code, Data: data})
+	}
+
+	for _, st := range s.steps {
+		switch st.kind {
+		case "type":
+			typed, err := transform.Apply(transform.Explode(transform.ExplodeChar, st.cadence), &h, []asciinema.V2Event{{Time: asciinema.NewTime(clock), Code: "o", Data: st.text}})
+			if err != nil {
+				return nil, nil, err
+			}
+			events = append(events, typed...)
+
+			if len(typed) > 0 {
+				clock = typed[len(typed)-1].Time.Decimal().Add(decimal.NewFromFloat(st.cadence))
+			}
+			if st.enter {
+				emit("o", "\r\n")
+			}
+
+			if st.run {
+				chunks, elapsed, err := run(ctx, s.shell, st.text, s.dir, env, width, height, st.timeout)
+				if err != nil {
+					return nil, nil, fmt.Errorf("run %v: %w", st.text, err)
+				}
+
+				start := clock
+				for _, c := range chunks {
+					clock = start.Add(c.at)
+					emit("o", c.data)
+				}
+				clock = start.Add(elapsed)
+			}
+		case "print":
+			emit("o", st.text)
+		case "pause":
+			clock = clock.Add(st.pause)
+		case "clear":
+			emit("o", clearScreen)
+		case "marker":
+			emit("m", st.text)
+		case "resize":
+			emit("r", st.text)
+			_, _ = fmt.Sscanf(st.text, "%dx%d", &width, &height)
+		}
+	}
+
+	return &h, events, nil
+}
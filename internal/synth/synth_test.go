@@ -0,0 +1,219 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package synth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScript_Generate(t *testing.T) {
+	type args struct {
+		script string
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				script: `title: Demo
+cadence: 100ms
+steps:
+  - print: "$ "
+  - pause: 1s
+  - type: ls
+  - print: "a.txt\r\n"
+  - marker: listed
+  - pause: 500ms
+  - resize: 100x30
+  - clear
+  - type: exit
+    cadence: 50ms
+    enter: false
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Title: "Demo"},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "1", Code: "o", Data: "l"},
+					{Time: "1.1", Code: "o", Data: "s"},
+					{Time: "1.2", Code: "o", Data: "\r\n"},
+					{Time: "1.2", Code: "o", Data: "a.txt\r\n"},
+					{Time: "1.2", Code: "m", Data: "listed"},
+					{Time: "1.7", Code: "r", Data: "100x30"},
+					{Time: "1.7", Code: "o", Data: "\u001b[H\u001b[2J\u001b[3J"},
+					{Time: "1.7", Code: "o", Data: "e"},
+					{Time: "1.75", Code: "o", Data: "x"},
+					{Time: "1.8", Code: "o", Data: "i"},
+					{Time: "1.85", Code: "o", Data: "t"},
+				},
+			},
+		},
+		{
+			name: "happy path: size and theme",
+			args: &args{
+				script: `width: 100
+height: 30
+theme: solarized-dark
+steps:
+  - type: "é"
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 100, Height: 30, Theme: &asciinema.V2HeaderTheme{FG: "#839496", BG: "#002b36", Palette: "#073642:#dc322f:#859900:#b58900:#268bd2:#d33682:#2aa198:#eee8d5:#002b36:#cb4b16:#586e75:#657b83:#839496:#6c71c4:#93a1a1:#fdf6e3"}},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "é"},
+					{Time: "0.1", Code: "o", Data: "\r\n"},
+				},
+			},
+		},
+		{
+			name: "edge path: no steps",
+			args: &args{
+				script: "width: 80\n",
+			},
+			expected: &expected{
+				err: errors.New("no steps in script"),
+			},
+		},
+		{
+			name: "edge path: two kinds",
+			args: &args{
+				script: `steps:
+  - print: a
+    pause: 1s
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("either print or pause is allowed for each step")),
+			},
+		},
+		{
+			name: "edge path: no kind",
+			args: &args{
+				script: `steps:
+  - cadence: 1s
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("one of type, print, pause, clear, marker, resize is required for each step")),
+			},
+		},
+		{
+			name: "edge path: type option for print",
+			args: &args{
+				script: `steps:
+  - print: a
+    run: true
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("cadence, enter, run, timeout are only allowed for type")),
+			},
+		},
+		{
+			name: "edge path: invalid parameter",
+			args: &args{
+				script: `steps:
+  - type: ls
+    speed: 2
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("invalid parameter: speed")),
+			},
+		},
+		{
+			name: "edge path: invalid resize",
+			args: &args{
+				script: `steps:
+  - resize: 100
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("invalid resize: 100")),
+			},
+		},
+		{
+			name: "edge path: invalid timeout",
+			args: &args{
+				script: `steps:
+  - type: ls
+    run: true
+    timeout: 0s
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("invalid timeout: 0")),
+			},
+		},
+		{
+			name: "edge path: invalid step",
+			args: &args{
+				script: `steps:
+  - wait
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("invalid step")),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+
+			// Act
+			s, err := Parse(strings.NewReader(tt.args.script), t.TempDir())
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+
+				h, events, err := s.Generate(ctx)
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
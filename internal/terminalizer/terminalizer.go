@@ -0,0 +1,211 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package terminalizer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	themeKeys = []string{
+		"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+		"brightBlack", "brightRed", "brightGreen", "brightYellow", "brightBlue", "brightMagenta", "brightCyan", "brightWhite",
+	}
+)
+
+type Recording struct {
+	Config  Config   `yaml:"config"`
+	Records []Record `yaml:"records"`
+}
+
+type Config struct {
+	Command     string            `yaml:"command,omitempty"`
+	Cols        any               `yaml:"cols"`
+	Rows        any               `yaml:"rows"`
+	MaxIdleTime any               `yaml:"maxIdleTime,omitempty"`
+	Theme       map[string]string `yaml:"theme,omitempty"`
+	// NOTE: rendering options such as fontFamily are kept as is
+	Extra map[string]any `yaml:",inline"`
+}
+
+type Record struct {
+	Delay   float64 `yaml:"delay"`
+	Content string  `yaml:"content"`
+}
+
+func Read(r io.Reader) (*Recording, error) {
+	rec := new(Recording)
+	if err := yaml.NewDecoder(r).Decode(rec); err != nil {
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+func Write(w io.Writer, rec *Recording) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	if err := enc.Encode(rec); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// ToV2 converts records into an asciicast. Delays in milliseconds are accumulated into times.
+// When width or height is 0, it is taken from cols and rows of the config.
+func ToV2(rec *Recording, width, height int) (*asciinema.V2Header, []asciinema.V2Event, error) {
+	if width == 0 {
+		width, _ = size(rec.Config.Cols)
+	}
+	if height == 0 {
+		height, _ = size(rec.Config.Rows)
+	}
+	if width == 0 || height == 0 {
+		return nil, nil, errors.New("unable to infer terminal size, width and height required")
+	}
+
+	h := &asciinema.V2Header{
+		Version: 2,
+		Width:   width,
+		Height:  height,
+		Command: rec.Config.Command,
+	}
+
+	if ms, ok := size(rec.Config.MaxIdleTime); ok {
+		h.IdleTimeLimit = decimal.NewFromInt(int64(ms)).Shift(-3).InexactFloat64()
+	}
+
+	if t := toV2Theme(rec.Config.Theme); t != nil {
+		h.Theme = t
+	}
+
+	events := make([]asciinema.V2Event, 0, len(rec.Records))
+	t := decimal.Zero
+	for _, r := range rec.Records {
+		t = t.Add(decimal.NewFromFloat(r.Delay).Shift(-3))
+		events = append(events, asciinema.V2Event{Time: asciinema.NewTime(t), Code: "o", Data: r.Content})
+	}
+
+	return h, events, nil
+}
+
+// FromV2 converts output events into records. Delays are rounded to milliseconds
+// without accumulating the rounding error.
+func FromV2(h *asciinema.V2Header, events []asciinema.V2Event) (*Recording, error) {
+	rec := &Recording{
+		Config: Config{
+			Command: h.Command,
+			Cols:    h.Width,
+			Rows:    h.Height,
+		},
+		Records: make([]Record, 0, len(events)),
+	}
+
+	if h.IdleTimeLimit != 0 {
+		rec.Config.MaxIdleTime = decimal.NewFromFloat(h.IdleTimeLimit).Shift(3).Round(0).IntPart()
+	}
+
+	if h.Theme != nil {
+		rec.Config.Theme = fromV2Theme(h.Theme)
+	}
+
+	prev := int64(0)
+	for _, e := range events {
+		if e.Code != "o" {
+			continue
+		}
+
+		data, ok := e.Data.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid event data: %v", e.Data)
+		}
+
+		ms := e.Time.Decimal().Shift(3).Round(0).IntPart()
+		rec.Records = append(rec.Records, Record{Delay: float64(ms - prev), Content: data})
+		prev = ms
+	}
+
+	return rec, nil
+}
+
+func size(v any) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case string:
+		// NOTE: "auto" means the size of the current terminal
+		i, err := strconv.Atoi(n)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func toV2Theme(theme map[string]string) *asciinema.V2HeaderTheme {
+	if len(theme) == 0 {
+		return nil
+	}
+
+	colors := make([]string, 0, len(themeKeys))
+	for _, key := range themeKeys {
+		colors = append(colors, theme[key])
+	}
+
+	t := &asciinema.V2HeaderTheme{
+		FG:      theme["foreground"],
+		BG:      theme["background"],
+		Palette: strings.Join(colors, ":"),
+	}
+
+	// NOTE: such as "transparent" background, which asciicast cannot express
+	if err := t.Validate(); err != nil {
+		return nil
+	}
+
+	return t
+}
+
+func fromV2Theme(t *asciinema.V2HeaderTheme) map[string]string {
+	theme := map[string]string{
+		"foreground": t.FG,
+		"background": t.BG,
+	}
+
+	colors := strings.Split(t.Palette, ":")
+	for i, key := range themeKeys {
+		// NOTE: 8 color palette is repeated for bright colors
+		theme[key] = colors[i%len(colors)]
+	}
+
+	return theme
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package terminalizer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToV2(t *testing.T) {
+	type args struct {
+		name   string
+		data   string
+		width  int
+		height int
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				name: "testdata/demo.yml",
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{
+					Version:       2,
+					Width:         80,
+					Height:        24,
+					IdleTimeLimit: 2,
+					Command:       "bash -l",
+				},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "$ "},
+					{Time: "0.6", Code: "o", Data: "l"},
+					{Time: "0.75", Code: "o", Data: "s"},
+					{Time: "1", Code: "o", Data: "\r\nfoo\r\n$ "},
+				},
+			},
+		},
+		{
+			name: "happy path: theme",
+			args: &args{
+				data: `config:
+  cols: auto
+  rows: auto
+  theme:
+    background: "#000000"
+    foreground: "#ffffff"
+    black: "#000000"
+    red: "#ff0000"
+    green: "#00ff00"
+    yellow: "#ffff00"
+    blue: "#0000ff"
+    magenta: "#ff00ff"
+    cyan: "#00ffff"
+    white: "#ffffff"
+    brightBlack: "#000000"
+    brightRed: "#ff0000"
+    brightGreen: "#00ff00"
+    brightYellow: "#ffff00"
+    brightBlue: "#0000ff"
+    brightMagenta: "#ff00ff"
+    brightCyan: "#00ffff"
+    brightWhite: "#ffffff"
+records:
+  - delay: 1.5
+    content: "$ "
+`,
+				width:  100,
+				height: 30,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{
+					Version: 2,
+					Width:   100,
+					Height:  30,
+					Theme: &asciinema.V2HeaderTheme{
+						FG:      "#ffffff",
+						BG:      "#000000",
+						Palette: "#000000:#ff0000:#00ff00:#ffff00:#0000ff:#ff00ff:#00ffff:#ffffff:#000000:#ff0000:#00ff00:#ffff00:#0000ff:#ff00ff:#00ffff:#ffffff",
+					},
+				},
+				events: []asciinema.V2Event{
+					{Time: "0.0015", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "edge path: auto size",
+			args: &args{
+				data: `config:
+  cols: auto
+  rows: auto
+records: []
+`,
+			},
+			expected: &expected{
+				err: errors.New("unable to infer terminal size, width and height required"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			data := []byte(tt.args.data)
+			if tt.args.name != "" {
+				data, _ = os.ReadFile(tt.args.name)
+			}
+
+			rec, err := Read(bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			// Act
+			h, events, err := ToV2(rec, tt.args.width, tt.args.height)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, h)
+				assert.Nil(t, events)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestFromV2(t *testing.T) {
+	type args struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		data string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				header: &asciinema.V2Header{
+					Version:       2,
+					Width:         80,
+					Height:        24,
+					IdleTimeLimit: 2,
+					Theme: &asciinema.V2HeaderTheme{
+						FG:      "#ffffff",
+						BG:      "#000000",
+						Palette: "#000000:#ff0000:#00ff00:#ffff00:#0000ff:#ff00ff:#00ffff:#ffffff",
+					},
+				},
+				events: []asciinema.V2Event{
+					{Time: "0.5004", Code: "o", Data: "$ "},
+					{Time: "0.6", Code: "i", Data: "l"},
+					{Time: "0.6004", Code: "o", Data: "l"},
+					{Time: "0.7006", Code: "o", Data: "\u001b[0m\r\n"},
+				},
+			},
+			expected: &expected{
+				data: `config:
+  cols: 80
+  rows: 24
+  maxIdleTime: 2000
+  theme:
+    background: '#000000'
+    black: '#000000'
+    blue: '#0000ff'
+    brightBlack: '#000000'
+    brightBlue: '#0000ff'
+    brightCyan: '#00ffff'
+    brightGreen: '#00ff00'
+    brightMagenta: '#ff00ff'
+    brightRed: '#ff0000'
+    brightWhite: '#ffffff'
+    brightYellow: '#ffff00'
+    cyan: '#00ffff'
+    foreground: '#ffffff'
+    green: '#00ff00'
+    magenta: '#ff00ff'
+    red: '#ff0000'
+    white: '#ffffff'
+    yellow: '#ffff00'
+records:
+  - delay: 500
+    content: '$ '
+  - delay: 100
+    content: l
+  - delay: 101
+    content: "\e[0m\r\n"
+`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			buf := new(bytes.Buffer)
+
+			// Act
+			rec, err := FromV2(tt.args.header, tt.args.events)
+			assert.NoError(t, err)
+			err = Write(buf, rec)
+
+			// Assert
+			assert.Equal(t, tt.expected.data, buf.String())
+			assert.NoError(t, err)
+		})
+	}
+}
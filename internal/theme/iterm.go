@@ -0,0 +1,156 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+func ParseITermColors(r io.Reader) (*asciinema.V2HeaderTheme, error) {
+	dec := xml.NewDecoder(r)
+
+	var root any
+	for root == nil {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("invalid itermcolors: missing dict")
+			}
+			return nil, err
+		}
+
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "dict" {
+			if root, err = decodePlistValue(dec, se); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	entries, ok := root.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid itermcolors: missing dict")
+	}
+
+	color := func(key string) (string, error) {
+		c, ok := entries[key].(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("invalid itermcolors: missing %v", key)
+		}
+
+		rgb := make([]float64, 0, 3)
+		for _, k := range []string{"Red Component", "Green Component", "Blue Component"} {
+			v, ok := c[k].(float64)
+			if !ok {
+				return "", fmt.Errorf("invalid itermcolors: missing %v of %v", k, key)
+			}
+			rgb = append(rgb, v)
+		}
+
+		return hexColor(rgb[0], rgb[1], rgb[2]), nil
+	}
+
+	fg, err := color("Foreground Color")
+	if err != nil {
+		return nil, err
+	}
+
+	bg, err := color("Background Color")
+	if err != nil {
+		return nil, err
+	}
+
+	palette := make([]string, 0, 16)
+	for i := 0; i < 16; i++ {
+		c, err := color(fmt.Sprintf("Ansi %d Color", i))
+		if err != nil {
+			return nil, err
+		}
+		palette = append(palette, c)
+	}
+
+	return newTheme(fg, bg, palette)
+}
+
+func decodePlistValue(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		key := ""
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := dec.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
+				v, err := decodePlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			case xml.EndElement:
+				return m, nil
+			}
+		}
+	case "real", "integer":
+		var s string
+		if err := dec.DecodeElement(&s, &start); err != nil {
+			return nil, err
+		}
+
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+
+		return start.Name.Local == "true", nil
+	case "string":
+		var s string
+		if err := dec.DecodeElement(&s, &start); err != nil {
+			return nil, err
+		}
+
+		return s, nil
+	default:
+		// NOTE: arrays, data and dates are not used by color presets
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+// LoadFile reads a theme from an asciicast theme JSON, iTerm2 .itermcolors,
+// Windows Terminal JSON or Xresources file, guessed by its extension and content.
+func LoadFile(name string) (*asciinema.V2HeaderTheme, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".itermcolors":
+		return ParseITermColors(bytes.NewReader(data))
+	case ".json":
+		var probe map[string]json.RawMessage
+		if err := json.Unmarshal(data, &probe); err != nil {
+			return nil, err
+		}
+
+		if _, ok := probe["palette"]; ok {
+			t := new(asciinema.V2HeaderTheme)
+			if err := json.Unmarshal(data, t); err != nil {
+				return nil, err
+			}
+
+			if err := t.Validate(); err != nil {
+				return nil, err
+			}
+
+			return t, nil
+		}
+
+		return ParseWindowsTerminal(bytes.NewReader(data), "")
+	default:
+		return ParseXresources(bytes.NewReader(data))
+	}
+}
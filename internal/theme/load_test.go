@@ -0,0 +1,130 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFile(t *testing.T) {
+	type args struct {
+		name string
+	}
+
+	type expected struct {
+		data  *asciinema.V2HeaderTheme
+		errIs error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: asciicast theme",
+			args: &args{
+				name: "testdata/tango.theme.json",
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				errIs: nil,
+			},
+		},
+		{
+			name: "happy path: itermcolors",
+			args: &args{
+				name: "testdata/tango.itermcolors",
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				errIs: nil,
+			},
+		},
+		{
+			name: "happy path: windows terminal",
+			args: &args{
+				name: "testdata/tango.json",
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				errIs: nil,
+			},
+		},
+		{
+			name: "happy path: xresources",
+			args: &args{
+				name: "testdata/tango.Xresources",
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				errIs: nil,
+			},
+		},
+		{
+			name: "edge path: not exist",
+			args: &args{
+				name: "testdata/not-exist/tango.json",
+			},
+			expected: &expected{
+				data:  nil,
+				errIs: os.ErrNotExist,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := LoadFile(tt.args.name)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, actual)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, actual)
+				assert.ErrorIs(t, err, tt.expected.errIs)
+			}
+		})
+	}
+}
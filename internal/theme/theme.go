@@ -0,0 +1,123 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+var (
+	bundled = map[string]asciinema.V2HeaderTheme{
+		"asciinema": {
+			FG:      "#cccccc",
+			BG:      "#121314",
+			Palette: "#000000:#dd3c69:#4ebf22:#ddaf3c:#26b0d7:#b954e1:#54e1b9:#d9d9d9:#4d4d4d:#dd3c69:#4ebf22:#ddaf3c:#26b0d7:#b954e1:#54e1b9:#ffffff",
+		},
+		"dracula": {
+			FG:      "#f8f8f2",
+			BG:      "#282a36",
+			Palette: "#21222c:#ff5555:#50fa7b:#f1fa8c:#bd93f9:#ff79c6:#8be9fd:#f8f8f2:#6272a4:#ff6e6e:#69ff94:#ffffa5:#d6acff:#ff92df:#a4ffff:#ffffff",
+		},
+		"gruvbox-dark": {
+			FG:      "#ebdbb2",
+			BG:      "#282828",
+			Palette: "#282828:#cc241d:#98971a:#d79921:#458588:#b16286:#689d6a:#a89984:#928374:#fb4934:#b8bb26:#fabd2f:#83a598:#d3869b:#8ec07c:#ebdbb2",
+		},
+		"monokai": {
+			FG:      "#f8f8f2",
+			BG:      "#272822",
+			Palette: "#272822:#f92672:#a6e22e:#f4bf75:#66d9ef:#ae81ff:#a1efe4:#f8f8f2:#75715e:#f92672:#a6e22e:#f4bf75:#66d9ef:#ae81ff:#a1efe4:#f9f8f5",
+		},
+		"nord": {
+			FG:      "#d8dee9",
+			BG:      "#2e3440",
+			Palette: "#3b4252:#bf616a:#a3be8c:#ebcb8b:#81a1c1:#b48ead:#88c0d0:#e5e9f0:#4c566a:#bf616a:#a3be8c:#ebcb8b:#81a1c1:#b48ead:#8fbcbb:#eceff4",
+		},
+		"solarized-dark": {
+			FG:      "#839496",
+			BG:      "#002b36",
+			Palette: "#073642:#dc322f:#859900:#b58900:#268bd2:#d33682:#2aa198:#eee8d5:#002b36:#cb4b16:#586e75:#657b83:#839496:#6c71c4:#93a1a1:#fdf6e3",
+		},
+		"solarized-light": {
+			FG:      "#657b83",
+			BG:      "#fdf6e3",
+			Palette: "#073642:#dc322f:#859900:#b58900:#268bd2:#d33682:#2aa198:#eee8d5:#002b36:#cb4b16:#586e75:#657b83:#839496:#6c71c4:#93a1a1:#fdf6e3",
+		},
+		"tango": {
+			FG:      "#d3d7cf",
+			BG:      "#2e3436",
+			Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+		},
+		"tomorrow-night": {
+			FG:      "#c5c8c6",
+			BG:      "#1d1f21",
+			Palette: "#1d1f21:#cc6666:#b5bd68:#f0c674:#81a2be:#b294bb:#8abeb7:#c5c8c6:#969896:#cc6666:#b5bd68:#f0c674:#81a2be:#b294bb:#8abeb7:#ffffff",
+		},
+	}
+)
+
+func Names() []string {
+	names := make([]string, 0, len(bundled))
+	for name := range bundled {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func Lookup(name string) (*asciinema.V2HeaderTheme, error) {
+	key := strings.ToLower(strings.NewReplacer(" ", "-", "_", "-").Replace(name))
+
+	t, ok := bundled[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme: %v", name)
+	}
+
+	return &t, nil
+}
+
+func newTheme(fg, bg string, palette []string) (*asciinema.V2HeaderTheme, error) {
+	t := &asciinema.V2HeaderTheme{
+		FG:      strings.ToLower(fg),
+		BG:      strings.ToLower(bg),
+		Palette: strings.ToLower(strings.Join(palette, ":")),
+	}
+
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func hexColor(r, g, b float64) string {
+	return fmt.Sprintf("#%02x%02x%02x", component(r), component(g), component(b))
+}
+
+func component(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+type windowsTerminalScheme struct {
+	Name         string `json:"name"`
+	Foreground   string `json:"foreground"`
+	Background   string `json:"background"`
+	Black        string `json:"black"`
+	Red          string `json:"red"`
+	Green        string `json:"green"`
+	Yellow       string `json:"yellow"`
+	Blue         string `json:"blue"`
+	Purple       string `json:"purple"`
+	Cyan         string `json:"cyan"`
+	White        string `json:"white"`
+	BrightBlack  string `json:"brightBlack"`
+	BrightRed    string `json:"brightRed"`
+	BrightGreen  string `json:"brightGreen"`
+	BrightYellow string `json:"brightYellow"`
+	BrightBlue   string `json:"brightBlue"`
+	BrightPurple string `json:"brightPurple"`
+	BrightCyan   string `json:"brightCyan"`
+	BrightWhite  string `json:"brightWhite"`
+}
+
+type windowsTerminalSettings struct {
+	Schemes []windowsTerminalScheme `json:"schemes"`
+}
+
+// ParseWindowsTerminal reads either a single color scheme or a settings.json with "schemes".
+// When the settings contain more than one scheme, name selects which one to use.
+func ParseWindowsTerminal(r io.Reader, name string) (*asciinema.V2HeaderTheme, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings windowsTerminalSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+
+	if settings.Schemes == nil {
+		var s windowsTerminalScheme
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		settings.Schemes = []windowsTerminalScheme{s}
+	}
+
+	var scheme *windowsTerminalScheme
+	switch {
+	case name != "":
+		for i := range settings.Schemes {
+			if settings.Schemes[i].Name == name {
+				scheme = &settings.Schemes[i]
+				break
+			}
+		}
+		if scheme == nil {
+			return nil, fmt.Errorf("unknown scheme: %v", name)
+		}
+	case len(settings.Schemes) == 1:
+		scheme = &settings.Schemes[0]
+	default:
+		return nil, errors.New("multiple schemes found, scheme name required")
+	}
+
+	return newTheme(scheme.Foreground, scheme.Background, []string{
+		scheme.Black, scheme.Red, scheme.Green, scheme.Yellow,
+		scheme.Blue, scheme.Purple, scheme.Cyan, scheme.White,
+		scheme.BrightBlack, scheme.BrightRed, scheme.BrightGreen, scheme.BrightYellow,
+		scheme.BrightBlue, scheme.BrightPurple, scheme.BrightCyan, scheme.BrightWhite,
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWindowsTerminal(t *testing.T) {
+	tango, _ := os.ReadFile("testdata/tango.json")
+	settings := `{"schemes": [` + string(tango) + `, ` + strings.Replace(string(tango), "Tango", "Other", 1) + `]}`
+
+	type args struct {
+		data string
+		name string
+	}
+
+	type expected struct {
+		data *asciinema.V2HeaderTheme
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: scheme",
+			args: &args{
+				data: string(tango),
+				name: "",
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "happy path: settings",
+			args: &args{
+				data: settings,
+				name: "Tango",
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "edge path: ambiguous scheme",
+			args: &args{
+				data: settings,
+				name: "",
+			},
+			expected: &expected{
+				data: nil,
+				err:  errors.New("multiple schemes found, scheme name required"),
+			},
+		},
+		{
+			name: "edge path: unknown scheme",
+			args: &args{
+				data: settings,
+				name: "Unknown",
+			},
+			expected: &expected{
+				data: nil,
+				err:  fmt.Errorf("unknown scheme: %v", "Unknown"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := ParseWindowsTerminal(strings.NewReader(tt.args.data), tt.args.name)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, actual)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, actual)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+var (
+	xresourcesDefinePattern = regexp.MustCompile(`^#define\s+(\S+)\s+(\S+)`)
+	xresourcesRGBPattern    = regexp.MustCompile(`^rgb:([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})/([0-9a-fA-F]{1,4})$`)
+)
+
+func ParseXresources(r io.Reader) (*asciinema.V2HeaderTheme, error) {
+	defines := make(map[string]string)
+	resources := make(map[string]string)
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		if m := xresourcesDefinePattern.FindStringSubmatch(line); m != nil {
+			defines[m[1]] = m[2]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		// NOTE: "URxvt*color0", "*.color0" and "color0" all mean the same resource
+		key = strings.TrimSpace(key)
+		if i := strings.LastIndexAny(key, ".*"); i >= 0 {
+			key = key[i+1:]
+		}
+
+		value = strings.TrimSpace(value)
+		if v, ok := defines[value]; ok {
+			value = v
+		}
+
+		resources[key] = value
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	color := func(key string) (string, error) {
+		v, ok := resources[key]
+		if !ok {
+			return "", fmt.Errorf("invalid xresources: missing %v", key)
+		}
+
+		return xresourcesColor(v)
+	}
+
+	fg, err := color("foreground")
+	if err != nil {
+		return nil, err
+	}
+
+	bg, err := color("background")
+	if err != nil {
+		return nil, err
+	}
+
+	n := 8
+	if _, ok := resources["color15"]; ok {
+		n = 16
+	}
+
+	palette := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		c, err := color(fmt.Sprintf("color%d", i))
+		if err != nil {
+			return nil, err
+		}
+		palette = append(palette, c)
+	}
+
+	return newTheme(fg, bg, palette)
+}
+
+func xresourcesColor(v string) (string, error) {
+	m := xresourcesRGBPattern.FindStringSubmatch(v)
+	if m == nil {
+		return v, nil
+	}
+
+	rgb := make([]float64, 0, 3)
+	for _, s := range m[1:] {
+		n, err := strconv.ParseUint(s, 16, 16)
+		if err != nil {
+			return "", err
+		}
+		rgb = append(rgb, float64(n)/float64(uint64(1)<<(4*len(s))-1))
+	}
+
+	return hexColor(rgb[0], rgb[1], rgb[2]), nil
+}
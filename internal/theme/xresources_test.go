@@ -0,0 +1,117 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package theme
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseXresources(t *testing.T) {
+	tango, _ := os.ReadFile("testdata/tango.Xresources")
+
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		data *asciinema.V2HeaderTheme
+		err  error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: 16 colors",
+			args: &args{
+				data: string(tango),
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#d3d7cf",
+					BG:      "#2e3436",
+					Palette: "#2e3436:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf:#555753:#ef2929:#8ae234:#fce94f:#729fcf:#ad7fa8:#34e2e2:#eeeeec",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "happy path: 8 colors",
+			args: &args{
+				data: `foreground: #ffffff
+background: #000000
+color0: #000000
+color1: rgb:ffff/0000/0000
+color2: rgb:0/f/0
+color3: #ffff00
+color4: #0000ff
+color5: #ff00ff
+color6: #00ffff
+color7: #ffffff
+`,
+			},
+			expected: &expected{
+				data: &asciinema.V2HeaderTheme{
+					FG:      "#ffffff",
+					BG:      "#000000",
+					Palette: "#000000:#ff0000:#00ff00:#ffff00:#0000ff:#ff00ff:#00ffff:#ffffff",
+				},
+				err: nil,
+			},
+		},
+		{
+			name: "edge path: missing color",
+			args: &args{
+				data: `foreground: #ffffff
+background: #000000
+`,
+			},
+			expected: &expected{
+				data: nil,
+				err:  errors.New("invalid xresources: missing color0"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := ParseXresources(strings.NewReader(tt.args.data))
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.data, actual)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, actual)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/vt"
+	"github.com/shopspring/decimal"
+)
+
+// ExplodeUnit tells into what Explode splits event data.
+type ExplodeUnit string
+
+const (
+	// ExplodeChar splits data into characters.
+	ExplodeChar ExplodeUnit = "char"
+	// ExplodeLine splits data into lines.
+	ExplodeLine ExplodeUnit = "line"
+)
+
+func ParseExplodeUnit(s string) (ExplodeUnit, error) {
+	switch u := ExplodeUnit(s); u {
+	case ExplodeChar, ExplodeLine:
+		return u, nil
+	default:
+		return "", fmt.Errorf("invalid unit: %v", s)
+	}
+}
+
+type coalesce struct {
+	threshold decimal.Decimal
+	pending   *asciinema.V2Event
+	last      decimal.Decimal
+}
+
+// Coalesce merges an output or input event into the one before it, if it has
+// the same code and follows the last merged event by less than threshold
+// seconds. The merged event keeps the time of the first one.
+func Coalesce(threshold float64) Transform {
+	return &coalesce{threshold: decimal.NewFromFloat(threshold)}
+}
+
+func (t *coalesce) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *coalesce) Event(e asciinema.V2Event, emit Emit) error {
+	at := e.Time.Decimal()
+	data, ok := e.Data.(string)
+
+	if p := t.pending; p != nil {
+		if ok && e.Code == p.Code && at.Sub(t.last).LessThan(t.threshold) {
+			// NOTE: data is only appended, so neither a character nor an escape sequence is split
+			if pd, ok := p.Data.(string); ok {
+				p.Data = pd + data
+				t.last = at
+
+				return nil
+			}
+		}
+
+		if err := emit(*p); err != nil {
+			return err
+		}
+		t.pending = nil
+	}
+
+	if ok && (e.Code == "o" || e.Code == "i") {
+		t.pending, t.last = &e, at
+		return nil
+	}
+
+	return emit(e)
+}
+
+func (t *coalesce) Flush(emit Emit) error {
+	if t.pending == nil {
+		return nil
+	}
+
+	return emit(*t.pending)
+}
+
+type explode struct {
+	split    func(s string) []string
+	interval decimal.Decimal
+	pending  *asciinema.V2Event
+}
+
+// Explode splits output and input events into characters or lines, as if typed
+// one after another every interval seconds. The pieces are squeezed to fit
+// before the next event, so that the events after them keep their times.
+// Escape sequences stay whole, together with the character they come before.
+func Explode(unit ExplodeUnit, interval float64) Transform {
+	t := &explode{split: splitChars, interval: decimal.NewFromFloat(interval)}
+	if unit == ExplodeLine {
+		t.split = splitLines
+	}
+
+	return t
+}
+
+func (t *explode) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *explode) Event(e asciinema.V2Event, emit Emit) error {
+	if t.pending != nil {
+		limit := e.Time.Decimal()
+		if err := t.explode(*t.pending, &limit, emit); err != nil {
+			return err
+		}
+		t.pending = nil
+	}
+
+	if _, ok := e.Data.(string); ok && (e.Code == "o" || e.Code == "i") {
+		t.pending = &e
+		return nil
+	}
+
+	return emit(e)
+}
+
+func (t *explode) Flush(emit Emit) error {
+	if t.pending == nil {
+		return nil
+	}
+
+	return t.explode(*t.pending, nil, emit)
+}
+
+func (t *explode) explode(e asciinema.V2Event, limit *decimal.Decimal, emit Emit) error {
+	data, _ := e.Data.(string)
+	pieces := t.split(data)
+	if len(pieces) <= 1 {
+		return emit(e)
+	}
+
+	at := e.Time.Decimal()
+	step := t.interval
+	if limit != nil {
+		fit := limit.Sub(at).Div(decimal.NewFromInt(int64(len(pieces))))
+		step = decimal.Max(decimal.Min(step, fit), decimal.Zero)
+	}
+
+	for i, piece := range pieces {
+		e.Time = e.Time.Move(at.Add(step.Mul(decimal.NewFromInt(int64(i)))))
+		e.Data = piece
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitChars splits s into characters, each with the control characters and
+// escape sequences before it. A zero-width character, such as a combining mark,
+// stays with the character before it, and controls at the end make a piece of
+// their own.
+func splitChars(s string) []string {
+	tokens, rest := vt.Tokens(s)
+
+	pieces := make([]string, 0, len(s))
+	var prefix strings.Builder
+	for _, tok := range tokens {
+		if tok.Kind != vt.TokenText {
+			prefix.WriteString(tok.Raw)
+			continue
+		}
+
+		for raw := tok.Raw; len(raw) > 0; {
+			r, size := utf8.DecodeRuneInString(raw)
+			if vt.RuneWidth(r) == 0 && prefix.Len() == 0 && len(pieces) > 0 {
+				pieces[len(pieces)-1] += raw[:size]
+			} else {
+				pieces = append(pieces, prefix.String()+raw[:size])
+				prefix.Reset()
+			}
+			raw = raw[size:]
+		}
+	}
+
+	// NOTE: an escape sequence cut off at the end is kept whole too
+	prefix.WriteString(rest)
+	if prefix.Len() > 0 {
+		pieces = append(pieces, prefix.String())
+	}
+
+	return pieces
+}
+
+// splitLines splits s after every line feed.
+func splitLines(s string) []string {
+	tokens, rest := vt.Tokens(s)
+
+	pieces := make([]string, 0)
+	var line strings.Builder
+	for _, tok := range tokens {
+		line.WriteString(tok.Raw)
+		if tok.Raw == "\n" {
+			pieces = append(pieces, line.String())
+			line.Reset()
+		}
+	}
+
+	line.WriteString(rest)
+	if line.Len() > 0 {
+		pieces = append(pieces, line.String())
+	}
+
+	return pieces
+}
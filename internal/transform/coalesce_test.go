@@ -0,0 +1,231 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesce(t *testing.T) {
+	type args struct {
+		threshold float64
+		events    []asciinema.V2Event
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				threshold: 0.05,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "\u001b[1"},
+					{Time: "0.11", Code: "o", Data: ";32mh"},
+					{Time: "0.15", Code: "o", Data: "é"},
+					{Time: "0.5", Code: "o", Data: "l"},
+					{Time: "0.52", Code: "i", Data: "l"},
+					{Time: "0.53", Code: "o", Data: "o"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "\u001b[1;32mhé"},
+					{Time: "0.5", Code: "o", Data: "l"},
+					{Time: "0.52", Code: "i", Data: "l"},
+					{Time: "0.53", Code: "o", Data: "o"},
+				},
+			},
+		},
+		{
+			name: "happy path: markers and resizes",
+			args: &args{
+				threshold: 1,
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "0.1", Code: "m", Data: "x"},
+					{Time: "0.2", Code: "m", Data: "y"},
+					{Time: "0.3", Code: "o", Data: "b"},
+					{Time: "0.4", Code: "r", Data: "100x30"},
+					{Time: "0.5", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "0.1", Code: "m", Data: "x"},
+					{Time: "0.2", Code: "m", Data: "y"},
+					{Time: "0.3", Code: "o", Data: "b"},
+					{Time: "0.4", Code: "r", Data: "100x30"},
+					{Time: "0.5", Code: "o", Data: "c"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			out, err := Apply(Coalesce(tt.args.threshold), h, tt.args.events)
+
+			// Assert
+			assert.Equal(t, tt.expected.events, out)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestExplode(t *testing.T) {
+	type args struct {
+		unit     string
+		interval float64
+		events   []asciinema.V2Event
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: char",
+			args: &args{
+				unit:     "char",
+				interval: 0.1,
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "i", Data: "ls\r"},
+					{Time: "2", Code: "o", Data: "\u001b[1;32mé́\u001b[0m\r\n"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "i", Data: "l"},
+					{Time: "1.1", Code: "i", Data: "s"},
+					{Time: "1.2", Code: "i", Data: "\r"},
+					{Time: "2", Code: "o", Data: "\u001b[1;32mé́"},
+					{Time: "2.1", Code: "o", Data: "\u001b[0m\r\n"},
+				},
+			},
+		},
+		{
+			name: "happy path: squeezed before the next event",
+			args: &args{
+				unit:     "char",
+				interval: 0.1,
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "abcd"},
+					{Time: "1.2", Code: "m", Data: "done"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+					{Time: "1.05", Code: "o", Data: "b"},
+					{Time: "1.1", Code: "o", Data: "c"},
+					{Time: "1.15", Code: "o", Data: "d"},
+					{Time: "1.2", Code: "m", Data: "done"},
+				},
+			},
+		},
+		{
+			name: "happy path: line",
+			args: &args{
+				unit:     "line",
+				interval: 0.5,
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a\r\n\u001b]0;b\nc\u0007d\r\ne"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a\r\n"},
+					{Time: "0.5", Code: "o", Data: "\u001b]0;b\nc\u0007d\r\n"},
+					{Time: "1", Code: "o", Data: "e"},
+				},
+			},
+		},
+		{
+			name: "happy path: incomplete escape sequence",
+			args: &args{
+				unit:     "char",
+				interval: 0.1,
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a\u001b[3"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "0.1", Code: "o", Data: "\u001b[3"},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid unit",
+			args: &args{
+				unit: "word",
+			},
+			expected: &expected{
+				err: errors.New("invalid unit: word"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			unit, err := ParseExplodeUnit(tt.args.unit)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+
+				out, err := Apply(Explode(unit, tt.args.interval), h, tt.args.events)
+				assert.Equal(t, tt.expected.events, out)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+)
+
+type delta struct {
+	mark  *asciinema.V2HeaderDelta
+	prev  decimal.Decimal
+	times map[asciinema.Time]bool
+}
+
+// Delta turns event times into the time since the previous event, as in a Δ file,
+// and marks the header with the deltascii key holding the original duration and
+// the times Accumulate could not write back as they were, such as 1.0.
+func Delta() Transform {
+	return new(delta)
+}
+
+func (t *delta) Header(h *asciinema.V2Header) error {
+	t.mark = &asciinema.V2HeaderDelta{Version: asciinema.DeltaVersion}
+	t.times = make(map[asciinema.Time]bool)
+	h.Delta = t.mark
+
+	return nil
+}
+
+func (t *delta) Event(e asciinema.V2Event, emit Emit) error {
+	at := e.Time.Decimal()
+	if e.Time != "" && e.Time != asciinema.NewTime(at) && !t.times[e.Time] {
+		t.times[e.Time] = true
+		t.mark.Times = append(t.mark.Times, json.Number(e.Time))
+	}
+	e.Time = e.Time.Move(at.Sub(t.prev))
+	t.prev = at
+
+	return emit(e)
+}
+
+func (t *delta) Flush(emit Emit) error {
+	// NOTE: the header is written after the last event, when the duration is known
+	if t.mark != nil {
+		t.mark.Duration = t.prev.InexactFloat64()
+	}
+
+	return nil
+}
+
+type accumulate struct {
+	stderr io.Writer
+	mark   *asciinema.V2HeaderDelta
+	sum    decimal.Decimal
+	times  map[string]asciinema.Time
+}
+
+// Accumulate turns times since the previous event back into event times, the
+// inverse of Delta. It strips the deltascii header key, writes times recorded
+// there as they were, and reports to stderr, if not nil, how much the duration
+// changed from the one recorded there.
+func Accumulate(stderr io.Writer) Transform {
+	return &accumulate{stderr: stderr}
+}
+
+func (t *accumulate) Header(h *asciinema.V2Header) error {
+	if h.Delta != nil {
+		if err := h.Delta.Validate(); err != nil {
+			return err
+		}
+	}
+
+	t.mark, h.Delta = h.Delta, nil
+
+	// NOTE: times are looked up by value, so that edits moving events do not matter
+	t.times = make(map[string]asciinema.Time)
+	if t.mark != nil {
+		for _, n := range t.mark.Times {
+			tm := asciinema.Time(n)
+			t.times[tm.Decimal().String()] = tm
+		}
+	}
+
+	return nil
+}
+
+func (t *accumulate) Event(e asciinema.V2Event, emit Emit) error {
+	t.sum = t.sum.Add(e.Time.Decimal())
+	if tm, ok := t.times[t.sum.String()]; ok {
+		e.Time = tm
+	} else {
+		e.Time = e.Time.Move(t.sum)
+	}
+
+	return emit(e)
+}
+
+func (t *accumulate) Flush(emit Emit) error {
+	if t.stderr == nil || t.mark == nil {
+		return nil
+	}
+
+	orig := decimal.NewFromFloat(t.mark.Duration)
+	if diff := t.sum.Sub(orig); diff.Abs().GreaterThan(asciinema.DurationTolerance) {
+		fmt.Fprintf(t.stderr, "duration changed from %vs to %vs (%+vs)\n", orig, t.sum, diff.InexactFloat64())
+	}
+
+	return nil
+}
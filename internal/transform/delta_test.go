@@ -0,0 +1,237 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelta(t *testing.T) {
+	type args struct {
+		t      func(stderr io.Writer) Transform
+		mark   *asciinema.V2HeaderDelta
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		mark   *asciinema.V2HeaderDelta
+		events []asciinema.V2Event
+		stderr string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: delta",
+			args: &args{
+				t: func(stderr io.Writer) Transform { return Delta() },
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "i", Data: "b"},
+					{Time: "0.3", Code: "m", Data: ""},
+					{Time: "1.2", Code: "r", Data: "100x30"},
+				},
+			},
+			expected: &expected{
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 1.2},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "i", Data: "b"},
+					{Time: "0", Code: "m", Data: ""},
+					{Time: "0.9", Code: "r", Data: "100x30"},
+				},
+			},
+		},
+		{
+			name: "happy path: accumulate",
+			args: &args{
+				t:    Accumulate,
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 1.2},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "i", Data: "b"},
+					{Time: "0", Code: "m", Data: ""},
+					{Time: "0.9", Code: "r", Data: "100x30"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "i", Data: "b"},
+					{Time: "0.3", Code: "m", Data: ""},
+					{Time: "1.2", Code: "r", Data: "100x30"},
+				},
+			},
+		},
+		{
+			name: "happy path: round trip",
+			args: &args{
+				t: func(stderr io.Writer) Transform { return Chain(Delta(), Accumulate(stderr)) },
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "o", Data: "b"},
+					{Time: "0.6", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "o", Data: "b"},
+					{Time: "0.6", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "happy path: delta with trailing zeros",
+			args: &args{
+				t: func(stderr io.Writer) Transform { return Delta() },
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "a"},
+					{Time: "2.0", Code: "o", Data: "b"},
+					{Time: "2.50", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 2.5, Times: []json.Number{"2.0", "2.50"}},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "a"},
+					{Time: "1.5", Code: "o", Data: "b"},
+					{Time: "0.5", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "happy path: accumulate with recorded times",
+			args: &args{
+				t:    Accumulate,
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 2.5, Times: []json.Number{"2.0", "2.50"}},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "a"},
+					{Time: "1.5", Code: "o", Data: "b"},
+					{Time: "0.25", Code: "o", Data: "x"},
+					{Time: "0.25", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "a"},
+					{Time: "2.0", Code: "o", Data: "b"},
+					{Time: "2.25", Code: "o", Data: "x"},
+					{Time: "2.50", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid recorded time",
+			args: &args{
+				t:    Accumulate,
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 2, Times: []json.Number{"x"}},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+				},
+			},
+			expected: &expected{
+				err: errors.New("invalid Δ-asciicast time: x"),
+			},
+		},
+		{
+			name: "happy path: accumulate with changed duration",
+			args: &args{
+				t:    Accumulate,
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 2},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "i", Data: "b"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "i", Data: "b"},
+				},
+				stderr: "duration changed from 2s to 0.3s (-1.7s)\n",
+			},
+		},
+		{
+			name: "happy path: accumulate with duration within tolerance",
+			args: &args{
+				t:    Accumulate,
+				mark: &asciinema.V2HeaderDelta{Version: 1, Duration: 0.3005},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "i", Data: "b"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.3", Code: "i", Data: "b"},
+				},
+			},
+		},
+		{
+			name: "edge path: unsupported version",
+			args: &args{
+				t:    Accumulate,
+				mark: &asciinema.V2HeaderDelta{Version: 2, Duration: 2},
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+				},
+			},
+			expected: &expected{
+				err: errors.New("unsupported Δ-asciicast version: 2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Delta: tt.args.mark}
+			stderr := new(bytes.Buffer)
+
+			// Act
+			events, err := Apply(tt.args.t(stderr), h, tt.args.events)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.mark, h.Delta)
+				assert.Equal(t, tt.expected.events, events)
+				assert.Equal(t, tt.expected.stderr, stderr.String())
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+// DurationMode tells what becomes of the header duration after timing changes.
+type DurationMode string
+
+const (
+	// DurationUpdate recomputes the duration if the header has one.
+	DurationUpdate DurationMode = "update"
+	// DurationSet always writes the recomputed duration.
+	DurationSet DurationMode = "set"
+	// DurationKeep leaves the duration as is.
+	DurationKeep DurationMode = "keep"
+	// DurationDrop removes the duration.
+	DurationDrop DurationMode = "drop"
+)
+
+func ParseDurationMode(s string) (DurationMode, error) {
+	switch m := DurationMode(s); m {
+	case DurationUpdate, DurationSet, DurationKeep, DurationDrop:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid duration mode: %v", s)
+	}
+}
+
+type duration struct {
+	mode   DurationMode
+	header *asciinema.V2Header
+	last   asciinema.Time
+}
+
+// Duration sets the header duration to the last event time, as the mode tells.
+// A Δ-asciicast keeps the duration of the asciicast it was made from.
+func Duration(mode DurationMode) Transform {
+	return &duration{mode: mode}
+}
+
+func (t *duration) Header(h *asciinema.V2Header) error {
+	t.header = h
+
+	return nil
+}
+
+func (t *duration) Event(e asciinema.V2Event, emit Emit) error {
+	t.last = e.Time
+
+	return emit(e)
+}
+
+func (t *duration) Flush(emit Emit) error {
+	h := t.header
+	if h == nil || h.Delta != nil {
+		return nil
+	}
+
+	switch t.mode {
+	case DurationUpdate:
+		if h.Duration != 0 {
+			h.Duration = t.last.Float64()
+		}
+	case DurationSet:
+		h.Duration = t.last.Float64()
+	case DurationDrop:
+		h.Duration = 0
+	}
+
+	return nil
+}
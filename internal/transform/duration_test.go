@@ -0,0 +1,143 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "0.5", Code: "o", Data: "a"},
+		{Time: "1.5", Code: "o", Data: "b"},
+	}
+
+	type args struct {
+		mode   string
+		header *asciinema.V2Header
+	}
+
+	type expected struct {
+		duration float64
+		err      error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: update",
+			args: &args{
+				mode:   "update",
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Duration: 9},
+			},
+			expected: &expected{
+				duration: 1.5,
+			},
+		},
+		{
+			name: "happy path: update without duration",
+			args: &args{
+				mode:   "update",
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+			},
+			expected: &expected{
+				duration: 0,
+			},
+		},
+		{
+			name: "happy path: set",
+			args: &args{
+				mode:   "set",
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+			},
+			expected: &expected{
+				duration: 1.5,
+			},
+		},
+		{
+			name: "happy path: keep",
+			args: &args{
+				mode:   "keep",
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Duration: 9},
+			},
+			expected: &expected{
+				duration: 9,
+			},
+		},
+		{
+			name: "happy path: drop",
+			args: &args{
+				mode:   "drop",
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Duration: 9},
+			},
+			expected: &expected{
+				duration: 0,
+			},
+		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				mode:   "set",
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Duration: 9, Delta: &asciinema.V2HeaderDelta{Version: 1, Duration: 9}},
+			},
+			expected: &expected{
+				duration: 9,
+			},
+		},
+		{
+			name: "edge path: invalid mode",
+			args: &args{
+				mode: "recompute",
+			},
+			expected: &expected{
+				err: errors.New("invalid duration mode: recompute"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mode, err := ParseDurationMode(tt.args.mode)
+
+			// Act
+			if err == nil {
+				_, err = Apply(Duration(mode), tt.args.header, events)
+			}
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.duration, tt.args.header.Duration)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
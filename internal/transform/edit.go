@@ -0,0 +1,192 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+)
+
+type idleLimit struct {
+	limit decimal.Decimal
+	prev  decimal.Decimal
+	shift decimal.Decimal
+}
+
+// IdleLimit shortens every pause between events to at most limit seconds.
+func IdleLimit(limit float64) Transform {
+	return &idleLimit{limit: decimal.NewFromFloat(limit)}
+}
+
+func (t *idleLimit) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *idleLimit) Event(e asciinema.V2Event, emit Emit) error {
+	at := e.Time.Decimal()
+	if gap := at.Sub(t.prev); gap.GreaterThan(t.limit) {
+		t.shift = t.shift.Add(gap.Sub(t.limit))
+	}
+	t.prev = at
+
+	e.Time = e.Time.Move(at.Sub(t.shift))
+
+	return emit(e)
+}
+
+func (t *idleLimit) Flush(emit Emit) error {
+	return nil
+}
+
+// Range selects a span of an asciicast. Each end is given either by a time in
+// seconds or by a marker label, and an end left unset is open.
+type Range struct {
+	Start       *float64
+	End         *float64
+	StartMarker string
+	EndMarker   string
+}
+
+type speed struct {
+	factor  decimal.Decimal
+	rng     *Range
+	start   *decimal.Decimal
+	end     *decimal.Decimal
+	prevIn  decimal.Decimal
+	prevOut decimal.Decimal
+}
+
+// Speed plays the pauses within rng factor times faster, and shifts the
+// events after it accordingly.
+func Speed(factor float64, rng *Range) Transform {
+	t := &speed{factor: decimal.NewFromFloat(factor), rng: rng}
+
+	if rng.Start != nil {
+		start := decimal.NewFromFloat(*rng.Start)
+		t.start = &start
+	} else if rng.StartMarker == "" {
+		start := decimal.Zero
+		t.start = &start
+	}
+
+	if rng.End != nil {
+		end := decimal.NewFromFloat(*rng.End)
+		t.end = &end
+	}
+
+	return t
+}
+
+func (t *speed) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *speed) Event(e asciinema.V2Event, emit Emit) error {
+	at := e.Time.Decimal()
+
+	// NOTE: the range opens at the first start marker and closes at the first end marker after it
+	if label, ok := e.Data.(string); ok && e.Code == "m" {
+		if t.start == nil && label == t.rng.StartMarker {
+			t.start = &at
+		} else if t.start != nil && t.end == nil && label == t.rng.EndMarker {
+			t.end = &at
+		}
+	}
+
+	gap := at.Sub(t.prevIn)
+	fast := decimal.Zero
+	if t.start != nil {
+		from := decimal.Max(t.prevIn, *t.start)
+		to := at
+		if t.end != nil {
+			to = decimal.Min(to, *t.end)
+		}
+		if to.GreaterThan(from) {
+			fast = to.Sub(from)
+		}
+	}
+
+	out := t.prevOut.Add(gap.Sub(fast)).Add(fast.Div(t.factor))
+	t.prevIn, t.prevOut = at, out
+
+	e.Time = e.Time.Move(out)
+
+	return emit(e)
+}
+
+func (t *speed) Flush(emit Emit) error {
+	return nil
+}
+
+type redact struct {
+	pattern     *regexp.Regexp
+	replacement string
+	codes       []string
+}
+
+// Redact replaces every match of pattern in the data of events with one of
+// codes. Matches are searched within each event.
+func Redact(pattern *regexp.Regexp, replacement string, codes []string) Transform {
+	return &redact{pattern: pattern, replacement: replacement, codes: codes}
+}
+
+func (t *redact) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *redact) Event(e asciinema.V2Event, emit Emit) error {
+	if data, ok := e.Data.(string); ok && slices.Contains(t.codes, e.Code) {
+		e.Data = t.pattern.ReplaceAllString(data, t.replacement)
+	}
+
+	return emit(e)
+}
+
+func (t *redact) Flush(emit Emit) error {
+	return nil
+}
+
+type setTheme struct {
+	theme *asciinema.V2HeaderTheme
+}
+
+// SetTheme sets the header theme.
+func SetTheme(theme *asciinema.V2HeaderTheme) Transform {
+	return &setTheme{theme: theme}
+}
+
+func (t *setTheme) Header(h *asciinema.V2Header) error {
+	theme := *t.theme
+	h.Theme = &theme
+
+	return nil
+}
+
+func (t *setTheme) Event(e asciinema.V2Event, emit Emit) error {
+	return emit(e)
+}
+
+func (t *setTheme) Flush(emit Emit) error {
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdits(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "0", Code: "o", Data: "$ "},
+		{Time: "1", Code: "i", Data: "make install token=s3cret\r"},
+		{Time: "1.5", Code: "m", Data: "install"},
+		{Time: "5.5", Code: "o", Data: "done token=s3cret\r\n"},
+		{Time: "6.5", Code: "m", Data: "installed"},
+		{Time: "7", Code: "o", Data: "$ "},
+	}
+
+	float := func(f float64) *float64 { return &f }
+
+	type args struct {
+		t Transform
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		times  []float64
+		data   []any
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: idle limit",
+			args: &args{
+				t: IdleLimit(0.5),
+			},
+			expected: &expected{
+				times: []float64{0, 0.5, 1, 1.5, 2, 2.5},
+			},
+		},
+		{
+			name: "happy path: speed between markers",
+			args: &args{
+				t: Speed(4, &Range{StartMarker: "install", EndMarker: "installed"}),
+			},
+			expected: &expected{
+				times: []float64{0, 1, 1.5, 2.5, 2.75, 3.25},
+			},
+		},
+		{
+			name: "happy path: speed between times",
+			args: &args{
+				t: Speed(2, &Range{Start: float(0.5), End: float(5)}),
+			},
+			expected: &expected{
+				times: []float64{0, 0.75, 1, 3.25, 4.25, 4.75},
+			},
+		},
+		{
+			name: "happy path: speed until marker",
+			args: &args{
+				t: Speed(0.5, &Range{EndMarker: "install"}),
+			},
+			expected: &expected{
+				times: []float64{0, 2, 3, 7, 8, 8.5},
+			},
+		},
+		{
+			name: "happy path: redact",
+			args: &args{
+				t: Redact(regexp.MustCompile(`token=\S+`), "token=***", []string{"o", "i"}),
+			},
+			expected: &expected{
+				data: []any{"$ ", "make install token=***\r", "install", "done token=***\r\n", "installed", "$ "},
+			},
+		},
+		{
+			name: "happy path: redact codes",
+			args: &args{
+				t: Redact(regexp.MustCompile(`s3cret`), "", []string{"i"}),
+			},
+			expected: &expected{
+				data: []any{"$ ", "make install token=\r", "install", "done token=s3cret\r\n", "installed", "$ "},
+			},
+		},
+		{
+			name: "happy path: theme",
+			args: &args{
+				t: SetTheme(&asciinema.V2HeaderTheme{FG: "#ffffff", BG: "#000000"}),
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Theme: &asciinema.V2HeaderTheme{FG: "#ffffff", BG: "#000000"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			out, err := Apply(tt.args.t, h, events)
+
+			// Assert
+			assert.NoError(t, err)
+			if tt.expected.header != nil {
+				assert.Equal(t, tt.expected.header, h)
+			}
+
+			times := make([]float64, 0, len(out))
+			data := make([]any, 0, len(out))
+			for _, e := range out {
+				times = append(times, e.Time.Float64())
+				data = append(data, e.Data)
+			}
+			if tt.expected.times != nil {
+				assert.Equal(t, tt.expected.times, times)
+			}
+			if tt.expected.data != nil {
+				assert.Equal(t, tt.expected.data, data)
+			}
+		})
+	}
+}
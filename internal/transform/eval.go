@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/script"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// NOTE: event codes whose data is text, as asciicast v2 defines them
+	textCodes = []string{"o", "i", "m", "r"}
+)
+
+type eval struct {
+	program *script.Program
+	vars    map[string]any
+	n       int
+	prev    decimal.Decimal
+	out     decimal.Decimal
+}
+
+// Eval runs a script over every event, with time, delta, code and data set to
+// the event and acc kept across events, as well as any other variable the
+// script assigns. An assigned time places the event there, otherwise the event
+// follows the previous output event by delta, so that a changed or dropped
+// event shifts the events after it.
+func Eval(program *script.Program) Transform {
+	return &eval{
+		program: program,
+		vars:    map[string]any{"acc": float64(0)},
+	}
+}
+
+func (t *eval) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *eval) Event(e asciinema.V2Event, emit Emit) error {
+	t.n++
+
+	at := e.Time.Decimal()
+	delta := at.Sub(t.prev)
+	t.prev = at
+
+	t.vars["time"] = at.InexactFloat64()
+	t.vars["delta"] = delta.InexactFloat64()
+	t.vars["code"] = e.Code
+	t.vars["data"] = e.Data
+
+	dropped, err := t.program.Run(t.vars)
+	if err != nil {
+		return fmt.Errorf("event %d: %w", t.n, err)
+	}
+	if dropped {
+		return nil
+	}
+
+	time, ok := t.vars["time"].(float64)
+	if !ok {
+		return fmt.Errorf("event %d: time must be number", t.n)
+	}
+	d, ok := t.vars["delta"].(float64)
+	if !ok {
+		return fmt.Errorf("event %d: delta must be number", t.n)
+	}
+	if e.Code, ok = t.vars["code"].(string); !ok {
+		return fmt.Errorf("event %d: code must be string", t.n)
+	}
+	e.Data = t.vars["data"]
+
+	// NOTE: only events of unknown codes may carry other data than text
+	if _, ok := e.Data.(string); !ok && slices.Contains(textCodes, e.Code) {
+		return fmt.Errorf("event %d: data must be string", t.n)
+	}
+
+	// NOTE: numbers left unchanged by the script keep their exact decimals
+	if d != delta.InexactFloat64() {
+		delta = decimal.NewFromFloat(d)
+	}
+	out := t.out.Add(delta)
+	if time != at.InexactFloat64() {
+		out = decimal.NewFromFloat(time)
+	}
+	if out.LessThan(t.out) {
+		return fmt.Errorf("event %d: time goes backwards: %v", t.n, out)
+	}
+	t.out = out
+
+	e.Time = e.Time.Move(out)
+
+	return emit(e)
+}
+
+func (t *eval) Flush(emit Emit) error {
+	return nil
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/script"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "0", Code: "o", Data: "$ "},
+		{Time: "1", Code: "i", Data: "ls\r"},
+		{Time: "5", Code: "o", Data: "a.txt\r\n"},
+		{Time: "6", Code: "o", Data: "$ "},
+	}
+
+	type args struct {
+		script string
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: shorten delta",
+			args: &args{
+				script: `if code == "o" && delta > 3 { delta = 1 }`,
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "1", Code: "i", Data: "ls\r"},
+					{Time: "2", Code: "o", Data: "a.txt\r\n"},
+					{Time: "3", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "happy path: set time",
+			args: &args{
+				script: `if data == "a.txt\r\n" { time = 2 }`,
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "1", Code: "i", Data: "ls\r"},
+					{Time: "2", Code: "o", Data: "a.txt\r\n"},
+					{Time: "3", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "happy path: drop",
+			args: &args{
+				script: `if code == "i" { drop }`,
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "4", Code: "o", Data: "a.txt\r\n"},
+					{Time: "5", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "happy path: accumulator",
+			args: &args{
+				script: `acc += 1
+data = string(acc) + ": " + data`,
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "1: $ "},
+					{Time: "1", Code: "i", Data: "2: ls\r"},
+					{Time: "5", Code: "o", Data: "3: a.txt\r\n"},
+					{Time: "6", Code: "o", Data: "4: $ "},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid code",
+			args: &args{
+				script: `code = 1`,
+			},
+			expected: &expected{
+				err: errors.New("event 1: code must be string"),
+			},
+		},
+		{
+			name: "edge path: invalid data",
+			args: &args{
+				script: `if code == "i" { data = 1 }`,
+			},
+			expected: &expected{
+				err: errors.New("event 2: data must be string"),
+			},
+		},
+		{
+			name: "edge path: time goes backwards",
+			args: &args{
+				script: `if code == "i" { delta = -2 }`,
+			},
+			expected: &expected{
+				err: errors.New("event 2: time goes backwards: -2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			p, err := script.Parse(tt.args.script)
+			assert.NoError(t, err)
+
+			// Act
+			out, err := Apply(Eval(p), h, events)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.events, out)
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+// Funcs is a transform made of functions, where a nil function passes the
+// header or events through as they are.
+type Funcs struct {
+	HeaderFunc func(h *asciinema.V2Header) error
+	EventFunc  func(e asciinema.V2Event, emit Emit) error
+	FlushFunc  func(emit Emit) error
+}
+
+func (f *Funcs) Header(h *asciinema.V2Header) error {
+	if f.HeaderFunc == nil {
+		return nil
+	}
+
+	return f.HeaderFunc(h)
+}
+
+func (f *Funcs) Event(e asciinema.V2Event, emit Emit) error {
+	if f.EventFunc == nil {
+		return emit(e)
+	}
+
+	return f.EventFunc(e, emit)
+}
+
+func (f *Funcs) Flush(emit Emit) error {
+	if f.FlushFunc == nil {
+		return nil
+	}
+
+	return f.FlushFunc(emit)
+}
+
+// Filter keeps only the events for which keep returns true.
+func Filter(keep func(e asciinema.V2Event) bool) Transform {
+	return &Funcs{
+		EventFunc: func(e asciinema.V2Event, emit Emit) error {
+			if !keep(e) {
+				return nil
+			}
+
+			return emit(e)
+		},
+	}
+}
+
+// Map rewrites every event one by one, such as to retime or recode it.
+func Map(fn func(e asciinema.V2Event) (asciinema.V2Event, error)) Transform {
+	return &Funcs{
+		EventFunc: func(e asciinema.V2Event, emit Emit) error {
+			e, err := fn(e)
+			if err != nil {
+				return err
+			}
+
+			return emit(e)
+		},
+	}
+}
+
+// Buffer holds back all events and hands them to fn at the end, for edits that
+// need to look ahead. It gives up streaming, so prefer holding back only the
+// events needed. Since the header is written after Flush, fn may change it too,
+// such as its duration.
+func Buffer(fn func(h *asciinema.V2Header, events []asciinema.V2Event) ([]asciinema.V2Event, error)) Transform {
+	var h *asciinema.V2Header
+	events := make([]asciinema.V2Event, 0)
+
+	return &Funcs{
+		HeaderFunc: func(header *asciinema.V2Header) error {
+			h = header
+			return nil
+		},
+		EventFunc: func(e asciinema.V2Event, emit Emit) error {
+			events = append(events, e)
+			return nil
+		},
+		FlushFunc: func(emit Emit) error {
+			out, err := fn(h, events)
+			if err != nil {
+				return err
+			}
+
+			for _, e := range out {
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
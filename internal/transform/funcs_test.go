@@ -0,0 +1,154 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncs(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "1", Code: "o", Data: "a"},
+		{Time: "2", Code: "i", Data: "b"},
+		{Time: "3", Code: "o", Data: "c"},
+	}
+
+	type args struct {
+		t Transform
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: funcs pass through",
+			args: &args{
+				t: new(Funcs),
+			},
+			expected: &expected{
+				events: events,
+			},
+		},
+		{
+			name: "happy path: filter",
+			args: &args{
+				t: Filter(func(e asciinema.V2Event) bool { return e.Code == "o" }),
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+					{Time: "3", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "happy path: map",
+			args: &args{
+				t: Map(func(e asciinema.V2Event) (asciinema.V2Event, error) {
+					e.Time = asciinema.NewTime(e.Time.Decimal().Mul(decimal.NewFromInt(2)))
+					return e, nil
+				}),
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "2", Code: "o", Data: "a"},
+					{Time: "4", Code: "i", Data: "b"},
+					{Time: "6", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "happy path: buffer",
+			args: &args{
+				t: Buffer(func(h *asciinema.V2Header, events []asciinema.V2Event) ([]asciinema.V2Event, error) {
+					// NOTE: retime events backwards from the last one, which needs the whole cast
+					out := slices.Clone(events)
+					last := events[len(events)-1].Time.Decimal()
+					for i := range out {
+						out[i].Time = asciinema.NewTime(last.Sub(events[len(events)-1-i].Time.Decimal()))
+					}
+					return out, nil
+				}),
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "1", Code: "i", Data: "b"},
+					{Time: "2", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "edge path: map failed",
+			args: &args{
+				t: Map(func(e asciinema.V2Event) (asciinema.V2Event, error) {
+					return e, errors.New("failed")
+				}),
+			},
+			expected: &expected{
+				err: errors.New("failed"),
+			},
+		},
+		{
+			name: "edge path: buffer failed",
+			args: &args{
+				t: Buffer(func(h *asciinema.V2Header, events []asciinema.V2Event) ([]asciinema.V2Event, error) {
+					return nil, errors.New("failed")
+				}),
+			},
+			expected: &expected{
+				err: errors.New("failed"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			out, err := Apply(tt.args.t, h, events)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.events, out)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
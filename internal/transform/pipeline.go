@@ -0,0 +1,368 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/script"
+	"github.com/Aton-Kish/deltascii/internal/theme"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	builders = map[string]func(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error){
+		"delta":      buildDelta,
+		"accumulate": buildAccumulate,
+		"idle-limit": buildIdleLimit,
+		"redact":     buildRedact,
+		"speed":      buildSpeed,
+		"theme":      buildTheme,
+		"plugin":     buildPlugin,
+		"eval":       buildEval,
+		"coalesce":   buildCoalesce,
+		"explode":    buildExplode,
+	}
+)
+
+// Pipeline is an ordered list of transforms read from a pipeline file such as
+//
+//	transforms:
+//	  - type: idle-limit
+//	    max: 2s
+//	  - type: speed
+//	    factor: 4
+//	    from: install
+//	    to: installed
+type Pipeline struct {
+	steps []func(stderr io.Writer) Transform
+}
+
+type pipelineFile struct {
+	Transforms []yaml.Node `yaml:"transforms"`
+}
+
+// Load reads a pipeline file. Relative file names in parameters are resolved
+// against the directory of the pipeline file.
+func Load(name string) (*Pipeline, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f, filepath.Dir(name))
+}
+
+// Parse reads a pipeline, resolving relative file names against dir.
+func Parse(r io.Reader, dir string) (*Pipeline, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var pf pipelineFile
+	if err := dec.Decode(&pf); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(pf.Transforms) == 0 {
+		return nil, errors.New("no transforms in pipeline")
+	}
+
+	p := &Pipeline{steps: make([]func(stderr io.Writer) Transform, 0, len(pf.Transforms))}
+	for i := range pf.Transforms {
+		node := &pf.Transforms[i]
+
+		var step struct {
+			Type string `yaml:"type"`
+		}
+		if err := node.Decode(&step); err != nil {
+			return nil, err
+		}
+
+		build, ok := builders[step.Type]
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid transform type: %v", node.Line, step.Type)
+		}
+
+		fn, err := build(node, dir)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+
+		p.steps = append(p.steps, fn)
+	}
+
+	return p, nil
+}
+
+// Transform returns the pipeline as a new chain, since transforms keep the
+// state of the asciicast they run over. Plugins write diagnostics to stderr.
+func (p *Pipeline) Transform(stderr io.Writer) Transform {
+	ts := make([]Transform, 0, len(p.steps))
+	for _, fn := range p.steps {
+		ts = append(ts, fn(stderr))
+	}
+
+	return Chain(ts...)
+}
+
+// Seconds is a time in seconds, written as a number or a duration such as "500ms".
+type Seconds float64
+
+func (s *Seconds) UnmarshalYAML(node *yaml.Node) error {
+	var f float64
+	if err := node.Decode(&f); err == nil {
+		*s = Seconds(f)
+		return nil
+	}
+
+	d, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %v", node.Value)
+	}
+	*s = Seconds(d.Seconds())
+
+	return nil
+}
+
+// decodeParams decodes the parameters of a transform into v, rejecting keys
+// that v does not have so that typos do not pass silently.
+func decodeParams(node *yaml.Node, v any) error {
+	rt := reflect.TypeOf(v).Elem()
+	keys := make([]string, 0, rt.NumField()+1)
+	keys = append(keys, "type")
+	for i := 0; i < rt.NumField(); i++ {
+		keys = append(keys, strings.Split(rt.Field(i).Tag.Get("yaml"), ",")[0])
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if key := node.Content[i].Value; !slices.Contains(keys, key) {
+			return fmt.Errorf("invalid parameter: %v", key)
+		}
+	}
+
+	return node.Decode(v)
+}
+
+func buildDelta(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	if err := decodeParams(node, &struct{}{}); err != nil {
+		return nil, err
+	}
+
+	return func(stderr io.Writer) Transform { return Delta() }, nil
+}
+
+func buildAccumulate(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	if err := decodeParams(node, &struct{}{}); err != nil {
+		return nil, err
+	}
+
+	return func(stderr io.Writer) Transform { return Accumulate(stderr) }, nil
+}
+
+func buildIdleLimit(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	var p struct {
+		Max Seconds `yaml:"max"`
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Max <= 0 {
+		return nil, fmt.Errorf("invalid max: %v", p.Max)
+	}
+
+	return func(stderr io.Writer) Transform { return IdleLimit(float64(p.Max)) }, nil
+}
+
+func buildRedact(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	p := struct {
+		Pattern     string   `yaml:"pattern"`
+		Replacement string   `yaml:"replacement"`
+		Codes       []string `yaml:"codes"`
+	}{
+		Replacement: "***",
+		Codes:       []string{"o", "i"},
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	pattern, err := regexp.Compile(p.Pattern)
+	if err != nil || p.Pattern == "" {
+		return nil, fmt.Errorf("invalid pattern: %v", p.Pattern)
+	}
+
+	return func(stderr io.Writer) Transform { return Redact(pattern, p.Replacement, p.Codes) }, nil
+}
+
+func buildSpeed(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	var p struct {
+		Factor float64  `yaml:"factor"`
+		Start  *Seconds `yaml:"start"`
+		End    *Seconds `yaml:"end"`
+		From   string   `yaml:"from"`
+		To     string   `yaml:"to"`
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Factor <= 0 {
+		return nil, fmt.Errorf("invalid factor: %v", p.Factor)
+	}
+
+	if p.Start != nil && p.From != "" || p.End != nil && p.To != "" {
+		return nil, errors.New("either a time (start, end) or a marker (from, to) is allowed for each end")
+	}
+
+	rng := &Range{StartMarker: p.From, EndMarker: p.To}
+	if p.Start != nil {
+		start := float64(*p.Start)
+		rng.Start = &start
+	}
+	if p.End != nil {
+		end := float64(*p.End)
+		rng.End = &end
+	}
+
+	return func(stderr io.Writer) Transform { return Speed(p.Factor, rng) }, nil
+}
+
+func buildTheme(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	var p struct {
+		Name string `yaml:"name"`
+		File string `yaml:"file"`
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	var t *asciinema.V2HeaderTheme
+	var err error
+	switch {
+	case p.Name != "" && p.File == "":
+		t, err = theme.Lookup(p.Name)
+	case p.Name == "" && p.File != "":
+		name := p.File
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		t, err = theme.LoadFile(name)
+	default:
+		err = errors.New("either theme name or file is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return func(stderr io.Writer) Transform { return SetTheme(t) }, nil
+}
+
+func buildPlugin(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	var p struct {
+		Command string   `yaml:"command"`
+		Args    []string `yaml:"args"`
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Command == "" {
+		return nil, errors.New("plugin command is required")
+	}
+
+	// NOTE: a command with a path is relative to the pipeline file, a bare name is looked up in PATH
+	name := p.Command
+	if strings.ContainsRune(name, filepath.Separator) && !filepath.IsAbs(name) {
+		name = filepath.Join(dir, name)
+	}
+
+	return func(stderr io.Writer) Transform { return Plugin(name, p.Args, stderr) }, nil
+}
+
+func buildEval(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	var p struct {
+		Script string `yaml:"script"`
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(p.Script) == "" {
+		return nil, errors.New("eval script is required")
+	}
+
+	program, err := script.Parse(p.Script)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script: %w", err)
+	}
+
+	return func(stderr io.Writer) Transform { return Eval(program) }, nil
+}
+
+func buildCoalesce(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	var p struct {
+		Threshold Seconds `yaml:"threshold"`
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Threshold <= 0 {
+		return nil, fmt.Errorf("invalid threshold: %v", p.Threshold)
+	}
+
+	return func(stderr io.Writer) Transform { return Coalesce(float64(p.Threshold)) }, nil
+}
+
+func buildExplode(node *yaml.Node, dir string) (func(stderr io.Writer) Transform, error) {
+	p := struct {
+		Unit     string  `yaml:"unit"`
+		Interval Seconds `yaml:"interval"`
+	}{
+		Unit: string(ExplodeChar),
+	}
+	if err := decodeParams(node, &p); err != nil {
+		return nil, err
+	}
+
+	unit, err := ParseExplodeUnit(p.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Interval <= 0 {
+		return nil, fmt.Errorf("invalid interval: %v", p.Interval)
+	}
+
+	return func(stderr io.Writer) Transform { return Explode(unit, float64(p.Interval)) }, nil
+}
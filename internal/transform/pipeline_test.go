@@ -0,0 +1,284 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "0", Code: "o", Data: "$ "},
+		{Time: "3", Code: "i", Data: "echo s3cret\r"},
+		{Time: "4", Code: "m", Data: "install"},
+		{Time: "8", Code: "o", Data: "s3cret\r\n"},
+	}
+
+	type args struct {
+		pipeline string
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				pipeline: `transforms:
+  - type: idle-limit
+    max: 2s
+  - type: redact
+    pattern: s3cret
+  - type: speed
+    factor: 2
+    from: install
+  - type: theme
+    file: theme.json
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Theme: &asciinema.V2HeaderTheme{FG: "#ffffff", BG: "#000000", Palette: "#000000:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf"}},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "2", Code: "i", Data: "echo ***\r"},
+					{Time: "3", Code: "m", Data: "install"},
+					{Time: "4", Code: "o", Data: "***\r\n"},
+				},
+			},
+		},
+		{
+			name: "happy path: seconds as number",
+			args: &args{
+				pipeline: `transforms:
+  - type: idle-limit
+    max: 0.5
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "0.5", Code: "i", Data: "echo s3cret\r"},
+					{Time: "1", Code: "m", Data: "install"},
+					{Time: "1.5", Code: "o", Data: "s3cret\r\n"},
+				},
+			},
+		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				pipeline: `transforms:
+  - type: delta
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Delta: &asciinema.V2HeaderDelta{Version: 1, Duration: 8}},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "3", Code: "i", Data: "echo s3cret\r"},
+					{Time: "1", Code: "m", Data: "install"},
+					{Time: "4", Code: "o", Data: "s3cret\r\n"},
+				},
+			},
+		},
+		{
+			name: "happy path: eval",
+			args: &args{
+				pipeline: `transforms:
+  - type: eval
+    script: |
+      if code == "o" && delta > 3 {
+        delta = 1
+      }
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "3", Code: "i", Data: "echo s3cret\r"},
+					{Time: "4", Code: "m", Data: "install"},
+					{Time: "5", Code: "o", Data: "s3cret\r\n"},
+				},
+			},
+		},
+		{
+			name: "happy path: coalesce and explode",
+			args: &args{
+				pipeline: `transforms:
+  - type: coalesce
+    threshold: 1.5s
+  - type: explode
+    unit: line
+    interval: 1s
+`,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "$ "},
+					{Time: "3", Code: "i", Data: "echo s3cret\r"},
+					{Time: "4", Code: "m", Data: "install"},
+					{Time: "8", Code: "o", Data: "s3cret\r\n"},
+				},
+			},
+		},
+		{
+			name: "edge path: empty",
+			args: &args{
+				pipeline: "",
+			},
+			expected: &expected{
+				err: errors.New("no transforms in pipeline"),
+			},
+		},
+		{
+			name: "edge path: invalid type",
+			args: &args{
+				pipeline: `transforms:
+  - type: unknown
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: invalid transform type: %v", 2, "unknown"),
+			},
+		},
+		{
+			name: "edge path: invalid parameter",
+			args: &args{
+				pipeline: `transforms:
+  - type: idle-limit
+    max: 1
+  - type: speed
+    factr: 2
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 4, errors.New("invalid parameter: factr")),
+			},
+		},
+		{
+			name: "edge path: plugin without command",
+			args: &args{
+				pipeline: `transforms:
+  - type: plugin
+    args: [--fast]
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("plugin command is required")),
+			},
+		},
+		{
+			name: "edge path: invalid duration",
+			args: &args{
+				pipeline: `transforms:
+  - type: idle-limit
+    max: soon
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("invalid duration: soon")),
+			},
+		},
+		{
+			name: "edge path: invalid script",
+			args: &args{
+				pipeline: `transforms:
+  - type: eval
+    script: if delta > 3 { delta = }
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, fmt.Errorf("invalid script: %w", errors.New("1:24: unexpected \"}\""))),
+			},
+		},
+		{
+			name: "edge path: invalid unit",
+			args: &args{
+				pipeline: `transforms:
+  - type: explode
+    unit: word
+    interval: 1s
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("invalid unit: word")),
+			},
+		},
+		{
+			name: "edge path: invalid range",
+			args: &args{
+				pipeline: `transforms:
+  - type: speed
+    factor: 2
+    start: 1
+    from: install
+`,
+			},
+			expected: &expected{
+				err: fmt.Errorf("line %d: %w", 2, errors.New("either a time (start, end) or a marker (from, to) is allowed for each end")),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			_ = os.WriteFile(filepath.Join(dir, "theme.json"), []byte(`{"fg":"#ffffff","bg":"#000000","palette":"#000000:#cc0000:#4e9a06:#c4a000:#3465a4:#75507b:#06989a:#d3d7cf"}`), 0o644)
+
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			p, err := Parse(strings.NewReader(tt.args.pipeline), dir)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.NoError(t, err)
+
+				out, err := Apply(p.Transform(io.Discard), h, events)
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, out)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"sync"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+const (
+	// PluginProtocol is the version of the plugin protocol.
+	//
+	// A plugin is an executable that first writes a handshake line such as
+	//
+	//	{"deltascii_plugin":1,"capabilities":["header","events"]}
+	//
+	// to stdout. Then it reads the header and the events of the asciicast as
+	// JSON lines from stdin, until stdin is closed, and writes its own to
+	// stdout, one JSON line each:
+	//
+	//   - "header": the plugin writes back a header before any event
+	//   - "events": the plugin reads events and writes zero or more events at
+	//     any time, otherwise events pass it by
+	//   - "delta": events carry Δ times instead of times on both sides
+	PluginProtocol = 1
+)
+
+var (
+	pluginCapabilities = []string{"header", "events", "delta"}
+)
+
+type pluginHandshake struct {
+	Protocol     int      `json:"deltascii_plugin"`
+	Capabilities []string `json:"capabilities"`
+}
+
+type plugin struct {
+	name   string
+	args   []string
+	stderr io.Writer
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	out   *bufio.Reader
+	enc   *json.Encoder
+
+	header bool
+	events bool
+	delta  Transform
+	acc    Transform
+
+	mu      sync.Mutex
+	queue   []asciinema.V2Event
+	done    chan struct{}
+	readErr error
+	waited  bool
+}
+
+// Plugin runs an external executable as a transform, speaking the plugin
+// protocol over its stdin and stdout. The stderr of the plugin goes to stderr.
+func Plugin(name string, args []string, stderr io.Writer) Transform {
+	return &plugin{name: name, args: args, stderr: stderr}
+}
+
+func (t *plugin) Header(h *asciinema.V2Header) error {
+	if err := t.start(); err != nil {
+		return t.fail(err)
+	}
+
+	if err := t.enc.Encode(h); err != nil {
+		return t.fail(err)
+	}
+
+	if t.header {
+		line, err := t.out.ReadBytes('\n')
+		if err != nil {
+			return t.fail(fmt.Errorf("missing header: %w", err))
+		}
+
+		var ph asciinema.V2Header
+		if err := json.Unmarshal(line, &ph); err != nil {
+			return t.fail(fmt.Errorf("invalid header: %w", err))
+		}
+		*h = ph
+	}
+
+	if !t.events {
+		if err := t.stdin.Close(); err != nil {
+			return t.fail(err)
+		}
+	}
+
+	t.done = make(chan struct{})
+	go t.read()
+
+	return nil
+}
+
+func (t *plugin) Event(e asciinema.V2Event, emit Emit) error {
+	if !t.events {
+		return emit(e)
+	}
+
+	if err := t.delta.Event(e, func(e asciinema.V2Event) error { return t.enc.Encode(&e) }); err != nil {
+		return t.fail(err)
+	}
+
+	return t.drain(emit)
+}
+
+func (t *plugin) Flush(emit Emit) error {
+	if t.events {
+		if err := t.stdin.Close(); err != nil {
+			return t.fail(err)
+		}
+	}
+
+	<-t.done
+	if err := t.drain(emit); err != nil {
+		return err
+	}
+
+	if t.readErr != nil {
+		return t.fail(t.readErr)
+	}
+
+	t.waited = true
+	if err := t.cmd.Wait(); err != nil {
+		return fmt.Errorf("plugin %v: %w", t.name, err)
+	}
+
+	return nil
+}
+
+// Close stops the plugin if the asciicast ends early, such as on an error.
+func (t *plugin) Close() error {
+	if t.cmd == nil || t.cmd.Process == nil || t.waited {
+		return nil
+	}
+
+	t.waited = true
+	_ = t.cmd.Process.Kill()
+	_ = t.cmd.Wait()
+
+	return nil
+}
+
+func (t *plugin) start() error {
+	t.cmd = exec.Command(t.name, t.args...)
+	t.cmd.Stderr = t.stderr
+
+	stdin, err := t.cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := t.cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := t.cmd.Start(); err != nil {
+		return err
+	}
+
+	t.stdin = stdin
+	t.out = bufio.NewReader(stdout)
+	t.enc = json.NewEncoder(stdin)
+	t.enc.SetEscapeHTML(false)
+
+	line, err := t.out.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("missing handshake: %w", err)
+	}
+
+	var hs pluginHandshake
+	if err := json.Unmarshal(line, &hs); err != nil {
+		return fmt.Errorf("invalid handshake: %w", err)
+	}
+
+	if hs.Protocol != PluginProtocol {
+		return fmt.Errorf("unsupported protocol: %v", hs.Protocol)
+	}
+
+	for _, c := range hs.Capabilities {
+		if !slices.Contains(pluginCapabilities, c) {
+			return fmt.Errorf("invalid capability: %v", c)
+		}
+	}
+
+	t.header = slices.Contains(hs.Capabilities, "header")
+	t.events = slices.Contains(hs.Capabilities, "events")
+	if !t.header && !t.events {
+		return errors.New("no header or events capability")
+	}
+
+	// NOTE: for the delta capability, convert times on the way in and back on the way out
+	t.delta, t.acc = new(Funcs), new(Funcs)
+	if slices.Contains(hs.Capabilities, "delta") {
+		t.delta, t.acc = Delta(), Accumulate(nil)
+	}
+
+	return nil
+}
+
+// read collects the events written by the plugin, so that the plugin never
+// blocks on a full stdout while deltascii writes to its stdin.
+func (t *plugin) read() {
+	defer close(t.done)
+
+	for {
+		line, err := t.out.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var e asciinema.V2Event
+			if err := json.Unmarshal(line, &e); err != nil {
+				t.readErr = fmt.Errorf("invalid event: %w", err)
+				return
+			}
+
+			t.mu.Lock()
+			t.queue = append(t.queue, e)
+			t.mu.Unlock()
+		}
+
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.readErr = err
+			return
+		}
+	}
+}
+
+func (t *plugin) drain(emit Emit) error {
+	t.mu.Lock()
+	queue := t.queue
+	t.queue = nil
+	t.mu.Unlock()
+
+	for _, e := range queue {
+		if err := t.acc.Event(e, emit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *plugin) fail(err error) error {
+	_ = t.Close()
+
+	return fmt.Errorf("plugin %v: %w", t.name, err)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPluginEnv = "DELTASCII_TEST_PLUGIN"
+)
+
+func TestMain(m *testing.M) {
+	// NOTE: the test binary doubles as a plugin so that plugin tests need no other executable
+	if mode := os.Getenv(testPluginEnv); mode != "" {
+		os.Exit(runTestPlugin(mode))
+	}
+
+	os.Exit(m.Run())
+}
+
+func runTestPlugin(mode string) int {
+	capabilities := map[string]string{
+		"upper":   `["events"]`,
+		"title":   `["header"]`,
+		"stretch": `["events","delta"]`,
+		"exit":    `["events"]`,
+	}
+
+	if c, ok := capabilities[mode]; ok {
+		fmt.Printf(`{"deltascii_plugin":1,"capabilities":%s}`+"\n", c)
+	} else {
+		fmt.Println(`{"deltascii_plugin":2,"capabilities":["events"]}`)
+		return 0
+	}
+
+	dec := json.NewDecoder(os.Stdin)
+	enc := json.NewEncoder(os.Stdout)
+
+	var h asciinema.V2Header
+	if err := dec.Decode(&h); err != nil {
+		return 1
+	}
+
+	if mode == "title" {
+		h.Title = "plugin"
+		_ = enc.Encode(&h)
+		return 0
+	}
+
+	for dec.More() {
+		var e asciinema.V2Event
+		if err := dec.Decode(&e); err != nil {
+			return 1
+		}
+
+		switch mode {
+		case "upper":
+			if s, ok := e.Data.(string); ok {
+				e.Data = strings.ToUpper(s)
+			}
+		case "stretch":
+			e.Time = asciinema.NewTime(e.Time.Decimal().Mul(decimal.NewFromInt(2)))
+		case "exit":
+			continue
+		}
+
+		_ = enc.Encode(&e)
+	}
+
+	if mode == "exit" {
+		fmt.Fprintln(os.Stderr, "failed")
+		return 3
+	}
+
+	return 0
+}
+
+func TestPlugin(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "0", Code: "o", Data: "a"},
+		{Time: "1", Code: "o", Data: "b"},
+		{Time: "3", Code: "m", Data: "c"},
+	}
+
+	type args struct {
+		mode string
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		stderr string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: events",
+			args: &args{
+				mode: "upper",
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "A"},
+					{Time: "1", Code: "o", Data: "B"},
+					{Time: "3", Code: "m", Data: "C"},
+				},
+			},
+		},
+		{
+			name: "happy path: header",
+			args: &args{
+				mode: "title",
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Title: "plugin"},
+				events: events,
+			},
+		},
+		{
+			name: "happy path: delta",
+			args: &args{
+				mode: "stretch",
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "2", Code: "o", Data: "b"},
+					{Time: "6", Code: "m", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "edge path: exit status",
+			args: &args{
+				mode: "exit",
+			},
+			expected: &expected{
+				stderr: "failed\n",
+				err:    fmt.Errorf("plugin %v: %v", os.Args[0], "exit status 3"),
+			},
+		},
+		{
+			name: "edge path: unsupported protocol",
+			args: &args{
+				mode: "handshake",
+			},
+			expected: &expected{
+				err: fmt.Errorf("plugin %v: %v", os.Args[0], "unsupported protocol: 2"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(testPluginEnv, tt.args.mode)
+
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+			stderr := new(bytes.Buffer)
+
+			// Act
+			out, err := Apply(Plugin(os.Args[0], nil, stderr), h, events)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, out)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.stderr, stderr.String())
+				assert.EqualError(t, err, tt.expected.err.Error())
+			}
+		})
+	}
+}
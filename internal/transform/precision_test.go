@@ -0,0 +1,94 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrecision(t *testing.T) {
+	events := []asciinema.V2Event{
+		{Time: "0.10", Code: "o", Data: "a"},
+		{Time: "0.3333333333333333", Code: "o", Data: "b"},
+		{Time: "1e-07", Code: "o", Data: "c"},
+		{Time: "2.0000005", Code: "o", Data: "d"},
+	}
+
+	type args struct {
+		places int32
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: microseconds",
+			args: &args{
+				places: asciinema.TimePrecision,
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.10", Code: "o", Data: "a"},
+					{Time: "0.333333", Code: "o", Data: "b"},
+					{Time: "0", Code: "o", Data: "c"},
+					{Time: "2.000001", Code: "o", Data: "d"},
+				},
+			},
+		},
+		{
+			name: "happy path: seconds",
+			args: &args{
+				places: 0,
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "0", Code: "o", Data: "b"},
+					{Time: "0", Code: "o", Data: "c"},
+					{Time: "2", Code: "o", Data: "d"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			out, err := Apply(Precision(tt.args.places), h, events)
+
+			// Assert
+			assert.Equal(t, tt.expected.events, out)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/shopspring/decimal"
+)
+
+type quantize struct {
+	step   decimal.Decimal
+	sumIn  decimal.Decimal
+	sumOut decimal.Decimal
+}
+
+// Quantize rounds Δ times, as made by Delta, to multiples of step. The sum of the
+// times is rounded rather than each time, so that rounding remainders carry over
+// to the next event and the total stays within half a step of the original.
+func Quantize(step float64) Transform {
+	return &quantize{step: decimal.NewFromFloat(step)}
+}
+
+func (t *quantize) Header(h *asciinema.V2Header) error {
+	return nil
+}
+
+func (t *quantize) Event(e asciinema.V2Event, emit Emit) error {
+	t.sumIn = t.sumIn.Add(e.Time.Decimal())
+
+	sum := t.sumIn.Div(t.step).Round(0).Mul(t.step)
+	e.Time = e.Time.Move(sum.Sub(t.sumOut))
+	t.sumOut = sum
+
+	return emit(e)
+}
+
+func (t *quantize) Flush(emit Emit) error {
+	return nil
+}
+
+// QuantizeTimes rounds event times since the beginning, as in asciicast v2, to
+// multiples of step, as Quantize does by their sum. An event already on the grid
+// keeps its time as written.
+func QuantizeTimes(step float64) Transform {
+	s := decimal.NewFromFloat(step)
+
+	return Map(func(e asciinema.V2Event) (asciinema.V2Event, error) {
+		e.Time = e.Time.Move(e.Time.Decimal().Div(s).Round(0).Mul(s))
+
+		return e, nil
+	})
+}
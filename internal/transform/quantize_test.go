@@ -0,0 +1,188 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantize(t *testing.T) {
+	type args struct {
+		step   float64
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				step: 0.01,
+				events: []asciinema.V2Event{
+					{Time: "0.143663", Code: "o", Data: "a"},
+					{Time: "0.182408", Code: "o", Data: "b"},
+					{Time: "0.174625", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.14", Code: "o", Data: "a"},
+					{Time: "0.19", Code: "o", Data: "b"},
+					{Time: "0.17", Code: "o", Data: "c"},
+				},
+			},
+		},
+		{
+			name: "happy path: remainders carried over",
+			args: &args{
+				step: 0.25,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.1", Code: "o", Data: "b"},
+					{Time: "0.1", Code: "o", Data: "c"},
+					{Time: "0.1", Code: "o", Data: "d"},
+					{Time: "0.1", Code: "o", Data: "e"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "0.25", Code: "o", Data: "b"},
+					{Time: "0", Code: "o", Data: "c"},
+					{Time: "0.25", Code: "o", Data: "d"},
+					{Time: "0", Code: "o", Data: "e"},
+				},
+			},
+		},
+		{
+			name: "happy path: on the grid",
+			args: &args{
+				step: 0.1,
+				events: []asciinema.V2Event{
+					{Time: "0.50", Code: "o", Data: "a"},
+					{Time: "1", Code: "o", Data: "b"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.50", Code: "o", Data: "a"},
+					{Time: "1", Code: "o", Data: "b"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			out, err := Apply(Quantize(tt.args.step), h, tt.args.events)
+
+			// Assert
+			assert.Equal(t, tt.expected.events, out)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestQuantizeTimes(t *testing.T) {
+	type args struct {
+		step   float64
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		events []asciinema.V2Event
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				step: 0.25,
+				events: []asciinema.V2Event{
+					{Time: "0.1", Code: "o", Data: "a"},
+					{Time: "0.2", Code: "o", Data: "b"},
+					{Time: "0.3", Code: "o", Data: "c"},
+					{Time: "0.4", Code: "o", Data: "d"},
+					{Time: "0.5", Code: "o", Data: "e"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "a"},
+					{Time: "0.25", Code: "o", Data: "b"},
+					{Time: "0.25", Code: "o", Data: "c"},
+					{Time: "0.5", Code: "o", Data: "d"},
+					{Time: "0.5", Code: "o", Data: "e"},
+				},
+			},
+		},
+		{
+			name: "happy path: on the grid",
+			args: &args{
+				step: 0.5,
+				events: []asciinema.V2Event{
+					{Time: "0.50", Code: "o", Data: "a"},
+					{Time: "2", Code: "o", Data: "b"},
+					{Time: "5.0", Code: "o", Data: "c"},
+				},
+			},
+			expected: &expected{
+				events: []asciinema.V2Event{
+					{Time: "0.50", Code: "o", Data: "a"},
+					{Time: "2", Code: "o", Data: "b"},
+					{Time: "5.0", Code: "o", Data: "c"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := &asciinema.V2Header{Version: 2, Width: 80, Height: 24}
+
+			// Act
+			out, err := Apply(QuantizeTimes(tt.args.step), h, tt.args.events)
+
+			// Assert
+			assert.Equal(t, tt.expected.events, out)
+			assert.NoError(t, err)
+		})
+	}
+}
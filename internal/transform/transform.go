@@ -0,0 +1,128 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"io"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+)
+
+// Emit passes an event on to the next stage.
+type Emit func(e asciinema.V2Event) error
+
+// Transform edits an asciicast as a stream. Header is called once before any
+// event, Event for every event in order and Flush after the last event, so a
+// transform may drop, hold back or add events. The header is written out after
+// Flush, so a transform may keep it and finish it there. A transform holding resources,
+// such as a plugin process, also implements io.Closer.
+type Transform interface {
+	Header(h *asciinema.V2Header) error
+	Event(e asciinema.V2Event, emit Emit) error
+	Flush(emit Emit) error
+}
+
+type chain []Transform
+
+// Chain composes transforms, where events flow from the first to the last.
+func Chain(ts ...Transform) Transform {
+	return chain(ts)
+}
+
+func (c chain) Header(h *asciinema.V2Header) error {
+	for _, t := range c {
+		if err := t.Header(h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c chain) Event(e asciinema.V2Event, emit Emit) error {
+	return c.event(0, e, emit)
+}
+
+func (c chain) Flush(emit Emit) error {
+	for i, t := range c {
+		// NOTE: events flushed by a stage still pass through the later stages before they flush
+		if err := t.Flush(func(e asciinema.V2Event) error { return c.event(i+1, e, emit) }); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c chain) Close() error {
+	errs := make([]error, 0, len(c))
+	for _, t := range c {
+		errs = append(errs, Close(t))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c chain) event(i int, e asciinema.V2Event, emit Emit) error {
+	if i == len(c) {
+		return emit(e)
+	}
+
+	return c[i].Event(e, func(e asciinema.V2Event) error { return c.event(i+1, e, emit) })
+}
+
+// Close releases the resources of t, if any. Call it after the stream ends,
+// whether it ends with Flush or with an error.
+func Close(t Transform) error {
+	if c, ok := t.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// Apply runs t over a whole asciicast in memory.
+func Apply(t Transform, h *asciinema.V2Header, events []asciinema.V2Event) ([]asciinema.V2Event, error) {
+	defer Close(t)
+
+	if err := t.Header(h); err != nil {
+		return nil, err
+	}
+
+	out := make([]asciinema.V2Event, 0, len(events))
+	emit := func(e asciinema.V2Event) error {
+		out = append(out, e)
+		return nil
+	}
+
+	for _, e := range events {
+		if err := t.Event(e, emit); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.Flush(emit); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
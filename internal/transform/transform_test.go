@@ -0,0 +1,163 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+// repeat emits every event n times and a marker on flush.
+type repeat struct {
+	n int
+}
+
+func (t *repeat) Header(h *asciinema.V2Header) error {
+	h.Title += "!"
+	return nil
+}
+
+func (t *repeat) Event(e asciinema.V2Event, emit Emit) error {
+	for i := 0; i < t.n; i++ {
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *repeat) Flush(emit Emit) error {
+	return emit(asciinema.V2Event{Time: "9", Code: "m", Data: "end"})
+}
+
+type failing struct {
+	repeat
+}
+
+func (t *failing) Event(e asciinema.V2Event, emit Emit) error {
+	return errors.New("failed")
+}
+
+func TestApply(t *testing.T) {
+	type args struct {
+		t      Transform
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		title  string
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: single",
+			args: &args{
+				t: &repeat{n: 2},
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+				},
+			},
+			expected: &expected{
+				title: "!",
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+					{Time: "1", Code: "o", Data: "a"},
+					{Time: "9", Code: "m", Data: "end"},
+				},
+			},
+		},
+		{
+			name: "happy path: chain",
+			args: &args{
+				t: Chain(&repeat{n: 0}, &repeat{n: 2}),
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+				},
+			},
+			expected: &expected{
+				title: "!!",
+				events: []asciinema.V2Event{
+					// NOTE: the first flush passes through the second stage before it flushes
+					{Time: "9", Code: "m", Data: "end"},
+					{Time: "9", Code: "m", Data: "end"},
+					{Time: "9", Code: "m", Data: "end"},
+				},
+			},
+		},
+		{
+			name: "happy path: empty chain",
+			args: &args{
+				t: Chain(),
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+				},
+			},
+			expected: &expected{
+				title: "",
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+				},
+			},
+		},
+		{
+			name: "edge path: failed",
+			args: &args{
+				t: Chain(&repeat{n: 1}, &failing{}),
+				events: []asciinema.V2Event{
+					{Time: "1", Code: "o", Data: "a"},
+				},
+			},
+			expected: &expected{
+				err: errors.New("failed"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			h := new(asciinema.V2Header)
+
+			// Act
+			events, err := Apply(tt.args.t, h, tt.args.events)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.title, h.Title)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ttyrec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/xutf8"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// NOTE: xterm window manipulation "CSI 8 ; height ; width t"
+	resizePattern = regexp.MustCompile(`\x1b\[8;(\d+);(\d+)t`)
+)
+
+type Frame struct {
+	Sec  uint32
+	Usec uint32
+	Data []byte
+}
+
+func Read(r io.Reader) ([]Frame, error) {
+	frames := make([]Frame, 0)
+	for {
+		var h [3]uint32
+		if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+			if errors.Is(err, io.EOF) {
+				return frames, nil
+			}
+			return nil, err
+		}
+
+		data := make([]byte, h[2])
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		frames = append(frames, Frame{Sec: h[0], Usec: h[1], Data: data})
+	}
+}
+
+func Write(w io.Writer, frames []Frame) error {
+	for _, f := range frames {
+		if len(f.Data) > math.MaxUint32 {
+			return fmt.Errorf("too large frame: %d bytes", len(f.Data))
+		}
+
+		h := [3]uint32{f.Sec, f.Usec, uint32(len(f.Data))}
+		if err := binary.Write(w, binary.LittleEndian, h); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(f.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ToV2 converts frames into an asciicast. When width or height is 0, it is inferred
+// from the first resize sequence in the output.
+func ToV2(frames []Frame, width, height int) (*asciinema.V2Header, []asciinema.V2Event, error) {
+	if width == 0 || height == 0 {
+		w, h, ok := inferSize(frames)
+		if !ok {
+			return nil, nil, errors.New("unable to infer terminal size, width and height required")
+		}
+
+		if width == 0 {
+			width = w
+		}
+		if height == 0 {
+			height = h
+		}
+	}
+
+	header := &asciinema.V2Header{
+		Version: 2,
+		Width:   width,
+		Height:  height,
+	}
+
+	events := make([]asciinema.V2Event, 0, len(frames))
+	if len(frames) == 0 {
+		return header, events, nil
+	}
+
+	start := frameTime(frames[0])
+	header.Timestamp = int(frames[0].Sec)
+
+	dec := new(xutf8.Decoder)
+	var last decimal.Decimal
+	for _, f := range frames {
+		last = frameTime(f).Sub(start)
+		if data := dec.Decode(f.Data); data != "" {
+			events = append(events, asciinema.V2Event{Time: asciinema.NewTime(last), Code: "o", Data: data})
+		}
+	}
+	if data := dec.Flush(); data != "" {
+		events = append(events, asciinema.V2Event{Time: asciinema.NewTime(last), Code: "o", Data: data})
+	}
+
+	return header, events, nil
+}
+
+// FromV2 converts output events into frames. Resize events are written as resize
+// sequences and other events are dropped.
+func FromV2(header *asciinema.V2Header, events []asciinema.V2Event) ([]Frame, error) {
+	start := decimal.NewFromInt(int64(header.Timestamp))
+
+	frames := make([]Frame, 0, len(events))
+	for _, e := range events {
+		data, ok := e.Data.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid event data: %v", e.Data)
+		}
+
+		switch e.Code {
+		case "o":
+		case "r":
+			var w, h int
+			if _, err := fmt.Sscanf(data, "%dx%d", &w, &h); err != nil {
+				return nil, fmt.Errorf("invalid resize event data: %v", e.Data)
+			}
+			data = fmt.Sprintf("\x1b[8;%d;%dt", h, w)
+		default:
+			continue
+		}
+
+		usec := start.Add(e.Time.Decimal()).Shift(6).Round(0).IntPart()
+		frames = append(frames, Frame{Sec: uint32(usec / 1_000_000), Usec: uint32(usec % 1_000_000), Data: []byte(data)})
+	}
+
+	return frames, nil
+}
+
+func frameTime(f Frame) decimal.Decimal {
+	return decimal.New(int64(f.Sec)*1_000_000+int64(f.Usec), -6)
+}
+
+func inferSize(frames []Frame) (width, height int, ok bool) {
+	for _, f := range frames {
+		m := resizePattern.FindSubmatch(f.Data)
+		if m == nil {
+			continue
+		}
+
+		h, err := strconv.Atoi(string(m[1]))
+		if err != nil {
+			continue
+		}
+
+		w, err := strconv.Atoi(string(m[2]))
+		if err != nil {
+			continue
+		}
+
+		return w, h, true
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ttyrec
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadWrite(t *testing.T) {
+	data := []byte{
+		0x00, 0x00, 0x00, 0x5a, 0x20, 0xa1, 0x07, 0x00, 0x02, 0x00, 0x00, 0x00, 'h', 'i',
+		0x01, 0x00, 0x00, 0x5a, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, '!',
+	}
+	frames := []Frame{
+		{Sec: 1509949440, Usec: 500000, Data: []byte("hi")},
+		{Sec: 1509949441, Usec: 0, Data: []byte("!")},
+	}
+
+	type args struct {
+		data []byte
+	}
+
+	type expected struct {
+		frames []Frame
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				data: data,
+			},
+			expected: &expected{
+				frames: frames,
+			},
+		},
+		{
+			name: "edge path: truncated data",
+			args: &args{
+				data: data[:len(data)-1],
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := Read(bytes.NewReader(tt.args.data))
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.frames, actual)
+				assert.NoError(t, err)
+
+				buf := new(bytes.Buffer)
+				assert.NoError(t, Write(buf, actual))
+				assert.Equal(t, tt.args.data, buf.Bytes())
+			} else {
+				assert.Nil(t, actual)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestToV2(t *testing.T) {
+	type args struct {
+		frames []Frame
+		width  int
+		height int
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: explicit size",
+			args: &args{
+				frames: []Frame{
+					{Sec: 100, Usec: 500000, Data: []byte("h")},
+					{Sec: 100, Usec: 600000, Data: []byte("i\xe3\x81")},
+					{Sec: 101, Usec: 100000, Data: []byte("\x82")},
+				},
+				width:  100,
+				height: 30,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 100, Height: 30, Timestamp: 100},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "h"},
+					{Time: "0.1", Code: "o", Data: "i"},
+					{Time: "0.6", Code: "o", Data: "あ"},
+				},
+			},
+		},
+		{
+			name: "happy path: inferred size",
+			args: &args{
+				frames: []Frame{
+					{Sec: 100, Usec: 0, Data: []byte("\x1b[8;24;80t")},
+					{Sec: 100, Usec: 1, Data: []byte("$ ")},
+				},
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Timestamp: 100},
+				events: []asciinema.V2Event{
+					{Time: "0", Code: "o", Data: "\x1b[8;24;80t"},
+					{Time: "0.000001", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "edge path: unknown size",
+			args: &args{
+				frames: []Frame{
+					{Sec: 100, Usec: 0, Data: []byte("$ ")},
+				},
+			},
+			expected: &expected{
+				err: errors.New("unable to infer terminal size, width and height required"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			h, events, err := ToV2(tt.args.frames, tt.args.width, tt.args.height)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, h)
+				assert.Nil(t, events)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestFromV2(t *testing.T) {
+	type args struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+	}
+
+	type expected struct {
+		frames []Frame
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24, Timestamp: 100},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "hi"},
+					{Time: "0.6", Code: "i", Data: "x"},
+					{Time: "1.9999999", Code: "r", Data: "100x30"},
+				},
+			},
+			expected: &expected{
+				frames: []Frame{
+					{Sec: 100, Usec: 500000, Data: []byte("hi")},
+					{Sec: 102, Usec: 0, Data: []byte("\x1b[8;30;100t")},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid resize",
+			args: &args{
+				header: &asciinema.V2Header{Version: 2, Width: 80, Height: 24},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "r", Data: "large"},
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual, err := FromV2(tt.args.header, tt.args.events)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.frames, actual)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, actual)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package typescript
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/Aton-Kish/deltascii/internal/xutf8"
+	"github.com/shopspring/decimal"
+)
+
+type Format string
+
+const (
+	// FormatClassic is the timing of "script -t", lines of "<delay> <size>" for output only.
+	FormatClassic Format = "classic"
+	// FormatAdvanced is the timing of "script --log-timing", lines of "<type> <delay> <size or info>"
+	// for output (O), input (I), signals (S) and header information (H).
+	FormatAdvanced Format = "advanced"
+)
+
+const (
+	startedPrefix = "Script started on "
+	timeLayout    = "2006-01-02 15:04:05-07:00"
+)
+
+var (
+	infoPattern   = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	winchPattern  = regexp.MustCompile(`ROWS=(\d+) COLS=(\d+)`)
+	errNoTermSize = errors.New("unable to infer terminal size, width and height required")
+)
+
+type stream struct {
+	r   *bufio.Reader
+	dec *xutf8.Decoder
+}
+
+func newStream(r io.Reader, h *asciinema.V2Header) (*stream, error) {
+	br := bufio.NewReader(r)
+
+	// NOTE: script writes "Script started on ... [KEY="VALUE" ...]" before the logged bytes unless --quiet
+	peek, _ := br.Peek(len(startedPrefix))
+	if string(peek) == startedPrefix {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		parseStartedLine(line, h)
+	}
+
+	return &stream{r: br, dec: new(xutf8.Decoder)}, nil
+}
+
+func (s *stream) read(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		return "", fmt.Errorf("log is shorter than timing: %w", err)
+	}
+
+	return s.dec.Decode(buf), nil
+}
+
+// ToV2 combines a timing file with the logged bytes. in is the input log of "script --log-in",
+// nil means that input is logged into the same file as output, such as "script --log-io".
+func ToV2(timing io.Reader, out io.Reader, in io.Reader, width, height int) (*asciinema.V2Header, []asciinema.V2Event, error) {
+	h := &asciinema.V2Header{Version: 2}
+
+	outStream, err := newStream(out, h)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inStream := outStream
+	if in != nil {
+		if inStream, err = newStream(in, h); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	events := make([]asciinema.V2Event, 0)
+	t := decimal.Zero
+	sized := false
+
+	sc := bufio.NewScanner(timing)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		// NOTE: classic timing has no type field
+		typ := "O"
+		if _, err := decimal.NewFromString(fields[0]); err != nil {
+			typ, fields = fields[0], fields[1:]
+		}
+
+		if len(fields) < 2 {
+			return nil, nil, fmt.Errorf("invalid timing at line %d: %s", n, sc.Text())
+		}
+
+		delay, err := decimal.NewFromString(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid timing at line %d: %s", n, sc.Text())
+		}
+		t = t.Add(delay)
+
+		switch typ {
+		case "O", "I":
+			size, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid timing at line %d: %s", n, sc.Text())
+			}
+
+			s, code := outStream, "o"
+			if typ == "I" {
+				s, code = inStream, "i"
+			}
+
+			data, err := s.read(size)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if data != "" {
+				events = append(events, asciinema.V2Event{Time: asciinema.NewTime(t), Code: code, Data: data})
+			}
+		case "S":
+			info := strings.Join(fields[2:], " ")
+			if fields[1] != "SIGWINCH" {
+				events = append(events, asciinema.V2Event{Time: asciinema.NewTime(t), Code: "m", Data: fields[1]})
+				continue
+			}
+
+			m := winchPattern.FindStringSubmatch(info)
+			if m == nil {
+				return nil, nil, fmt.Errorf("invalid timing at line %d: %s", n, sc.Text())
+			}
+
+			if !sized && len(events) == 0 {
+				h.Height, _ = strconv.Atoi(m[1])
+				h.Width, _ = strconv.Atoi(m[2])
+			} else {
+				events = append(events, asciinema.V2Event{Time: asciinema.NewTime(t), Code: "r", Data: fmt.Sprintf("%sx%s", m[2], m[1])})
+			}
+			sized = true
+		case "H":
+			setInfo(h, fields[1], strings.Join(fields[2:], " "))
+		default:
+			return nil, nil, fmt.Errorf("invalid timing at line %d: %s", n, sc.Text())
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, s := range []*stream{outStream, inStream} {
+		if data := s.dec.Flush(); data != "" {
+			code := "o"
+			if s != outStream {
+				code = "i"
+			}
+			events = append(events, asciinema.V2Event{Time: asciinema.NewTime(t), Code: code, Data: data})
+		}
+	}
+
+	if width != 0 {
+		h.Width = width
+	}
+	if height != 0 {
+		h.Height = height
+	}
+	if h.Width == 0 || h.Height == 0 {
+		return nil, nil, errNoTermSize
+	}
+
+	return h, events, nil
+}
+
+// FromV2 writes the logged bytes and the timing that scriptreplay can play.
+// Classic format keeps output only, advanced format keeps output, input and resize.
+func FromV2(h *asciinema.V2Header, events []asciinema.V2Event, format Format, log io.Writer, timing io.Writer) error {
+	if format != FormatClassic && format != FormatAdvanced {
+		return fmt.Errorf("invalid format: %v", format)
+	}
+
+	start := time.Unix(int64(h.Timestamp), 0).UTC().Format(timeLayout)
+	info := fmt.Sprintf(`COLUMNS="%d" LINES="%d"`, h.Width, h.Height)
+	if term := h.Env["TERM"]; term != "" {
+		info = fmt.Sprintf(`TERM="%s" %s`, term, info)
+	}
+	if _, err := fmt.Fprintf(log, "%s%s [%s]\n", startedPrefix, start, info); err != nil {
+		return err
+	}
+
+	tw := new(bytes.Buffer)
+	if format == FormatAdvanced {
+		fmt.Fprintf(tw, "H %s START_TIME %s\n", decimal.Zero.StringFixed(6), start)
+		if term := h.Env["TERM"]; term != "" {
+			fmt.Fprintf(tw, "H %s TERM %s\n", decimal.Zero.StringFixed(6), term)
+		}
+		fmt.Fprintf(tw, "H %s COLUMNS %d\n", decimal.Zero.StringFixed(6), h.Width)
+		fmt.Fprintf(tw, "H %s LINES %d\n", decimal.Zero.StringFixed(6), h.Height)
+	}
+
+	prev := decimal.Zero
+	for _, e := range events {
+		data, ok := e.Data.(string)
+		if !ok {
+			return fmt.Errorf("invalid event data: %v", e.Data)
+		}
+
+		t := e.Time.Decimal()
+		delay := t.Sub(prev).StringFixed(6)
+
+		switch {
+		case e.Code == "o" && format == FormatClassic:
+			fmt.Fprintf(tw, "%s %d\n", delay, len(data))
+		case (e.Code == "o" || e.Code == "i") && format == FormatAdvanced:
+			fmt.Fprintf(tw, "%s %s %d\n", strings.ToUpper(e.Code), delay, len(data))
+		case e.Code == "r" && format == FormatAdvanced:
+			var w, ht int
+			if _, err := fmt.Sscanf(data, "%dx%d", &w, &ht); err != nil {
+				return fmt.Errorf("invalid resize event data: %v", e.Data)
+			}
+			fmt.Fprintf(tw, "S %s SIGWINCH ROWS=%d COLS=%d\n", delay, ht, w)
+			data = ""
+		default:
+			continue
+		}
+		prev = t
+
+		if _, err := io.WriteString(log, data); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(log, "\nScript done on %s [COMMAND_EXIT_CODE=\"0\"]\n", start); err != nil {
+		return err
+	}
+
+	_, err := tw.WriteTo(timing)
+
+	return err
+}
+
+func parseStartedLine(line string, h *asciinema.V2Header) {
+	rest := strings.TrimPrefix(strings.TrimSpace(line), startedPrefix)
+	if date, _, ok := strings.Cut(rest, " ["); ok {
+		if t, err := time.Parse(timeLayout, date); err == nil {
+			h.Timestamp = int(t.Unix())
+		}
+	}
+
+	for _, m := range infoPattern.FindAllStringSubmatch(rest, -1) {
+		setInfo(h, m[1], m[2])
+	}
+}
+
+func setInfo(h *asciinema.V2Header, key, value string) {
+	switch key {
+	case "START_TIME":
+		if t, err := time.Parse(timeLayout, value); err == nil {
+			h.Timestamp = int(t.Unix())
+		}
+	case "COLUMNS":
+		h.Width, _ = strconv.Atoi(value)
+	case "LINES":
+		h.Height, _ = strconv.Atoi(value)
+	case "TERM", "SHELL":
+		if h.Env == nil {
+			h.Env = make(map[string]string)
+		}
+		h.Env[key] = value
+	case "COMMAND":
+		h.Command = value
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package typescript
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Aton-Kish/deltascii/internal/asciinema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToV2(t *testing.T) {
+	type args struct {
+		timing string
+		out    string
+		in     string
+		width  int
+		height int
+	}
+
+	type expected struct {
+		header *asciinema.V2Header
+		events []asciinema.V2Event
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: classic",
+			args: &args{
+				timing: "testdata/classic.timing",
+				out:    "testdata/classic.typescript",
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{
+					Version:   2,
+					Width:     80,
+					Height:    24,
+					Timestamp: 1504467315,
+					Env:       map[string]string{"TERM": "xterm-256color"},
+				},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "$ ls"},
+					{Time: "0.75", Code: "o", Data: "\r\nfoo\r\n"},
+					{Time: "1.75", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "happy path: advanced",
+			args: &args{
+				timing: "testdata/advanced.timing",
+				out:    "testdata/advanced.typescript",
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{
+					Version:   2,
+					Width:     80,
+					Height:    24,
+					Timestamp: 1504467315,
+					Env:       map[string]string{"TERM": "xterm-256color"},
+				},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "$ "},
+					{Time: "0.6", Code: "i", Data: "ls\r"},
+					{Time: "0.6001", Code: "o", Data: "ls\r\n"},
+					{Time: "0.8001", Code: "r", Data: "100x30"},
+					{Time: "0.8501", Code: "o", Data: "foo\r\n"},
+					{Time: "0.9501", Code: "m", Data: "SIGTERM"},
+					{Time: "1.8501", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "happy path: explicit size",
+			args: &args{
+				timing: "testdata/classic.timing",
+				out:    "testdata/classic.typescript",
+				width:  100,
+				height: 30,
+			},
+			expected: &expected{
+				header: &asciinema.V2Header{
+					Version:   2,
+					Width:     100,
+					Height:    30,
+					Timestamp: 1504467315,
+					Env:       map[string]string{"TERM": "xterm-256color"},
+				},
+				events: []asciinema.V2Event{
+					{Time: "0.5", Code: "o", Data: "$ ls"},
+					{Time: "0.75", Code: "o", Data: "\r\nfoo\r\n"},
+					{Time: "1.75", Code: "o", Data: "$ "},
+				},
+			},
+		},
+		{
+			name: "edge path: invalid timing",
+			args: &args{
+				timing: "testdata/classic.typescript",
+				out:    "testdata/classic.typescript",
+			},
+			expected: &expected{
+				err: fmt.Errorf("invalid timing at line %d: %s", 1, `Script started on 2017-09-03 19:35:15+00:00 [TERM="xterm-256color" TTY="/dev/pts/0" COLUMNS="80" LINES="24"]`),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			timing, _ := os.Open(tt.args.timing)
+			defer timing.Close()
+
+			out, _ := os.Open(tt.args.out)
+			defer out.Close()
+
+			var in io.Reader
+			if tt.args.in != "" {
+				f, _ := os.Open(tt.args.in)
+				defer f.Close()
+				in = f
+			}
+
+			// Act
+			h, events, err := ToV2(timing, out, in, tt.args.width, tt.args.height)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.header, h)
+				assert.Equal(t, tt.expected.events, events)
+				assert.NoError(t, err)
+			} else {
+				assert.Nil(t, h)
+				assert.Nil(t, events)
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
+
+func TestToV2_SeparateInput(t *testing.T) {
+	// Arrange
+	timing := strings.NewReader("O 0.5 2\nI 0.1 3\nO 0.1 4\n")
+	out := strings.NewReader("$ ls\r\n")
+	in := strings.NewReader("ls\r")
+
+	// Act
+	h, events, err := ToV2(timing, out, in, 80, 24)
+
+	// Assert
+	assert.Equal(t, &asciinema.V2Header{Version: 2, Width: 80, Height: 24}, h)
+	assert.Equal(t, []asciinema.V2Event{
+		{Time: "0.5", Code: "o", Data: "$ "},
+		{Time: "0.6", Code: "i", Data: "ls\r"},
+		{Time: "0.7", Code: "o", Data: "ls\r\n"},
+	}, events)
+	assert.NoError(t, err)
+}
+
+func TestFromV2(t *testing.T) {
+	header := &asciinema.V2Header{
+		Version:   2,
+		Width:     80,
+		Height:    24,
+		Timestamp: 1504467315,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	events := []asciinema.V2Event{
+		{Time: "0.5", Code: "o", Data: "$ "},
+		{Time: "0.6", Code: "i", Data: "ls\r"},
+		{Time: "0.6001", Code: "o", Data: "ls\r\n"},
+		{Time: "0.8001", Code: "r", Data: "100x30"},
+		{Time: "0.8501", Code: "o", Data: "foo\r\n"},
+		{Time: "0.9501", Code: "m", Data: "SIGTERM"},
+		{Time: "1.8501", Code: "o", Data: "$ "},
+	}
+
+	type args struct {
+		format Format
+	}
+
+	type expected struct {
+		log    string
+		timing string
+		err    error
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: classic",
+			args: &args{
+				format: FormatClassic,
+			},
+			expected: &expected{
+				log: `Script started on 2017-09-03 19:35:15+00:00 [TERM="xterm-256color" COLUMNS="80" LINES="24"]
+$ ls` + "\r\nfoo\r\n$ " + `
+Script done on 2017-09-03 19:35:15+00:00 [COMMAND_EXIT_CODE="0"]
+`,
+				timing: `0.500000 2
+0.100100 4
+0.250000 5
+1.000000 2
+`,
+			},
+		},
+		{
+			name: "happy path: advanced",
+			args: &args{
+				format: FormatAdvanced,
+			},
+			expected: &expected{
+				log: `Script started on 2017-09-03 19:35:15+00:00 [TERM="xterm-256color" COLUMNS="80" LINES="24"]
+$ ls` + "\rls\r\nfoo\r\n$ " + `
+Script done on 2017-09-03 19:35:15+00:00 [COMMAND_EXIT_CODE="0"]
+`,
+				timing: `H 0.000000 START_TIME 2017-09-03 19:35:15+00:00
+H 0.000000 TERM xterm-256color
+H 0.000000 COLUMNS 80
+H 0.000000 LINES 24
+O 0.500000 2
+I 0.100000 3
+O 0.000100 4
+S 0.200000 SIGWINCH ROWS=30 COLS=100
+O 0.050000 5
+O 1.000000 2
+`,
+			},
+		},
+		{
+			name: "edge path: invalid format",
+			args: &args{
+				format: Format("unknown"),
+			},
+			expected: &expected{
+				err: errors.New("invalid format: unknown"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			log := new(bytes.Buffer)
+			timing := new(bytes.Buffer)
+
+			// Act
+			err := FromV2(header, events, tt.args.format, log, timing)
+
+			// Assert
+			if strings.HasPrefix(tt.name, "happy") {
+				assert.Equal(t, tt.expected.log, log.String())
+				assert.Equal(t, tt.expected.timing, timing.String())
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tt.expected.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	controlNames = [...]string{
+		"NUL", "SOH", "STX", "ETX", "EOT", "ENQ", "ACK", "BEL", "BS", "HT", "LF", "VT", "FF", "CR", "SO", "SI",
+		"DLE", "DC1", "DC2", "DC3", "DC4", "NAK", "SYN", "ETB", "CAN", "EM", "SUB", "ESC", "FS", "GS", "RS", "US",
+	}
+
+	csiNames = map[string]string{
+		"@": "ICH", "A": "CUU", "B": "CUD", "C": "CUF", "D": "CUB", "E": "CNL", "F": "CPL", "G": "CHA",
+		"H": "CUP", "J": "ED", "K": "EL", "L": "IL", "M": "DL", "P": "DCH", "S": "SU", "T": "SD",
+		"X": "ECH", "c": "DA", "d": "VPA", "f": "HVP", "m": "SGR", "n": "DSR", "r": "DECSTBM",
+		"s": "SCOSC", "t": "XTWINOPS", "u": "SCORC", "?h": "DECSET", "?l": "DECRST",
+	}
+
+	escapeNames = map[string]string{
+		"7": "DECSC", "8": "DECRC", "=": "DECKPAM", ">": "DECKPNM", "D": "IND", "E": "NEL", "H": "HTS",
+		"M": "RI", "c": "RIS",
+	}
+
+	stringNames = map[byte]string{
+		']': "OSC", 'P': "DCS", 'X': "SOS", '^': "PM", '_': "APC",
+	}
+)
+
+// Describe spells out the control characters and escape sequences in s with
+// their mnemonics, such as <CR><LF> and <SGR 1;32>, and keeps text as is.
+func Describe(s string) string {
+	tokens, rest := Tokens(s)
+
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(describeToken(t))
+	}
+
+	if rest != "" {
+		fmt.Fprintf(&b, "<incomplete %s>", strings.TrimPrefix(rest, "\x1b"))
+	}
+
+	return b.String()
+}
+
+func describeToken(t Token) string {
+	switch t.Kind {
+	case TokenControl:
+		r := []rune(t.Raw)[0]
+		switch {
+		case r < 0x20:
+			return "<" + controlNames[r] + ">"
+		case r == 0x7f:
+			return "<DEL>"
+		default:
+			return fmt.Sprintf("<U+%04X>", r)
+		}
+	case TokenEscape:
+		seq := t.Raw[1:]
+		if name, ok := escapeNames[seq]; ok {
+			return "<" + name + ">"
+		}
+
+		return "<ESC " + seq + ">"
+	case TokenCSI:
+		body := t.Raw[2 : len(t.Raw)-1]
+		final := t.Raw[len(t.Raw)-1:]
+		key := final
+		if strings.HasPrefix(body, "?") {
+			key = "?" + final
+		}
+
+		if name, ok := csiNames[key]; ok && strings.Trim(body, "?0123456789;:") == "" {
+			if params := strings.TrimPrefix(body, "?"); params != "" {
+				return "<" + name + " " + params + ">"
+			}
+
+			return "<" + name + ">"
+		}
+
+		return "<CSI " + body + final + ">"
+	case TokenString:
+		body := strings.TrimSuffix(strings.TrimSuffix(t.Raw[2:], "\x07"), "\x1b\\")
+		return "<" + stringNames[t.Raw[1]] + " " + body + ">"
+	default:
+		return t.Raw
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	type args struct {
+		s string
+	}
+
+	type expected struct {
+		s string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: text",
+			args:     &args{s: "hello"},
+			expected: &expected{s: "hello"},
+		},
+		{
+			name:     "happy path: controls",
+			args:     &args{s: "a\r\n\t\x7f\x07\u0085"},
+			expected: &expected{s: "a<CR><LF><HT><DEL><BEL><U+0085>"},
+		},
+		{
+			name:     "happy path: csi",
+			args:     &args{s: "\x1b[1;32muser\x1b[m\x1b[2J\x1b[?1049h\x1b[?2004l\x1b[>4;1m"},
+			expected: &expected{s: "<SGR 1;32>user<SGR><ED 2><DECSET 1049><DECRST 2004><CSI >4;1m>"},
+		},
+		{
+			name:     "happy path: escape",
+			args:     &args{s: "\x1b7\x1b(B\x1b8"},
+			expected: &expected{s: "<DECSC><ESC (B><DECRC>"},
+		},
+		{
+			name:     "happy path: strings",
+			args:     &args{s: "\x1b]0;title\x07\x1b]8;;http://x\x1b\\"},
+			expected: &expected{s: "<OSC 0;title><OSC 8;;http://x>"},
+		},
+		{
+			name:     "happy path: incomplete",
+			args:     &args{s: "a\x1b[1;3"},
+			expected: &expected{s: "a<incomplete [1;3>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := Describe(tt.args.s)
+
+			// Assert
+			assert.Equal(t, tt.expected.s, actual)
+		})
+	}
+}
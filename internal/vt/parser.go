@@ -0,0 +1,440 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"strconv"
+	"strings"
+)
+
+type parserState uint8
+
+const (
+	stateGround parserState = iota
+	stateEscape
+	stateCharset
+	stateCSI
+	stateString
+	stateStringEscape
+)
+
+var (
+	// NOTE: DEC Special Graphics selected by ESC ( 0
+	lineDrawing = map[rune]rune{
+		'`': '◆', 'a': '▒', 'f': '°', 'g': '±', 'j': '┘', 'k': '┐', 'l': '┌', 'm': '└',
+		'n': '┼', 'o': '⎺', 'p': '⎻', 'q': '─', 'r': '⎼', 's': '⎽', 't': '├', 'u': '┤',
+		'v': '┴', 'w': '┬', 'x': '│', 'y': '≤', 'z': '≥', '{': 'π', '|': '≠', '}': '£',
+		'~': '·',
+	}
+)
+
+type parser struct {
+	state        parserState
+	private      rune
+	params       strings.Builder
+	intermediate strings.Builder
+	charset      rune
+}
+
+func (p *parser) feed(t *Terminal, r rune) {
+	// NOTE: C0 controls are executed in the middle of any sequence except strings
+	if r < 0x20 && p.state != stateString && p.state != stateStringEscape {
+		switch r {
+		case 0x1b:
+			p.state = stateEscape
+			p.intermediate.Reset()
+		case 0x18, 0x1a:
+			p.state = stateGround
+		default:
+			t.execute(r)
+		}
+
+		return
+	}
+
+	switch p.state {
+	case stateGround:
+		if r == 0x7f {
+			return
+		}
+
+		t.print(r)
+	case stateEscape:
+		p.escape(t, r)
+	case stateCharset:
+		if p.charset == '(' {
+			t.cursor.lineDrawing = r == '0'
+		}
+		p.state = stateGround
+	case stateCSI:
+		p.csi(t, r)
+	case stateString:
+		switch r {
+		case 0x07:
+			p.state = stateGround
+		case 0x1b:
+			p.state = stateStringEscape
+		}
+	case stateStringEscape:
+		if r == '\\' {
+			p.state = stateGround
+		} else {
+			p.state = stateString
+		}
+	}
+}
+
+func (p *parser) escape(t *Terminal, r rune) {
+	p.state = stateGround
+
+	switch r {
+	case '[':
+		p.state = stateCSI
+		p.private = 0
+		p.params.Reset()
+		p.intermediate.Reset()
+	case ']', 'P', 'X', '^', '_':
+		p.state = stateString
+	case '(', ')', '*', '+':
+		p.state = stateCharset
+		p.charset = r
+	case '#', ' ', '%':
+		// NOTE: ignore DEC alignment tests and character set announcements
+		p.state = stateCharset
+		p.charset = r
+	case '7':
+		t.saveCursor()
+	case '8':
+		t.restoreCursor()
+	case 'D':
+		t.lineFeed()
+	case 'E':
+		t.cursor.x = 0
+		t.lineFeed()
+	case 'M':
+		t.reverseIndex()
+	case 'c':
+		t.reset()
+	}
+}
+
+func (p *parser) csi(t *Terminal, r rune) {
+	switch {
+	case r >= '0' && r <= '9', r == ';', r == ':':
+		p.params.WriteRune(r)
+	case r >= '<' && r <= '?':
+		p.private = r
+	case r >= 0x20 && r <= 0x2f:
+		p.intermediate.WriteRune(r)
+	case r >= 0x40 && r <= 0x7e:
+		p.state = stateGround
+		if p.intermediate.Len() > 0 {
+			return
+		}
+
+		t.dispatchCSI(p.private, parseParams(p.params.String()), r)
+	default:
+		p.state = stateGround
+	}
+}
+
+// parseParams splits CSI parameters; each parameter keeps its colon separated
+// sub-parameters, and an omitted value is -1.
+func parseParams(s string) [][]int {
+	if s == "" {
+		return nil
+	}
+
+	fields := strings.Split(s, ";")
+	params := make([][]int, 0, len(fields))
+	for _, f := range fields {
+		subs := strings.Split(f, ":")
+		param := make([]int, 0, len(subs))
+		for _, sub := range subs {
+			n, err := strconv.Atoi(sub)
+			if err != nil {
+				n = -1
+			}
+
+			param = append(param, n)
+		}
+
+		params = append(params, param)
+	}
+
+	return params
+}
+
+func param(params [][]int, i, def int) int {
+	if i >= len(params) || params[i][0] < 0 {
+		return def
+	}
+
+	return params[i][0]
+}
+
+// count is a parameter where zero means one.
+func count(params [][]int, i int) int {
+	return max(param(params, i, 1), 1)
+}
+
+func (t *Terminal) execute(r rune) {
+	switch r {
+	case '\b':
+		if t.cursor.x > 0 {
+			t.cursor.x--
+		}
+		t.cursor.wrapPending = false
+	case '\t':
+		t.tab()
+	case '\n', '\v', '\f':
+		t.lineFeed()
+		t.cursor.wrapPending = false
+	case '\r':
+		t.cursor.x = 0
+		t.cursor.wrapPending = false
+	}
+}
+
+func (t *Terminal) dispatchCSI(private rune, params [][]int, final rune) {
+	if private == '?' {
+		switch final {
+		case 'h':
+			t.setModes(params, true)
+		case 'l':
+			t.setModes(params, false)
+		}
+
+		return
+	}
+
+	if private != 0 {
+		return
+	}
+
+	x, y := t.cursor.x, t.cursor.y
+	switch final {
+	case '@':
+		t.insertCells(count(params, 0))
+	case 'A':
+		t.moveTo(x, max(y-count(params, 0), min(t.top, y)))
+	case 'B', 'e':
+		t.moveTo(x, min(y+count(params, 0), max(t.bottom, y)))
+	case 'C', 'a':
+		t.moveTo(x+count(params, 0), y)
+	case 'D':
+		t.moveTo(x-count(params, 0), y)
+	case 'E':
+		t.moveTo(0, y+count(params, 0))
+	case 'F':
+		t.moveTo(0, y-count(params, 0))
+	case 'G', '`':
+		t.moveTo(count(params, 0)-1, y)
+	case 'H', 'f':
+		t.moveTo(count(params, 1)-1, count(params, 0)-1)
+	case 'I':
+		for i := 0; i < count(params, 0); i++ {
+			t.tab()
+		}
+	case 'J':
+		t.eraseInDisplay(param(params, 0, 0))
+	case 'K':
+		t.eraseInLine(param(params, 0, 0))
+	case 'L':
+		t.insertLines(count(params, 0))
+	case 'M':
+		t.deleteLines(count(params, 0))
+	case 'P':
+		t.deleteCells(count(params, 0))
+	case 'S':
+		t.scrollUp(count(params, 0))
+	case 'T':
+		t.scrollDown(count(params, 0))
+	case 'X':
+		t.eraseCells(y, x, x+count(params, 0))
+	case 'd':
+		t.moveTo(x, count(params, 0)-1)
+	case 'm':
+		t.setAttr(params)
+	case 'r':
+		t.setScrollRegion(param(params, 0, 1), param(params, 1, t.height))
+	case 's':
+		t.saveCursor()
+	case 't':
+		if param(params, 0, 0) == 8 {
+			t.Resize(param(params, 2, t.width), param(params, 1, t.height))
+		}
+	case 'u':
+		t.restoreCursor()
+	}
+}
+
+func (t *Terminal) setModes(params [][]int, on bool) {
+	for i := range params {
+		switch params[i][0] {
+		case 7:
+			t.autowrap = on
+		case 25:
+			t.cursorVisible = on
+		case 47, 1047:
+			t.useAlternate(on, on)
+		case 1048:
+			if on {
+				t.saveCursor()
+			} else {
+				t.restoreCursor()
+			}
+		case 1049:
+			if on {
+				t.saveCursor()
+				t.useAlternate(true, true)
+			} else {
+				t.useAlternate(false, false)
+				t.restoreCursor()
+			}
+		}
+	}
+}
+
+func (t *Terminal) setAttr(params [][]int) {
+	a := &t.cursor.attr
+	if len(params) == 0 {
+		*a = Attr{}
+		return
+	}
+
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch n := p[0]; {
+		case n <= 0:
+			*a = Attr{}
+		case n == 1:
+			a.Flags |= FlagBold
+		case n == 2:
+			a.Flags |= FlagFaint
+		case n == 3:
+			a.Flags |= FlagItalic
+		case n == 4:
+			if len(p) > 1 && p[1] == 0 {
+				a.Flags &^= FlagUnderline
+			} else {
+				a.Flags |= FlagUnderline
+			}
+		case n == 5, n == 6:
+			a.Flags |= FlagBlink
+		case n == 7:
+			a.Flags |= FlagInverse
+		case n == 8:
+			a.Flags |= FlagInvisible
+		case n == 9:
+			a.Flags |= FlagStrike
+		case n == 21:
+			a.Flags |= FlagUnderline
+		case n == 22:
+			a.Flags &^= FlagBold | FlagFaint
+		case n == 23:
+			a.Flags &^= FlagItalic
+		case n == 24:
+			a.Flags &^= FlagUnderline
+		case n == 25:
+			a.Flags &^= FlagBlink
+		case n == 27:
+			a.Flags &^= FlagInverse
+		case n == 28:
+			a.Flags &^= FlagInvisible
+		case n == 29:
+			a.Flags &^= FlagStrike
+		case n >= 30 && n <= 37:
+			a.FG = Color{Kind: ColorIndexed, Index: uint8(n - 30)}
+		case n == 38:
+			var c Color
+			c, i = extendedColor(params, i)
+			a.FG = c
+		case n == 39:
+			a.FG = Color{}
+		case n >= 40 && n <= 47:
+			a.BG = Color{Kind: ColorIndexed, Index: uint8(n - 40)}
+		case n == 48:
+			var c Color
+			c, i = extendedColor(params, i)
+			a.BG = c
+		case n == 49:
+			a.BG = Color{}
+		case n >= 90 && n <= 97:
+			a.FG = Color{Kind: ColorIndexed, Index: uint8(n - 90 + 8)}
+		case n >= 100 && n <= 107:
+			a.BG = Color{Kind: ColorIndexed, Index: uint8(n - 100 + 8)}
+		}
+	}
+}
+
+// extendedColor parses 38/48 colors in both the semicolon (38;5;n) and the
+// colon (38:5:n, 38:2::r:g:b) forms, and returns the index of the last
+// parameter consumed.
+func extendedColor(params [][]int, i int) (Color, int) {
+	values := params[i][1:]
+	next := i
+	if len(values) == 0 {
+		for j := i + 1; j < len(params); j++ {
+			values = append(values, params[j][0])
+		}
+	}
+
+	if len(values) == 0 {
+		return Color{}, i
+	}
+
+	switch values[0] {
+	case 5:
+		if len(values) < 2 {
+			return Color{}, len(params)
+		}
+
+		if len(params[i]) == 1 {
+			next = i + 2
+		}
+
+		return Color{Kind: ColorIndexed, Index: uint8(clamp(values[1], 0, 255))}, next
+	case 2:
+		rgb := values[1:]
+		// NOTE: the colon form may carry a color space id before the components
+		if len(params[i]) > 1 && len(rgb) == 4 {
+			rgb = rgb[1:]
+		}
+
+		if len(rgb) < 3 {
+			return Color{}, len(params)
+		}
+
+		if len(params[i]) == 1 {
+			next = i + 4
+		}
+
+		return Color{
+			Kind: ColorRGB,
+			R:    uint8(clamp(rgb[0], 0, 255)),
+			G:    uint8(clamp(rgb[1], 0, 255)),
+			B:    uint8(clamp(rgb[2], 0, 255)),
+		}, next
+	}
+
+	return Color{}, i
+}
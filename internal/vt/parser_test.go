@@ -0,0 +1,161 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseParams(t *testing.T) {
+	type args struct {
+		s string
+	}
+
+	type expected struct {
+		params [][]int
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: empty",
+			args:     &args{s: ""},
+			expected: &expected{params: nil},
+		},
+		{
+			name:     "happy path: semicolons",
+			args:     &args{s: "1;22;333"},
+			expected: &expected{params: [][]int{{1}, {22}, {333}}},
+		},
+		{
+			name:     "happy path: omitted",
+			args:     &args{s: ";5"},
+			expected: &expected{params: [][]int{{-1}, {5}}},
+		},
+		{
+			name:     "happy path: colons",
+			args:     &args{s: "38:2::1:2:3;4"},
+			expected: &expected{params: [][]int{{38, 2, -1, 1, 2, 3}, {4}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := parseParams(tt.args.s)
+
+			// Assert
+			assert.Equal(t, tt.expected.params, actual)
+		})
+	}
+}
+
+func TestTerminal_setAttr(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		attr Attr
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: basic colors",
+			args: &args{data: "\x1b[1;31;42m"},
+			expected: &expected{attr: Attr{
+				FG:    Color{Kind: ColorIndexed, Index: 1},
+				BG:    Color{Kind: ColorIndexed, Index: 2},
+				Flags: FlagBold,
+			}},
+		},
+		{
+			name: "happy path: bright colors",
+			args: &args{data: "\x1b[97;100m"},
+			expected: &expected{attr: Attr{
+				FG: Color{Kind: ColorIndexed, Index: 15},
+				BG: Color{Kind: ColorIndexed, Index: 8},
+			}},
+		},
+		{
+			name: "happy path: 256 colors",
+			args: &args{data: "\x1b[38;5;196;4m"},
+			expected: &expected{attr: Attr{
+				FG:    Color{Kind: ColorIndexed, Index: 196},
+				Flags: FlagUnderline,
+			}},
+		},
+		{
+			name: "happy path: true colors",
+			args: &args{data: "\x1b[48;2;1;2;3;7m"},
+			expected: &expected{attr: Attr{
+				BG:    Color{Kind: ColorRGB, R: 1, G: 2, B: 3},
+				Flags: FlagInverse,
+			}},
+		},
+		{
+			name: "happy path: colon true colors",
+			args: &args{data: "\x1b[38:2::10:20:30m"},
+			expected: &expected{attr: Attr{
+				FG: Color{Kind: ColorRGB, R: 10, G: 20, B: 30},
+			}},
+		},
+		{
+			name:     "happy path: reset",
+			args:     &args{data: "\x1b[1;31m\x1b[m"},
+			expected: &expected{attr: Attr{}},
+		},
+		{
+			name: "happy path: partial reset",
+			args: &args{data: "\x1b[1;3;31m\x1b[22;39m"},
+			expected: &expected{attr: Attr{
+				Flags: FlagItalic,
+			}},
+		},
+		{
+			name:     "edge path: truncated extended color",
+			args:     &args{data: "\x1b[38;5m"},
+			expected: &expected{attr: Attr{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			term := New(4, 1)
+
+			// Act
+			term.WriteString(tt.args.data)
+
+			// Assert
+			assert.Equal(t, tt.expected.attr, term.cursor.attr)
+		})
+	}
+}
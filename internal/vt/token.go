@@ -0,0 +1,122 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type TokenKind int
+
+const (
+	// TokenText is a run of printable characters.
+	TokenText TokenKind = iota
+	// TokenControl is a single C0 or C1 control character.
+	TokenControl
+	// TokenEscape is an escape sequence such as ESC 7 or ESC ( B.
+	TokenEscape
+	// TokenCSI is a control sequence such as ESC [ 1 ; 3 2 m.
+	TokenCSI
+	// TokenString is an OSC, DCS, SOS, PM or APC string with its terminator.
+	TokenString
+)
+
+type Token struct {
+	Kind TokenKind
+	Raw  string
+}
+
+// Tokens splits s into text runs, control characters and complete escape
+// sequences. An escape sequence cut off at the end of s is returned as rest,
+// so that it can be completed by the following data.
+func Tokens(s string) (tokens []Token, rest string) {
+	tokens = make([]Token, 0)
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		switch {
+		case r == 0x1b:
+			kind, n := escapeLength(s)
+			if n == 0 {
+				return tokens, s
+			}
+
+			tokens = append(tokens, Token{Kind: kind, Raw: s[:n]})
+			s = s[n:]
+		case isControl(r):
+			tokens = append(tokens, Token{Kind: TokenControl, Raw: s[:size]})
+			s = s[size:]
+		default:
+			n := strings.IndexFunc(s, isControl)
+			if n < 0 {
+				n = len(s)
+			}
+
+			tokens = append(tokens, Token{Kind: TokenText, Raw: s[:n]})
+			s = s[n:]
+		}
+	}
+
+	return tokens, ""
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r < 0xa0)
+}
+
+// escapeLength returns the kind and length of the escape sequence at the start
+// of s, or zero if s ends before the sequence does.
+func escapeLength(s string) (TokenKind, int) {
+	if len(s) < 2 {
+		return TokenEscape, 0
+	}
+
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return TokenCSI, i + 1
+			}
+		}
+
+		return TokenCSI, 0
+	case ']', 'P', 'X', '^', '_':
+		for i := 2; i < len(s); i++ {
+			switch {
+			case s[i] == 0x07:
+				return TokenString, i + 1
+			case s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\':
+				return TokenString, i + 2
+			}
+		}
+
+		return TokenString, 0
+	}
+
+	// NOTE: intermediates from 0x20 to 0x2f followed by a final byte
+	for i := 1; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x2f {
+			return TokenEscape, i + 1
+		}
+	}
+
+	return TokenEscape, 0
+}
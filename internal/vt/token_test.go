@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokens(t *testing.T) {
+	type args struct {
+		s string
+	}
+
+	type expected struct {
+		tokens []Token
+		rest   string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: text and controls",
+			args: &args{s: "héllo\r\nあ"},
+			expected: &expected{
+				tokens: []Token{
+					{Kind: TokenText, Raw: "héllo"},
+					{Kind: TokenControl, Raw: "\r"},
+					{Kind: TokenControl, Raw: "\n"},
+					{Kind: TokenText, Raw: "あ"},
+				},
+			},
+		},
+		{
+			name: "happy path: sequences",
+			args: &args{s: "\x1b[1;32mok\x1b[m\x1b(B\x1b7\x1b]0;title\x07\x1bPq\x1b\\"},
+			expected: &expected{
+				tokens: []Token{
+					{Kind: TokenCSI, Raw: "\x1b[1;32m"},
+					{Kind: TokenText, Raw: "ok"},
+					{Kind: TokenCSI, Raw: "\x1b[m"},
+					{Kind: TokenEscape, Raw: "\x1b(B"},
+					{Kind: TokenEscape, Raw: "\x1b7"},
+					{Kind: TokenString, Raw: "\x1b]0;title\x07"},
+					{Kind: TokenString, Raw: "\x1bPq\x1b\\"},
+				},
+			},
+		},
+		{
+			name: "happy path: incomplete csi",
+			args: &args{s: "a\x1b[1;3"},
+			expected: &expected{
+				tokens: []Token{{Kind: TokenText, Raw: "a"}},
+				rest:   "\x1b[1;3",
+			},
+		},
+		{
+			name: "happy path: incomplete osc",
+			args: &args{s: "\x1b]0;tit"},
+			expected: &expected{
+				tokens: []Token{},
+				rest:   "\x1b]0;tit",
+			},
+		},
+		{
+			name: "happy path: lone escape",
+			args: &args{s: "a\x1b"},
+			expected: &expected{
+				tokens: []Token{{Kind: TokenText, Raw: "a"}},
+				rest:   "\x1b",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			tokens, rest := Tokens(tt.args.s)
+
+			// Assert
+			assert.Equal(t, tt.expected.tokens, tokens)
+			assert.Equal(t, tt.expected.rest, rest)
+		})
+	}
+}
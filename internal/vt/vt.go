@@ -0,0 +1,501 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"strings"
+)
+
+type ColorKind uint8
+
+const (
+	ColorDefault ColorKind = iota
+	ColorIndexed
+	ColorRGB
+)
+
+type Color struct {
+	Kind    ColorKind
+	Index   uint8
+	R, G, B uint8
+}
+
+type Flag uint16
+
+const (
+	FlagBold Flag = 1 << iota
+	FlagFaint
+	FlagItalic
+	FlagUnderline
+	FlagBlink
+	FlagInverse
+	FlagInvisible
+	FlagStrike
+)
+
+type Attr struct {
+	FG    Color
+	BG    Color
+	Flags Flag
+}
+
+// Cell is a character cell; a wide rune occupies its cell with Width 2 and the
+// following cell with Width 0.
+type Cell struct {
+	Rune  rune
+	Width int8
+	Attr  Attr
+}
+
+type Screen struct {
+	Width         int
+	Height        int
+	Cells         []Cell
+	CursorX       int
+	CursorY       int
+	CursorVisible bool
+}
+
+func (s *Screen) Cell(x, y int) Cell {
+	return s.Cells[y*s.Width+x]
+}
+
+// Line returns the text of row y without trailing blanks.
+func (s *Screen) Line(y int) string {
+	return lineText(s.Cells[y*s.Width : (y+1)*s.Width])
+}
+
+func lineText(cells []Cell) string {
+	var b strings.Builder
+	for _, c := range cells {
+		if c.Width > 0 {
+			b.WriteRune(c.Rune)
+		}
+	}
+
+	return strings.TrimRight(b.String(), " ")
+}
+
+func (s *Screen) Equal(o *Screen) bool {
+	if s.Width != o.Width || s.Height != o.Height {
+		return false
+	}
+
+	if s.CursorVisible != o.CursorVisible {
+		return false
+	}
+
+	if s.CursorVisible && (s.CursorX != o.CursorX || s.CursorY != o.CursorY) {
+		return false
+	}
+
+	for i := range s.Cells {
+		if s.Cells[i] != o.Cells[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type cursor struct {
+	x, y        int
+	attr        Attr
+	wrapPending bool
+	lineDrawing bool
+}
+
+type Terminal struct {
+	width, height int
+	primary       [][]Cell
+	alternate     [][]Cell
+	lines         [][]Cell
+	altActive     bool
+	cursor        cursor
+	saved         cursor
+	top, bottom   int
+	cursorVisible bool
+	autowrap      bool
+
+	parser parser
+}
+
+func New(width, height int) *Terminal {
+	t := &Terminal{
+		width:  width,
+		height: height,
+	}
+	t.reset()
+
+	return t
+}
+
+func (t *Terminal) reset() {
+	t.primary = newLines(t.width, t.height)
+	t.alternate = newLines(t.width, t.height)
+	t.lines = t.primary
+	t.altActive = false
+	t.cursor = cursor{}
+	t.saved = cursor{}
+	t.top, t.bottom = 0, t.height-1
+	t.cursorVisible = true
+	t.autowrap = true
+	t.parser = parser{}
+}
+
+func newLines(width, height int) [][]Cell {
+	lines := make([][]Cell, 0, height)
+	for i := 0; i < height; i++ {
+		lines = append(lines, newLine(width, Attr{}))
+	}
+
+	return lines
+}
+
+func newLine(width int, attr Attr) []Cell {
+	line := make([]Cell, 0, width)
+	for i := 0; i < width; i++ {
+		line = append(line, blank(attr))
+	}
+
+	return line
+}
+
+// NOTE: erased cells keep the current background (bce)
+func blank(attr Attr) Cell {
+	return Cell{Rune: ' ', Width: 1, Attr: Attr{BG: attr.BG}}
+}
+
+func (t *Terminal) Size() (width, height int) {
+	return t.width, t.height
+}
+
+func (t *Terminal) Resize(width, height int) {
+	if width <= 0 || height <= 0 || (width == t.width && height == t.height) {
+		return
+	}
+
+	t.primary = resizeLines(t.primary, width, height)
+	t.alternate = resizeLines(t.alternate, width, height)
+	t.width, t.height = width, height
+	if t.altActive {
+		t.lines = t.alternate
+	} else {
+		t.lines = t.primary
+	}
+
+	t.top, t.bottom = 0, height-1
+	t.cursor.x = min(t.cursor.x, width-1)
+	t.cursor.y = min(t.cursor.y, height-1)
+	t.cursor.wrapPending = false
+	t.saved.x = min(t.saved.x, width-1)
+	t.saved.y = min(t.saved.y, height-1)
+}
+
+func resizeLines(lines [][]Cell, width, height int) [][]Cell {
+	resized := make([][]Cell, 0, height)
+	for y := 0; y < height; y++ {
+		line := newLine(width, Attr{})
+		if y < len(lines) {
+			copy(line, lines[y])
+			// NOTE: drop a wide rune cut in half by the right edge
+			if last := line[width-1]; last.Width == 2 {
+				line[width-1] = blank(Attr{})
+			}
+		}
+
+		resized = append(resized, line)
+	}
+
+	return resized
+}
+
+func (t *Terminal) Snapshot() *Screen {
+	cells := make([]Cell, 0, t.width*t.height)
+	for _, line := range t.lines {
+		cells = append(cells, line...)
+	}
+
+	return &Screen{
+		Width:         t.width,
+		Height:        t.height,
+		Cells:         cells,
+		CursorX:       t.cursor.x,
+		CursorY:       t.cursor.y,
+		CursorVisible: t.cursorVisible,
+	}
+}
+
+// CursorLine returns the text of the cursor row without trailing blanks.
+func (t *Terminal) CursorLine() string {
+	return lineText(t.lines[t.cursor.y])
+}
+
+func (t *Terminal) WriteString(s string) {
+	for _, r := range s {
+		t.parser.feed(t, r)
+	}
+}
+
+func (t *Terminal) Write(p []byte) (int, error) {
+	t.WriteString(string(p))
+
+	return len(p), nil
+}
+
+func (t *Terminal) print(r rune) {
+	if t.cursor.lineDrawing {
+		if m, ok := lineDrawing[r]; ok {
+			r = m
+		}
+	}
+
+	w := RuneWidth(r)
+	if w == 0 {
+		return
+	}
+
+	// NOTE: a wide rune never fits a single column, so it shows as a blank
+	if w == 2 && t.width < 2 {
+		r, w = ' ', 1
+	}
+
+	if t.cursor.wrapPending && t.autowrap {
+		t.cursor.x = 0
+		t.lineFeed()
+	}
+	t.cursor.wrapPending = false
+
+	if w == 2 && t.cursor.x == t.width-1 {
+		if !t.autowrap {
+			return
+		}
+
+		t.put(t.cursor.x, blank(t.cursor.attr))
+		t.cursor.x = 0
+		t.lineFeed()
+	}
+
+	t.put(t.cursor.x, Cell{Rune: r, Width: int8(w), Attr: t.cursor.attr})
+	if w == 2 {
+		t.put(t.cursor.x+1, Cell{Width: 0, Attr: t.cursor.attr})
+	}
+
+	t.cursor.x += w
+	if t.cursor.x >= t.width {
+		t.cursor.x = t.width - 1
+		t.cursor.wrapPending = true
+	}
+}
+
+// put writes c at column x of the cursor line, clearing any wide rune it splits.
+func (t *Terminal) put(x int, c Cell) {
+	line := t.lines[t.cursor.y]
+	if old := line[x]; old.Width == 0 && x > 0 && c.Width != 0 {
+		line[x-1] = blank(line[x-1].Attr)
+	} else if old.Width == 2 && x+1 < t.width && c.Width != 2 {
+		line[x+1] = blank(line[x+1].Attr)
+	}
+
+	line[x] = c
+}
+
+func (t *Terminal) lineFeed() {
+	switch {
+	case t.cursor.y == t.bottom:
+		t.scrollUp(1)
+	case t.cursor.y < t.height-1:
+		t.cursor.y++
+	}
+}
+
+func (t *Terminal) reverseIndex() {
+	switch {
+	case t.cursor.y == t.top:
+		t.scrollDown(1)
+	case t.cursor.y > 0:
+		t.cursor.y--
+	}
+}
+
+func (t *Terminal) scrollUp(n int) {
+	t.scrollRegionUp(t.top, t.bottom, n)
+}
+
+func (t *Terminal) scrollDown(n int) {
+	t.scrollRegionDown(t.top, t.bottom, n)
+}
+
+func (t *Terminal) scrollRegionUp(top, bottom, n int) {
+	n = min(n, bottom-top+1)
+	copy(t.lines[top:bottom+1], t.lines[top+n:bottom+1])
+	for y := bottom - n + 1; y <= bottom; y++ {
+		t.lines[y] = newLine(t.width, t.cursor.attr)
+	}
+}
+
+func (t *Terminal) scrollRegionDown(top, bottom, n int) {
+	n = min(n, bottom-top+1)
+	copy(t.lines[top+n:bottom+1], t.lines[top:bottom+1-n])
+	for y := top; y < top+n; y++ {
+		t.lines[y] = newLine(t.width, t.cursor.attr)
+	}
+}
+
+func (t *Terminal) eraseCells(y, from, to int) {
+	line := t.lines[y]
+	from = max(from, 0)
+	to = min(to, t.width)
+	for x := from; x < to; x++ {
+		line[x] = blank(t.cursor.attr)
+	}
+
+	// NOTE: never leave half of a wide rune behind
+	if from > 0 && from < t.width && line[from-1].Width == 2 {
+		line[from-1] = blank(t.cursor.attr)
+	}
+
+	if to < t.width && line[to].Width == 0 {
+		line[to] = blank(t.cursor.attr)
+	}
+}
+
+func (t *Terminal) eraseInDisplay(mode int) {
+	switch mode {
+	case 0:
+		t.eraseCells(t.cursor.y, t.cursor.x, t.width)
+		for y := t.cursor.y + 1; y < t.height; y++ {
+			t.eraseCells(y, 0, t.width)
+		}
+	case 1:
+		for y := 0; y < t.cursor.y; y++ {
+			t.eraseCells(y, 0, t.width)
+		}
+		t.eraseCells(t.cursor.y, 0, t.cursor.x+1)
+	case 2, 3:
+		for y := 0; y < t.height; y++ {
+			t.eraseCells(y, 0, t.width)
+		}
+	}
+}
+
+func (t *Terminal) eraseInLine(mode int) {
+	switch mode {
+	case 0:
+		t.eraseCells(t.cursor.y, t.cursor.x, t.width)
+	case 1:
+		t.eraseCells(t.cursor.y, 0, t.cursor.x+1)
+	case 2:
+		t.eraseCells(t.cursor.y, 0, t.width)
+	}
+}
+
+func (t *Terminal) insertCells(n int) {
+	line := t.lines[t.cursor.y]
+	x := t.cursor.x
+	n = min(n, t.width-x)
+	copy(line[x+n:], line[x:t.width-n])
+	t.eraseCells(t.cursor.y, x, x+n)
+}
+
+func (t *Terminal) deleteCells(n int) {
+	line := t.lines[t.cursor.y]
+	x := t.cursor.x
+	n = min(n, t.width-x)
+	copy(line[x:], line[x+n:])
+	t.eraseCells(t.cursor.y, t.width-n, t.width)
+}
+
+func (t *Terminal) insertLines(n int) {
+	if t.cursor.y < t.top || t.cursor.y > t.bottom {
+		return
+	}
+
+	t.scrollRegionDown(t.cursor.y, t.bottom, n)
+	t.cursor.x = 0
+}
+
+func (t *Terminal) deleteLines(n int) {
+	if t.cursor.y < t.top || t.cursor.y > t.bottom {
+		return
+	}
+
+	t.scrollRegionUp(t.cursor.y, t.bottom, n)
+	t.cursor.x = 0
+}
+
+func (t *Terminal) moveTo(x, y int) {
+	t.cursor.x = clamp(x, 0, t.width-1)
+	t.cursor.y = clamp(y, 0, t.height-1)
+	t.cursor.wrapPending = false
+}
+
+func (t *Terminal) tab() {
+	x := (t.cursor.x/8 + 1) * 8
+	t.cursor.x = min(x, t.width-1)
+}
+
+func (t *Terminal) saveCursor() {
+	t.saved = t.cursor
+}
+
+func (t *Terminal) restoreCursor() {
+	t.cursor = t.saved
+	t.cursor.x = min(t.cursor.x, t.width-1)
+	t.cursor.y = min(t.cursor.y, t.height-1)
+}
+
+func (t *Terminal) useAlternate(on, clear bool) {
+	if on == t.altActive {
+		return
+	}
+
+	t.altActive = on
+
+	if on {
+		t.lines = t.alternate
+		if clear {
+			for y := range t.lines {
+				t.lines[y] = newLine(t.width, Attr{})
+			}
+		}
+	} else {
+		t.lines = t.primary
+	}
+}
+
+func (t *Terminal) setScrollRegion(top, bottom int) {
+	if bottom <= 0 || bottom > t.height {
+		bottom = t.height
+	}
+
+	top = max(top, 1)
+	if top >= bottom {
+		return
+	}
+
+	t.top, t.bottom = top-1, bottom-1
+	t.moveTo(0, 0)
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
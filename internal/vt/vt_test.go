@@ -0,0 +1,376 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func screenText(s *Screen) []string {
+	lines := make([]string, 0, s.Height)
+	for y := 0; y < s.Height; y++ {
+		lines = append(lines, s.Line(y))
+	}
+
+	return lines
+}
+
+func TestTerminal(t *testing.T) {
+	type args struct {
+		width  int
+		height int
+		data   []string
+	}
+
+	type expected struct {
+		lines   []string
+		cursorX int
+		cursorY int
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: print",
+			args: &args{width: 10, height: 3, data: []string{"hello\r\nworld"}},
+			expected: &expected{
+				lines:   []string{"hello", "world", ""},
+				cursorX: 5,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: autowrap",
+			args: &args{width: 4, height: 3, data: []string{"abcdefg"}},
+			expected: &expected{
+				lines:   []string{"abcd", "efg", ""},
+				cursorX: 3,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: pending wrap",
+			args: &args{width: 4, height: 3, data: []string{"abcd\r\nef"}},
+			expected: &expected{
+				lines:   []string{"abcd", "ef", ""},
+				cursorX: 2,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: scroll",
+			args: &args{width: 4, height: 2, data: []string{"a\r\nb\r\nc"}},
+			expected: &expected{
+				lines:   []string{"b", "c"},
+				cursorX: 1,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: scroll region",
+			args: &args{width: 4, height: 4, data: []string{"a\r\nb\r\nc\r\nd", "\x1b[2;3r\x1b[3;1H\nx"}},
+			expected: &expected{
+				lines:   []string{"a", "c", "x", "d"},
+				cursorX: 1,
+				cursorY: 2,
+			},
+		},
+		{
+			name: "happy path: cursor position and erase",
+			args: &args{width: 6, height: 2, data: []string{"abcdef\r\nghijkl", "\x1b[1;3H\x1b[K\x1b[2;2H\x1b[1K"}},
+			expected: &expected{
+				lines:   []string{"ab", "  ijkl"},
+				cursorX: 1,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: erase display",
+			args: &args{width: 4, height: 2, data: []string{"ab\r\ncd", "\x1b[2J"}},
+			expected: &expected{
+				lines:   []string{"", ""},
+				cursorX: 2,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: insert and delete",
+			args: &args{width: 6, height: 1, data: []string{"abcdef", "\x1b[1;2H\x1b[2@\x1b[1;5H\x1b[P"}},
+			expected: &expected{
+				lines:   []string{"a  bd"},
+				cursorX: 4,
+				cursorY: 0,
+			},
+		},
+		{
+			name: "happy path: wide rune",
+			args: &args{width: 5, height: 2, data: []string{"aあいう"}},
+			expected: &expected{
+				lines:   []string{"aあい", "う"},
+				cursorX: 2,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "edge path: wide rune on single column",
+			args: &args{width: 1, height: 2, data: []string{"あb"}},
+			expected: &expected{
+				lines:   []string{"", "b"},
+				cursorX: 0,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: overwrite half of wide rune",
+			args: &args{width: 5, height: 1, data: []string{"あい", "\x1b[1;2Hx"}},
+			expected: &expected{
+				lines:   []string{" xい"},
+				cursorX: 2,
+				cursorY: 0,
+			},
+		},
+		{
+			name: "happy path: alternate screen",
+			args: &args{width: 4, height: 2, data: []string{"ab", "\x1b[?1049hxy", "\x1b[?1049l"}},
+			expected: &expected{
+				lines:   []string{"ab", ""},
+				cursorX: 2,
+				cursorY: 0,
+			},
+		},
+		{
+			name: "happy path: line drawing",
+			args: &args{width: 4, height: 1, data: []string{"\x1b(0lqk\x1b(Bq"}},
+			expected: &expected{
+				lines:   []string{"┌─┐q"},
+				cursorX: 3,
+				cursorY: 0,
+			},
+		},
+		{
+			name: "happy path: ignore strings",
+			args: &args{width: 4, height: 1, data: []string{"\x1b]0;title\x07a\x1bPqdata\x1b\\b"}},
+			expected: &expected{
+				lines:   []string{"ab"},
+				cursorX: 2,
+				cursorY: 0,
+			},
+		},
+		{
+			name: "happy path: sequence split across writes",
+			args: &args{width: 4, height: 2, data: []string{"ab\x1b[", "2;1", "Hc"}},
+			expected: &expected{
+				lines:   []string{"ab", "c"},
+				cursorX: 1,
+				cursorY: 1,
+			},
+		},
+		{
+			name: "happy path: resize sequence",
+			args: &args{width: 4, height: 2, data: []string{"abcd", "\x1b[8;3;2t"}},
+			expected: &expected{
+				lines:   []string{"ab", "", ""},
+				cursorX: 1,
+				cursorY: 0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			term := New(tt.args.width, tt.args.height)
+
+			// Act
+			for _, d := range tt.args.data {
+				term.WriteString(d)
+			}
+			s := term.Snapshot()
+
+			// Assert
+			assert.Equal(t, tt.expected.lines, screenText(s))
+			assert.Equal(t, tt.expected.cursorX, s.CursorX)
+			assert.Equal(t, tt.expected.cursorY, s.CursorY)
+		})
+	}
+}
+
+func TestTerminal_Resize(t *testing.T) {
+	type args struct {
+		data   string
+		width  int
+		height int
+	}
+
+	type expected struct {
+		lines []string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: grow",
+			args:     &args{data: "ab\r\ncd", width: 4, height: 3},
+			expected: &expected{lines: []string{"ab", "cd", ""}},
+		},
+		{
+			name:     "happy path: shrink",
+			args:     &args{data: "ab\r\ncd", width: 1, height: 1},
+			expected: &expected{lines: []string{"a"}},
+		},
+		{
+			name:     "happy path: cut wide rune",
+			args:     &args{data: "aあ", width: 2, height: 2},
+			expected: &expected{lines: []string{"a", ""}},
+		},
+		{
+			name:     "edge path: invalid size",
+			args:     &args{data: "ab", width: 0, height: 2},
+			expected: &expected{lines: []string{"ab", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			term := New(3, 2)
+			term.WriteString(tt.args.data)
+
+			// Act
+			term.Resize(tt.args.width, tt.args.height)
+
+			// Assert
+			assert.Equal(t, tt.expected.lines, screenText(term.Snapshot()))
+		})
+	}
+}
+
+func TestScreen_Equal(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+
+	type expected struct {
+		equal bool
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: same",
+			args:     &args{a: "ab", b: "ab"},
+			expected: &expected{equal: true},
+		},
+		{
+			name:     "happy path: different text",
+			args:     &args{a: "ab", b: "ac"},
+			expected: &expected{equal: false},
+		},
+		{
+			name:     "happy path: different cursor",
+			args:     &args{a: "ab", b: "ab\x1b[D"},
+			expected: &expected{equal: false},
+		},
+		{
+			name:     "happy path: hidden cursor",
+			args:     &args{a: "\x1b[?25lab", b: "\x1b[?25lab\x1b[D"},
+			expected: &expected{equal: true},
+		},
+		{
+			name:     "happy path: different attribute",
+			args:     &args{a: "ab", b: "a\x1b[1mb"},
+			expected: &expected{equal: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			a := New(4, 1)
+			a.WriteString(tt.args.a)
+			b := New(4, 1)
+			b.WriteString(tt.args.b)
+
+			// Act
+			actual := a.Snapshot().Equal(b.Snapshot())
+
+			// Assert
+			assert.Equal(t, tt.expected.equal, actual)
+		})
+	}
+}
+
+func TestTerminal_CursorLine(t *testing.T) {
+	type args struct {
+		data string
+	}
+
+	type expected struct {
+		line string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: first line",
+			args:     &args{data: "$ ls"},
+			expected: &expected{line: "$ ls"},
+		},
+		{
+			name:     "happy path: redrawn line",
+			args:     &args{data: "a\r\n$ lx\bs \b"},
+			expected: &expected{line: "$ ls"},
+		},
+		{
+			name:     "happy path: colored",
+			args:     &args{data: "\x1b[32m$\x1b[m ls"},
+			expected: &expected{line: "$ ls"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			term := New(10, 2)
+
+			// Act
+			term.WriteString(tt.args.data)
+
+			// Assert
+			assert.Equal(t, tt.expected.line, term.CursorLine())
+		})
+	}
+}
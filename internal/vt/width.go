@@ -0,0 +1,128 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"unicode"
+)
+
+var (
+	// NOTE: East Asian Wide and Fullwidth ranges, and emoji presented as wide
+	wideRanges = [][2]rune{
+		{0x1100, 0x115f},
+		{0x231a, 0x231b},
+		{0x2329, 0x232a},
+		{0x23e9, 0x23ec},
+		{0x23f0, 0x23f0},
+		{0x23f3, 0x23f3},
+		{0x25fd, 0x25fe},
+		{0x2614, 0x2615},
+		{0x2648, 0x2653},
+		{0x26a1, 0x26a1},
+		{0x26aa, 0x26ab},
+		{0x26bd, 0x26be},
+		{0x26c4, 0x26c5},
+		{0x26d4, 0x26d4},
+		{0x26ea, 0x26ea},
+		{0x26f2, 0x26f5},
+		{0x26fa, 0x26fd},
+		{0x2705, 0x2705},
+		{0x270a, 0x270b},
+		{0x2728, 0x2728},
+		{0x274c, 0x274c},
+		{0x2753, 0x2755},
+		{0x2757, 0x2757},
+		{0x2795, 0x2797},
+		{0x27b0, 0x27b0},
+		{0x27bf, 0x27bf},
+		{0x2b1b, 0x2b1c},
+		{0x2b50, 0x2b50},
+		{0x2b55, 0x2b55},
+		{0x2e80, 0x303e},
+		{0x3041, 0x33ff},
+		{0x3400, 0x4dbf},
+		{0x4e00, 0x9fff},
+		{0xa000, 0xa4cf},
+		{0xa960, 0xa97f},
+		{0xac00, 0xd7a3},
+		{0xf900, 0xfaff},
+		{0xfe10, 0xfe19},
+		{0xfe30, 0xfe6f},
+		{0xff00, 0xff60},
+		{0xffe0, 0xffe6},
+		{0x16fe0, 0x16fe4},
+		{0x17000, 0x18cff},
+		{0x1b000, 0x1b2ff},
+		{0x1f004, 0x1f004},
+		{0x1f0cf, 0x1f0cf},
+		{0x1f18e, 0x1f18e},
+		{0x1f191, 0x1f19a},
+		{0x1f200, 0x1f251},
+		{0x1f300, 0x1f64f},
+		{0x1f680, 0x1f6ff},
+		{0x1f7e0, 0x1f7eb},
+		{0x1f90c, 0x1f9ff},
+		{0x1fa70, 0x1faff},
+		{0x20000, 0x3fffd},
+	}
+)
+
+// RuneWidth returns the number of cells that r occupies on a terminal.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x1100:
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			return 0
+		}
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	lo, hi := 0, len(wideRanges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return 2
+		}
+	}
+
+	return 1
+}
+
+// StringWidth returns the number of cells that s occupies on a terminal.
+func StringWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += RuneWidth(r)
+	}
+
+	return n
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package vt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuneWidth(t *testing.T) {
+	type args struct {
+		r rune
+	}
+
+	type expected struct {
+		width int
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: ascii",
+			args:     &args{r: 'a'},
+			expected: &expected{width: 1},
+		},
+		{
+			name:     "happy path: control",
+			args:     &args{r: '\x1b'},
+			expected: &expected{width: 0},
+		},
+		{
+			name:     "happy path: combining mark",
+			args:     &args{r: '́'},
+			expected: &expected{width: 0},
+		},
+		{
+			name:     "happy path: hiragana",
+			args:     &args{r: 'あ'},
+			expected: &expected{width: 2},
+		},
+		{
+			name:     "happy path: emoji",
+			args:     &args{r: '😀'},
+			expected: &expected{width: 2},
+		},
+		{
+			name:     "happy path: box drawing",
+			args:     &args{r: '─'},
+			expected: &expected{width: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := RuneWidth(tt.args.r)
+
+			// Assert
+			assert.Equal(t, tt.expected.width, actual)
+		})
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	type args struct {
+		s string
+	}
+
+	type expected struct {
+		width int
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name:     "happy path: ascii",
+			args:     &args{s: "hello"},
+			expected: &expected{width: 5},
+		},
+		{
+			name:     "happy path: mixed",
+			args:     &args{s: "aあé"},
+			expected: &expected{width: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			actual := StringWidth(tt.args.s)
+
+			// Assert
+			assert.Equal(t, tt.expected.width, actual)
+		})
+	}
+}
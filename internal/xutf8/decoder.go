@@ -0,0 +1,57 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xutf8
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Decoder converts a byte stream split at arbitrary points into valid UTF-8 strings,
+// holding back an incomplete trailing sequence until the next chunk arrives.
+type Decoder struct {
+	pending []byte
+}
+
+func (d *Decoder) Decode(p []byte) string {
+	buf := append(d.pending, p...)
+
+	n := len(buf)
+	for i := 1; i < utf8.UTFMax && i <= len(buf); i++ {
+		if utf8.RuneStart(buf[len(buf)-i]) {
+			if !utf8.FullRune(buf[len(buf)-i:]) {
+				n = len(buf) - i
+			}
+			break
+		}
+	}
+
+	d.pending = append([]byte(nil), buf[n:]...)
+
+	return strings.ToValidUTF8(string(buf[:n]), string(utf8.RuneError))
+}
+
+func (d *Decoder) Flush() string {
+	s := strings.ToValidUTF8(string(d.pending), string(utf8.RuneError))
+	d.pending = nil
+
+	return s
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023 Aton-Kish
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package xutf8
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder(t *testing.T) {
+	type args struct {
+		chunks [][]byte
+	}
+
+	type expected struct {
+		data  []string
+		flush string
+	}
+
+	tests := []struct {
+		name     string
+		args     *args
+		expected *expected
+	}{
+		{
+			name: "happy path: ascii",
+			args: &args{
+				chunks: [][]byte{[]byte("hello"), []byte(" world")},
+			},
+			expected: &expected{
+				data:  []string{"hello", " world"},
+				flush: "",
+			},
+		},
+		{
+			name: "happy path: split multibyte",
+			args: &args{
+				chunks: [][]byte{[]byte("a\xe3\x81"), []byte("\x82b"), []byte("\xf0\x9f"), []byte("\x98\x80")},
+			},
+			expected: &expected{
+				data:  []string{"a", "あb", "", "😀"},
+				flush: "",
+			},
+		},
+		{
+			name: "happy path: invalid bytes",
+			args: &args{
+				chunks: [][]byte{[]byte("a\xffb"), []byte("c\xe3")},
+			},
+			expected: &expected{
+				data:  []string{"a�b", "c"},
+				flush: "�",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			d := new(Decoder)
+
+			// Act
+			data := make([]string, 0, len(tt.args.chunks))
+			for _, c := range tt.args.chunks {
+				data = append(data, d.Decode(c))
+			}
+			flush := d.Flush()
+
+			// Assert
+			assert.Equal(t, tt.expected.data, data)
+			assert.Equal(t, tt.expected.flush, flush)
+		})
+	}
+}